@@ -8,6 +8,7 @@ import (
 	"syscall"
 	"time"
 
+	"curlex/internal/assertion"
 	"curlex/internal/config"
 	"curlex/internal/models"
 	"curlex/internal/output"
@@ -39,6 +40,7 @@ func main() {
 func run(cfg *config.Config) int {
 	// Create YAML parser
 	yamlParser := parser.NewYAMLParser()
+	yamlParser.SetStrict(cfg.Strict)
 
 	// Parse test suite
 	suite, err := yamlParser.Parse(cfg.TestFile)
@@ -46,6 +48,16 @@ func run(cfg *config.Config) int {
 		fmt.Fprintf(os.Stderr, "Failed to parse test file: %v\n", err)
 		return 1
 	}
+	for _, warning := range yamlParser.Warnings() {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+	}
+
+	// Obsolete snapshots are found before filtering so that snapshots of
+	// filtered-out tests still count as used
+	obsoleteSnapshots, err := assertion.ObsoleteSnapshots(suite.Path, suite)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to list snapshots: %v\n", err)
+	}
 
 	// Apply test filtering if configured
 	filterConfig := runner.FilterConfig{
@@ -63,12 +75,20 @@ func run(cfg *config.Config) int {
 
 	// Create runner
 	testRunner := runner.NewRunner(cfg.Timeout, cfg.LogDir)
+	if cfg.Proxy != "" {
+		testRunner.SetProxy(&models.ProxyConfig{URL: cfg.Proxy})
+	}
+	if len(cfg.Resolve) > 0 {
+		testRunner.SetResolve(cfg.Resolve)
+	}
+	testRunner.SetUpdateSnapshots(cfg.UpdateSnapshots)
 
 	// Create progress indicator for human/verbose output (not quiet, json, junit)
 	var progress *output.Progress
 	showProgress := (cfg.OutputFormat == "human" || cfg.OutputFormat == "" || cfg.Verbose) && !cfg.Quiet && cfg.OutputFormat != "json" && cfg.OutputFormat != "junit"
 	if showProgress {
-		progress = output.NewProgress(len(suite.Tests), cfg.NoColor)
+		total := len(suite.Setup) + len(suite.Tests) + len(suite.Teardown)
+		progress = output.NewProgress(total, cfg.NoColor)
 		testRunner.SetProgress(progress)
 		progress.Start()
 	}
@@ -77,6 +97,13 @@ func run(cfg *config.Config) int {
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer cancel()
 
+	// Once interrupted, restore default signal handling so a second Ctrl-C
+	// exits immediately instead of waiting for teardown to finish
+	go func() {
+		<-ctx.Done()
+		cancel()
+	}()
+
 	var suiteResult *models.SuiteResult
 	if cfg.Parallel {
 		suiteResult, err = testRunner.RunParallel(ctx, suite, cfg.Concurrency, cfg.FailFast)
@@ -99,6 +126,16 @@ func run(cfg *config.Config) int {
 		return 1
 	}
 
+	if suiteResult.Error != nil {
+		fmt.Fprintf(os.Stderr, "Suite error: %v\n", suiteResult.Error)
+	}
+
+	if cfg.CookieJar != "" {
+		if err := testRunner.SaveCookies(cfg.CookieJar); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}
+
 	// Handle output based on format
 	if cfg.Quiet || cfg.OutputFormat == "quiet" {
 		// Quiet mode - minimal output
@@ -114,6 +151,7 @@ func run(cfg *config.Config) int {
 			fmt.Print(junitFormatter.Format(suiteResult))
 		default: // "human" or verbose
 			var formatter interface {
+				FormatPhaseHeader(string) string
 				FormatResult(models.TestResult) string
 				FormatSummary([]models.TestResult, time.Duration) string
 			}
@@ -123,16 +161,63 @@ func run(cfg *config.Config) int {
 				formatter = output.NewHumanFormatter(cfg.NoColor)
 			}
 
-			// Output results
+			// Output results, with setup and teardown under their own headings
+			if len(suiteResult.SetupResults) > 0 {
+				fmt.Print(formatter.FormatPhaseHeader("Setup"))
+				for _, result := range suiteResult.SetupResults {
+					fmt.Print(formatter.FormatResult(result))
+				}
+				fmt.Print(formatter.FormatPhaseHeader("Tests"))
+			}
 			for _, result := range suiteResult.Results {
 				fmt.Print(formatter.FormatResult(result))
 			}
+			if len(suiteResult.TeardownResults) > 0 {
+				fmt.Print(formatter.FormatPhaseHeader("Teardown"))
+				for _, result := range suiteResult.TeardownResults {
+					fmt.Print(formatter.FormatResult(result))
+				}
+			}
 
 			// Output summary
 			fmt.Print(formatter.FormatSummary(suiteResult.Results, suiteResult.TotalTime))
 		}
 	}
 
+	// The coverage report goes to stderr when stdout carries machine-readable output
+	if cfg.Coverage {
+		if suiteResult.OpenAPICoverage == nil {
+			fmt.Fprintf(os.Stderr, "Warning: --openapi-coverage requires 'openapi' in the test file\n")
+		} else if cfg.OutputFormat == "json" || cfg.OutputFormat == "junit" {
+			fmt.Fprint(os.Stderr, output.NewCoverageFormatter(true).Format(suiteResult.OpenAPICoverage))
+		} else {
+			fmt.Fprint(os.Stdout, output.NewCoverageFormatter(cfg.NoColor).Format(suiteResult.OpenAPICoverage))
+		}
+	}
+
+	// Snapshots no test uses are removed by --update-snapshots and reported otherwise
+	if len(obsoleteSnapshots) > 0 {
+		if suiteResult.Snapshots == nil {
+			suiteResult.Snapshots = &models.SnapshotReport{}
+		}
+		suiteResult.Snapshots.Obsolete = obsoleteSnapshots
+		if cfg.UpdateSnapshots {
+			for _, path := range obsoleteSnapshots {
+				if err := os.Remove(path); err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+				}
+			}
+			suiteResult.Snapshots.Removed = true
+		}
+	}
+	if suiteResult.Snapshots != nil {
+		if cfg.OutputFormat == "json" || cfg.OutputFormat == "junit" {
+			fmt.Fprint(os.Stderr, output.NewSnapshotFormatter(true).Format(suiteResult.Snapshots))
+		} else {
+			fmt.Fprint(os.Stdout, output.NewSnapshotFormatter(cfg.NoColor).Format(suiteResult.Snapshots))
+		}
+	}
+
 	// Return exit code
 	if suiteResult.HasFailures() {
 		return 1
@@ -2,11 +2,18 @@ package assertion
 
 import (
 	"fmt"
+	"regexp"
+	"regexp/syntax"
 	"strings"
+	"unicode/utf8"
 
 	"curlex/internal/models"
 )
 
+// excerptWidth is the number of body characters shown around a match in
+// failure messages
+const excerptWidth = 94
+
 // BodyValidator validates response body assertions
 type BodyValidator struct{}
 
@@ -19,20 +26,18 @@ func (v *BodyValidator) Validate(result *models.TestResult, assertion models.Ass
 		return nil // Success
 	}
 
+	// Show the body around the first difference
+	diff := 0
+	for diff < len(actual) && diff < len(expected) && actual[diff] == expected[diff] {
+		diff++
+	}
+
 	return &models.AssertionFailure{
 		Type:     models.AssertionBody,
 		Expected: expected,
-		Actual:   v.truncate(actual, 100),
-		Message:  "body mismatch: expected exact match",
-	}
-}
-
-// truncate limits string length for display
-func (v *BodyValidator) truncate(s string, maxLen int) string {
-	if len(s) <= maxLen {
-		return s
+		Actual:   excerpt(actual, diff, diff),
+		Message:  fmt.Sprintf("body mismatch: expected exact match, first difference at offset %d", diff),
 	}
-	return s[:maxLen] + "..."
 }
 
 // BodyContainsValidator validates body contains assertions
@@ -50,15 +55,138 @@ func (v *BodyContainsValidator) Validate(result *models.TestResult, assertion mo
 	return &models.AssertionFailure{
 		Type:     models.AssertionBodyContains,
 		Expected: fmt.Sprintf("body to contain: %q", substring),
-		Actual:   v.truncate(actual, 100),
+		Actual:   closestSubstring(actual, substring),
 		Message:  fmt.Sprintf("body does not contain %q", substring),
 	}
 }
 
-// truncate limits string length for display
-func (v *BodyContainsValidator) truncate(s string, maxLen int) string {
-	if len(s) <= maxLen {
+// BodyNotContainsValidator validates that the body does not contain a substring
+type BodyNotContainsValidator struct{}
+
+// Validate checks that the response body does not contain the substring
+func (v *BodyNotContainsValidator) Validate(result *models.TestResult, assertion models.Assertion) *models.AssertionFailure {
+	substring := assertion.Value
+	actual := result.ResponseBody
+
+	idx := strings.Index(actual, substring)
+	if idx == -1 {
+		return nil // Success
+	}
+
+	return &models.AssertionFailure{
+		Type:     models.AssertionBodyNotContains,
+		Expected: fmt.Sprintf("body not to contain: %q", substring),
+		Actual:   excerpt(actual, idx, idx+len(substring)),
+		Message:  fmt.Sprintf("body contains %q at offset %d", substring, idx),
+	}
+}
+
+// BodyMatchesValidator validates the body against a regular expression
+type BodyMatchesValidator struct{}
+
+// Validate checks if the response body matches the regular expression
+// Flags such as (?s) and (?i) are written inline in the pattern
+func (v *BodyMatchesValidator) Validate(result *models.TestResult, assertion models.Assertion) *models.AssertionFailure {
+	re, err := regexp.Compile(assertion.Value)
+	if err != nil {
+		return &models.AssertionFailure{
+			Type:    models.AssertionBodyMatches,
+			Message: fmt.Sprintf("invalid expression: invalid regex: %v", err),
+		}
+	}
+
+	actual := result.ResponseBody
+	if re.MatchString(actual) {
+		return nil // Success
+	}
+
+	return &models.AssertionFailure{
+		Type:     models.AssertionBodyMatches,
+		Expected: fmt.Sprintf("body to match: %s", assertion.Value),
+		Actual:   closestMatch(actual, assertion.Value),
+		Message:  fmt.Sprintf("body does not match %s", assertion.Value),
+	}
+}
+
+// closestSubstring shows the region of body containing the longest prefix
+// of substring, or the start of the body when nothing useful matches
+func closestSubstring(body, substring string) string {
+	// A prefix that is present means every shorter prefix is too
+	lo, hi := 0, len(substring)
+	for lo < hi {
+		mid := (lo + hi + 1) / 2
+		if strings.Contains(body, substring[:mid]) {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+
+	// Very short prefixes match almost any body and only add noise
+	if lo < min(3, len(substring)) {
+		return excerpt(body, 0, 0)
+	}
+	idx := strings.Index(body, substring[:lo])
+	return excerpt(body, idx, idx+lo)
+}
+
+// closestMatch shows the region of body matched by the longest leading part
+// of pattern, or the start of the body when no part of it matches
+func closestMatch(body, pattern string) string {
+	parsed, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return excerpt(body, 0, 0)
+	}
+
+	// Look through capture groups wrapping the whole pattern
+	for parsed.Op == syntax.OpCapture && len(parsed.Sub) == 1 {
+		parsed = parsed.Sub[0]
+	}
+	if parsed.Op != syntax.OpConcat {
+		return excerpt(body, 0, 0)
+	}
+
+	// Drop trailing parts of the pattern until what remains matches
+	for n := len(parsed.Sub) - 1; n > 0; n-- {
+		prefix := &syntax.Regexp{Op: syntax.OpConcat, Flags: parsed.Flags, Sub: parsed.Sub[:n]}
+		re, err := regexp.Compile(prefix.String())
+		if err != nil {
+			continue
+		}
+		if loc := re.FindStringIndex(body); loc != nil && loc[1] > loc[0] {
+			return excerpt(body, loc[0], loc[1])
+		}
+	}
+
+	return excerpt(body, 0, 0)
+}
+
+// excerpt returns up to excerptWidth characters of s around s[start:end],
+// marking omitted text with "..."
+func excerpt(s string, start, end int) string {
+	if len(s) <= excerptWidth {
 		return s
 	}
-	return s[:maxLen] + "..."
+
+	// Center the window on the region, keeping it inside s
+	from := max(0, start-(excerptWidth-(end-start))/2)
+	to := min(len(s), from+excerptWidth)
+	from = max(0, to-excerptWidth)
+
+	// Avoid splitting multi-byte characters
+	for from > 0 && !utf8.RuneStart(s[from]) {
+		from--
+	}
+	for to < len(s) && !utf8.RuneStart(s[to]) {
+		to--
+	}
+
+	out := s[from:to]
+	if from > 0 {
+		out = "..." + out
+	}
+	if to < len(s) {
+		out += "..."
+	}
+	return out
 }
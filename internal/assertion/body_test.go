@@ -1,7 +1,9 @@
 package assertion
 
 import (
+	"strings"
 	"testing"
+	"unicode/utf8"
 
 	"curlex/internal/models"
 )
@@ -77,6 +79,24 @@ func TestBodyValidator_LongBodyTruncation(t *testing.T) {
 	}
 }
 
+func TestBodyValidator_ShowsFirstDifference(t *testing.T) {
+	validator := &BodyValidator{}
+
+	body := strings.Repeat("a", 150) + "XYZ" + strings.Repeat("b", 150)
+	expected := strings.Repeat("a", 150) + "abc" + strings.Repeat("b", 150)
+
+	failure := validator.Validate(&models.TestResult{ResponseBody: body}, models.Assertion{Type: models.AssertionBody, Value: expected})
+	if failure == nil {
+		t.Fatal("Expected failure")
+	}
+	if !strings.HasPrefix(failure.Actual, "...") || !strings.Contains(failure.Actual, "XYZ") {
+		t.Errorf("Actual = %q, want the region around the difference", failure.Actual)
+	}
+	if !strings.Contains(failure.Message, "offset 150") {
+		t.Errorf("Message = %q, want the offset of the difference", failure.Message)
+	}
+}
+
 func TestBodyContainsValidator_Success(t *testing.T) {
 	validator := &BodyContainsValidator{}
 
@@ -136,3 +156,129 @@ func TestBodyContainsValidator_EmptyBody(t *testing.T) {
 		t.Error("Expected failure for empty body")
 	}
 }
+
+func TestBodyMatchesValidator(t *testing.T) {
+	result := &models.TestResult{
+		ResponseBody: "<html>\n<title>Welcome Back</title>\n<p>Order #9876 shipped</p>\n</html>",
+	}
+
+	tests := []struct {
+		name       string
+		pattern    string
+		shouldPass bool
+	}{
+		{"simple", `Order #\d+`, true},
+		{"anchored fails without multiline", `^<p>Order`, false},
+		{"multiline flag", `(?m)^<p>Order`, true},
+		{"case insensitive flag", `(?i)welcome back`, true},
+		{"case sensitive fails", `welcome back`, false},
+		{"dot matches newline flag", `(?s)<title>.*shipped`, true},
+		{"dot stops at newline", `<title>.*shipped`, false},
+	}
+
+	validator := &BodyMatchesValidator{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failure := validator.Validate(result, models.Assertion{Type: models.AssertionBodyMatches, Value: tt.pattern})
+			if tt.shouldPass && failure != nil {
+				t.Errorf("Expected pass, got failure: %v", failure.Message)
+			}
+			if !tt.shouldPass && failure == nil {
+				t.Error("Expected failure, got pass")
+			}
+		})
+	}
+}
+
+func TestBodyMatchesValidator_InvalidRegex(t *testing.T) {
+	validator := &BodyMatchesValidator{}
+	failure := validator.Validate(&models.TestResult{ResponseBody: "ok"}, models.Assertion{Type: models.AssertionBodyMatches, Value: "(unclosed"})
+	if failure == nil {
+		t.Fatal("Expected failure for invalid regex")
+	}
+	if !strings.Contains(failure.Message, "invalid regex") {
+		t.Errorf("Message = %q, want invalid regex", failure.Message)
+	}
+}
+
+func TestBodyMatchesValidator_ShowsClosestRegion(t *testing.T) {
+	body := strings.Repeat("padding ", 50) + `<span class="total">Total: 42.00 EUR</span>` + strings.Repeat(" filler", 50)
+	validator := &BodyMatchesValidator{}
+
+	failure := validator.Validate(&models.TestResult{ResponseBody: body}, models.Assertion{
+		Type:  models.AssertionBodyMatches,
+		Value: `Total: \d+\.\d{2} USD`,
+	})
+	if failure == nil {
+		t.Fatal("Expected failure, got pass")
+	}
+	if !strings.Contains(failure.Actual, "Total: 42.00") {
+		t.Errorf("Actual = %q, want the region around the partial match", failure.Actual)
+	}
+	if !strings.HasPrefix(failure.Actual, "...") || !strings.HasSuffix(failure.Actual, "...") {
+		t.Errorf("Actual = %q, want omitted text marked with ...", failure.Actual)
+	}
+}
+
+func TestBodyNotContainsValidator(t *testing.T) {
+	body := strings.Repeat("a", 200) + "Traceback (most recent call last)" + strings.Repeat("b", 200)
+	validator := &BodyNotContainsValidator{}
+
+	if failure := validator.Validate(&models.TestResult{ResponseBody: body}, models.Assertion{Type: models.AssertionBodyNotContains, Value: "Exception"}); failure != nil {
+		t.Errorf("Expected pass, got failure: %v", failure.Message)
+	}
+
+	failure := validator.Validate(&models.TestResult{ResponseBody: body}, models.Assertion{Type: models.AssertionBodyNotContains, Value: "Traceback"})
+	if failure == nil {
+		t.Fatal("Expected failure, got pass")
+	}
+	if !strings.Contains(failure.Actual, "Traceback (most recent") {
+		t.Errorf("Actual = %q, want the region around the match", failure.Actual)
+	}
+	if !strings.Contains(failure.Message, "offset 200") {
+		t.Errorf("Message = %q, want the match offset", failure.Message)
+	}
+}
+
+func TestBodyContainsValidator_ShowsClosestRegion(t *testing.T) {
+	body := strings.Repeat("x", 300) + `"status":"pending"` + strings.Repeat("y", 300)
+	validator := &BodyContainsValidator{}
+
+	failure := validator.Validate(&models.TestResult{ResponseBody: body}, models.Assertion{Type: models.AssertionBodyContains, Value: `"status":"done"`})
+	if failure == nil {
+		t.Fatal("Expected failure, got pass")
+	}
+	if !strings.Contains(failure.Actual, `"status":"pending"`) {
+		t.Errorf("Actual = %q, want the region around the longest matching prefix", failure.Actual)
+	}
+}
+
+func TestExcerpt(t *testing.T) {
+	long := strings.Repeat("0123456789", 20)
+
+	tests := []struct {
+		name       string
+		s          string
+		start, end int
+		want       string
+	}{
+		{"short body unchanged", "short", 0, 0, "short"},
+		{"start of body", long, 0, 0, long[:excerptWidth] + "..."},
+		{"end of body", long, 200, 200, "..." + long[200-excerptWidth:]},
+		{"middle of body", long, 100, 110, "..." + long[58:152] + "..."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := excerpt(tt.s, tt.start, tt.end); got != tt.want {
+				t.Errorf("excerpt() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// Multi-byte characters are never split
+	multi := strings.Repeat("é", 100)
+	if got := excerpt(multi, 50, 52); !utf8.ValidString(got) {
+		t.Errorf("excerpt() split a multi-byte character: %q", got)
+	}
+}
@@ -0,0 +1,353 @@
+package assertion
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"curlex/internal/models"
+)
+
+// CookieValidator validates assertions on cookies set by the response
+type CookieValidator struct{}
+
+// cookieCondition is a single check on a cookie, e.g. "HttpOnly" or "Path == /"
+type cookieCondition struct {
+	attribute string    // Lower-cased attribute name, "value" for the cookie value
+	cond      condition // Comparison; the operator is empty for flags such as Secure
+	negate    bool      // Flag prefixed with "!"
+}
+
+// Validate checks if the response's Set-Cookie headers meet the assertion
+func (v *CookieValidator) Validate(result *models.TestResult, assertion models.Assertion) *models.AssertionFailure {
+	// Parse the assertion: "name [condition] [&& condition...]"
+	// Examples: "session HttpOnly && Secure", "session == abc", "!tracking"
+	expr := strings.TrimSpace(assertion.Value)
+
+	name, absent, conditions, err := v.parseExpression(expr)
+	if err != nil {
+		return &models.AssertionFailure{
+			Type:    models.AssertionCookie,
+			Message: fmt.Sprintf("invalid expression: %v", err),
+		}
+	}
+
+	// Every Set-Cookie header is considered, not just the first
+	var cookies []*http.Cookie
+	for _, cookie := range (&http.Response{Header: result.Headers}).Cookies() {
+		if cookie.Name == name {
+			cookies = append(cookies, cookie)
+		}
+	}
+
+	if absent {
+		if len(cookies) > 0 {
+			return &models.AssertionFailure{
+				Type:     models.AssertionCookie,
+				Expected: fmt.Sprintf("cookie %q not to be set", name),
+				Actual:   cookies[0].Raw,
+				Message:  fmt.Sprintf("cookie %q was set but should not be", name),
+			}
+		}
+		return nil
+	}
+
+	if len(cookies) == 0 {
+		return &models.AssertionFailure{
+			Type:     models.AssertionCookie,
+			Expected: fmt.Sprintf("cookie %q to be set", name),
+			Actual:   "cookie not found",
+			Message:  fmt.Sprintf("cookie %q not set in response", name),
+		}
+	}
+
+	// A cookie set more than once (e.g. for different paths) must satisfy
+	// the conditions every time
+	now := time.Now()
+	for _, cookie := range cookies {
+		for _, cond := range conditions {
+			ok, actual, err := v.evaluateCookie(cookie, cond, now)
+			if err != nil {
+				return &models.AssertionFailure{
+					Type:    models.AssertionCookie,
+					Message: fmt.Sprintf("invalid expression: %v", err),
+				}
+			}
+			if !ok {
+				return &models.AssertionFailure{
+					Type:     models.AssertionCookie,
+					Expected: fmt.Sprintf("%s %s", name, cond),
+					Actual:   cookie.Raw,
+					Message:  fmt.Sprintf("cookie %s: %s failed: got %s", name, cond, actual),
+				}
+			}
+		}
+	}
+
+	return nil // Success
+}
+
+// parseExpression parses a cookie assertion expression
+// Format: "[!]name [condition] [&& condition...]"
+// A condition starting with an operator compares the cookie value
+// Returns: name, whether the cookie must be absent, conditions, error
+func (v *CookieValidator) parseExpression(expr string) (string, bool, []cookieCondition, error) {
+	parts := splitConditions(expr)
+
+	first := strings.TrimSpace(parts[0])
+	name, rest, _ := strings.Cut(first, " ")
+	absent := strings.HasPrefix(name, "!")
+	name = strings.TrimPrefix(name, "!")
+	if name == "" {
+		return "", false, nil, fmt.Errorf("missing cookie name in expression: %s", expr)
+	}
+
+	rest = strings.TrimSpace(rest)
+	if absent {
+		if rest != "" || len(parts) > 1 {
+			return "", false, nil, fmt.Errorf("conditions cannot be combined with !%s", name)
+		}
+		return name, true, nil, nil
+	}
+
+	var conditions []cookieCondition
+	if rest != "" {
+		cond, err := v.parseCondition(rest)
+		if err != nil {
+			return "", false, nil, err
+		}
+		conditions = append(conditions, cond)
+	}
+	for _, part := range parts[1:] {
+		cond, err := v.parseCondition(strings.TrimSpace(part))
+		if err != nil {
+			return "", false, nil, err
+		}
+		conditions = append(conditions, cond)
+	}
+
+	return name, false, conditions, nil
+}
+
+// parseCondition parses a flag ("Secure", "!HttpOnly"), an attribute
+// comparison ("Path == /", "SameSite=Strict", "Max-Age > 1h") or a value
+// comparison ("== abc")
+func (v *CookieValidator) parseCondition(s string) (cookieCondition, error) {
+	if s == "" {
+		return cookieCondition{}, fmt.Errorf("empty condition")
+	}
+
+	// Conditions that start with an operator compare the value
+	attribute, rest := "value", s
+	if !startsWithOperator(s) {
+		if before, after, found := strings.Cut(s, "="); found && !strings.ContainsAny(before, " !<>") && !strings.HasPrefix(after, "=") {
+			// Set-Cookie style "SameSite=Strict"
+			attribute, rest = before, "== "+after
+		} else {
+			attribute, rest, _ = strings.Cut(s, " ")
+			rest = strings.TrimSpace(rest)
+		}
+	}
+
+	negate := strings.HasPrefix(attribute, "!")
+	attribute = strings.ToLower(strings.TrimPrefix(attribute, "!"))
+
+	switch attribute {
+	case "secure", "httponly", "partitioned", "session":
+		if rest != "" {
+			return cookieCondition{}, fmt.Errorf("%s is a flag and takes no value: %s", attribute, s)
+		}
+		return cookieCondition{attribute: attribute, negate: negate}, nil
+	case "value", "path", "domain", "samesite", "max-age", "expires":
+	default:
+		return cookieCondition{}, fmt.Errorf("unknown cookie attribute %q: expected value, Secure, HttpOnly, SameSite, Path, Domain, Max-Age, Expires, Partitioned or Session", attribute)
+	}
+	if negate {
+		return cookieCondition{}, fmt.Errorf("only flags can be negated: %s", s)
+	}
+
+	op, value, ok := cutOperator(rest)
+	if !ok {
+		return cookieCondition{}, fmt.Errorf("no valid operator found in condition: %s", s)
+	}
+	if value == "" && attribute != "value" {
+		return cookieCondition{}, fmt.Errorf("missing value in condition: %s", s)
+	}
+	cond, err := parseCondition(op, value)
+	if err != nil {
+		return cookieCondition{}, err
+	}
+
+	switch {
+	case op == "exists" || op == "not exists":
+		return cookieCondition{}, fmt.Errorf("%s is not supported for cookie attributes, use the cookie name or !name", op)
+	case attribute == "max-age" || attribute == "expires":
+		// Lifetimes are checked up front so typos aren't hidden by a missing attribute
+		if !isComparison(op) {
+			return cookieCondition{}, fmt.Errorf("%s does not support %s", attribute, op)
+		}
+		lifetime, err := v.parseLifetime(cond.expected)
+		if err != nil {
+			return cookieCondition{}, err
+		}
+		cond.expected = lifetime
+	case attribute == "domain" || attribute == "samesite":
+		// Compared case-insensitively; a leading dot on a domain is ignored, as browsers do
+		cond.expected = normalizeCookieText(cond.expected)
+	}
+
+	return cookieCondition{attribute: attribute, cond: cond}, nil
+}
+
+// splitConditions splits an expression at each "&&" outside quotes, so
+// values such as 'a&&b' stay intact
+func splitConditions(expr string) []string {
+	var parts []string
+	var quote byte
+	start := 0
+	for i := 0; i < len(expr); i++ {
+		switch c := expr[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '&' && strings.HasPrefix(expr[i:], "&&"):
+			parts = append(parts, expr[start:i])
+			start = i + 2
+			i++
+		}
+	}
+	return append(parts, expr[start:])
+}
+
+// normalizeCookieText lower-cases expected text, including the items of an
+// "in" list, and strips a leading dot
+func normalizeCookieText(expected any) any {
+	switch e := expected.(type) {
+	case string:
+		return strings.TrimPrefix(strings.ToLower(e), ".")
+	case []any:
+		items := make([]any, len(e))
+		for i, item := range e {
+			items[i] = normalizeCookieText(item)
+		}
+		return items
+	}
+	return expected
+}
+
+// startsWithOperator reports whether s begins with a comparison operator
+func startsWithOperator(s string) bool {
+	_, _, ok := cutOperator(s)
+	return ok
+}
+
+// evaluateCookie checks a single condition against a cookie
+// Returns whether it holds and the actual value for the failure message
+func (v *CookieValidator) evaluateCookie(cookie *http.Cookie, cond cookieCondition, now time.Time) (bool, string, error) {
+	switch cond.attribute {
+	case "secure":
+		return cookie.Secure != cond.negate, flagString(cookie.Secure), nil
+	case "httponly":
+		return cookie.HttpOnly != cond.negate, flagString(cookie.HttpOnly), nil
+	case "partitioned":
+		return cookie.Partitioned != cond.negate, flagString(cookie.Partitioned), nil
+	case "session":
+		session := cookie.MaxAge == 0 && cookie.Expires.IsZero()
+		return session != cond.negate, flagString(session), nil
+	case "value":
+		ok, err := cond.cond.evaluate(cookie.Value)
+		return ok, cookie.Value, err
+	case "path":
+		ok, err := cond.cond.evaluate(cookie.Path)
+		return ok, cookie.Path, err
+	case "domain":
+		// A leading dot is ignored, as browsers do
+		actual := strings.TrimPrefix(strings.ToLower(cookie.Domain), ".")
+		ok, err := cond.cond.evaluate(actual)
+		return ok, actual, err
+	case "samesite":
+		actual := sameSiteName(cookie.SameSite)
+		ok, err := cond.cond.evaluate(strings.ToLower(actual))
+		if actual == "" {
+			actual = "not set"
+		}
+		return ok, actual, err
+	case "max-age":
+		if cookie.MaxAge == 0 {
+			return false, "no Max-Age", nil
+		}
+		// Go reports Max-Age=0 and negative values as -1; both expire immediately
+		maxAge := time.Duration(max(cookie.MaxAge, 0)) * time.Second
+		ok, err := cond.cond.evaluate(maxAge)
+		return ok, strconv.Itoa(max(cookie.MaxAge, 0)), err
+	case "expires":
+		// Max-Age takes precedence over Expires, as in browsers
+		var lifetime time.Duration
+		switch {
+		case cookie.MaxAge != 0:
+			lifetime = time.Duration(max(cookie.MaxAge, 0)) * time.Second
+		case !cookie.Expires.IsZero():
+			lifetime = cookie.Expires.Sub(now)
+		default:
+			return false, "session cookie", nil
+		}
+		ok, err := cond.cond.evaluate(lifetime)
+		return ok, lifetime.Round(time.Second).String(), err
+	}
+	return false, "", fmt.Errorf("unknown cookie attribute %q", cond.attribute)
+}
+
+// parseLifetime converts an expected lifetime, a number of seconds or a
+// duration such as "1h" or "30d", to a duration
+func (v *CookieValidator) parseLifetime(expected any) (time.Duration, error) {
+	switch e := expected.(type) {
+	case float64:
+		return time.Duration(e * float64(time.Second)), nil
+	case time.Duration:
+		return e, nil
+	}
+	s := describeValue(expected)
+	duration, err := parseLongDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q: expected seconds or e.g. 1h, 30d", s)
+	}
+	return duration, nil
+}
+
+// sameSiteName returns the SameSite attribute as written in Set-Cookie
+func sameSiteName(mode http.SameSite) string {
+	switch mode {
+	case http.SameSiteLaxMode:
+		return "Lax"
+	case http.SameSiteStrictMode:
+		return "Strict"
+	case http.SameSiteNoneMode:
+		return "None"
+	default:
+		return ""
+	}
+}
+
+// flagString describes whether a cookie flag is set
+func flagString(set bool) string {
+	if set {
+		return "set"
+	}
+	return "not set"
+}
+
+// String renders a condition as it would be written in an assertion
+func (c cookieCondition) String() string {
+	if c.cond.operator == "" {
+		if c.negate {
+			return "!" + c.attribute
+		}
+		return c.attribute
+	}
+	return fmt.Sprintf("%s %s", c.attribute, c.cond)
+}
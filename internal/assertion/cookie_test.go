@@ -0,0 +1,127 @@
+package assertion
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"curlex/internal/models"
+)
+
+func TestCookieValidator_Validate(t *testing.T) {
+	expires := time.Now().Add(48 * time.Hour).UTC().Format(http.TimeFormat)
+	headers := http.Header{}
+	headers.Add("Set-Cookie", "session=abc123; Path=/; Domain=.example.com; Secure; HttpOnly; SameSite=Strict; Max-Age=3600")
+	headers.Add("Set-Cookie", "theme=dark; Path=/app; Expires="+expires)
+	headers.Add("Set-Cookie", "tracking=1")
+	headers.Add("Set-Cookie", "lang=en; Path=/; Secure")
+	headers.Add("Set-Cookie", "lang=fr; Path=/fr")
+	headers.Add("Set-Cookie", "token=a&&b; Path=/")
+	result := &models.TestResult{Headers: headers}
+
+	tests := []struct {
+		name       string
+		value      string
+		shouldFail bool
+	}{
+		{"exists", "session", false},
+		{"missing fails", "missing", true},
+		{"absent", "!missing", false},
+		{"absent but set fails", "!tracking", true},
+		{"flags", "session HttpOnly && Secure", false},
+		{"flag not set fails", "theme Secure", true},
+		{"negated flag", "theme !HttpOnly", false},
+		{"value equals", "session == abc123", false},
+		{"value mismatch fails", "session == other", true},
+		{"value not equal", "session != other", false},
+		{"value contains", "session contains 123", false},
+		{"value with attributes", "session == abc123 && SameSite=Strict && Path == /", false},
+		{"quoted value containing &&", "token == 'a&&b' && Path == /", false},
+		{"value matches", "session matches /^[a-z]+\\d+$/", false},
+		{"value in list", "theme in [dark, light]", false},
+		{"samesite in list", "session SameSite in [Lax, Strict]", false},
+		{"domain in list fails", "session Domain in [other.com]", true},
+		{"quoted value containing && fails", "token == 'a&&c'", true},
+		{"samesite case insensitive", "session SameSite == strict", false},
+		{"samesite missing fails", "theme SameSite == Lax", true},
+		{"domain ignores leading dot", "session Domain == example.com", false},
+		{"path", "theme Path == /app", false},
+		{"max-age seconds", "session Max-Age >= 3600", false},
+		{"max-age duration", "session Max-Age < 2h", false},
+		{"max-age absent fails", "theme Max-Age > 0", true},
+		{"expires from Expires", "theme Expires > 1d", false},
+		{"expires from Max-Age", "session Expires <= 1h", false},
+		{"expires on session cookie fails", "tracking Expires > 1s", true},
+		{"session cookie", "tracking Session", false},
+		{"persistent cookie is not session", "theme Session", true},
+		{"every occurrence must match", "lang Secure", true},
+		{"every occurrence matches", "lang Path contains / && !HttpOnly", false},
+	}
+
+	validator := &CookieValidator{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failure := validator.Validate(result, models.Assertion{Type: models.AssertionCookie, Value: tt.value})
+			if tt.shouldFail && failure == nil {
+				t.Error("Expected failure, got success")
+			}
+			if !tt.shouldFail && failure != nil {
+				t.Errorf("Expected success, got failure: %s", failure.Message)
+			}
+			if failure != nil && strings.HasPrefix(failure.Message, "invalid expression") {
+				t.Errorf("Unexpected parse error: %s", failure.Message)
+			}
+		})
+	}
+}
+
+func TestCookieValidator_FailureMessage(t *testing.T) {
+	headers := http.Header{}
+	headers.Add("Set-Cookie", "session=abc; Path=/")
+	result := &models.TestResult{Headers: headers}
+
+	validator := &CookieValidator{}
+	failure := validator.Validate(result, models.Assertion{Type: models.AssertionCookie, Value: "session HttpOnly"})
+	if failure == nil {
+		t.Fatal("Expected failure, got success")
+	}
+	if failure.Actual != "session=abc; Path=/" {
+		t.Errorf("Actual = %q, want the raw Set-Cookie header", failure.Actual)
+	}
+	if !strings.Contains(failure.Message, "httponly failed: got not set") {
+		t.Errorf("Message = %q", failure.Message)
+	}
+}
+
+func TestCookieValidator_InvalidExpression(t *testing.T) {
+	result := &models.TestResult{Headers: http.Header{"Set-Cookie": {"session=abc"}}}
+
+	tests := []struct {
+		name    string
+		value   string
+		wantErr string
+	}{
+		{"missing name", "", "missing cookie name"},
+		{"unknown attribute", "session Colour == red", "unknown cookie attribute"},
+		{"flag with value", "session Secure == true", "takes no value"},
+		{"negated comparison", "session !Path == /", "only flags can be negated"},
+		{"absent with conditions", "!session Secure", "cannot be combined"},
+		{"missing operator", "session Path /", "no valid operator"},
+		{"bad duration", "session Max-Age > soon", "invalid duration"},
+		{"lifetime contains", "session Expires contains 1d", "does not support contains"},
+	}
+
+	validator := &CookieValidator{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failure := validator.Validate(result, models.Assertion{Type: models.AssertionCookie, Value: tt.value})
+			if failure == nil {
+				t.Fatal("Expected failure, got success")
+			}
+			if !strings.Contains(failure.Message, tt.wantErr) {
+				t.Errorf("Message = %q, want it to contain %q", failure.Message, tt.wantErr)
+			}
+		})
+	}
+}
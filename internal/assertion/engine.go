@@ -14,20 +14,45 @@ type Validator interface {
 	Validate(result *models.TestResult, assertion models.Assertion) *models.AssertionFailure
 }
 
+// MultiValidator is implemented by validators that can report several
+// failures for a single assertion, e.g. every schema violation
+type MultiValidator interface {
+	ValidateAll(result *models.TestResult, assertion models.Assertion) []models.AssertionFailure
+}
+
 // NewEngine creates a new assertion engine with all validators
 func NewEngine() *Engine {
 	return &Engine{
 		validators: map[models.AssertionType]Validator{
-			models.AssertionStatus:       &StatusValidator{},
-			models.AssertionBody:         &BodyValidator{},
-			models.AssertionBodyContains: &BodyContainsValidator{},
-			models.AssertionJSONPath:     &JSONPathValidator{},
-			models.AssertionHeader:       &HeaderValidator{},
-			models.AssertionResponseTime: &ResponseTimeValidator{},
+			models.AssertionStatus:          &StatusValidator{},
+			models.AssertionBody:            &BodyValidator{},
+			models.AssertionBodyContains:    &BodyContainsValidator{},
+			models.AssertionBodyNotContains: &BodyNotContainsValidator{},
+			models.AssertionBodyMatches:     &BodyMatchesValidator{},
+			models.AssertionJSONPath:        &JSONPathValidator{},
+			models.AssertionJSONSchema:      &JSONSchemaValidator{},
+			models.AssertionJSONBody:        &JSONBodyValidator{},
+			models.AssertionXPath:           &XPathValidator{},
+			models.AssertionSnapshot:        NewSnapshotStore("", false),
+			models.AssertionHeader:          &HeaderValidator{},
+			models.AssertionResponseTime:    &ResponseTimeValidator{},
+			models.AssertionTLS:             &TLSValidator{},
+			models.AssertionCookie:          &CookieValidator{},
+			models.AssertionExpr:            &ExprValidator{},
+			models.AssertionDNSLookup:       &TimingPhaseValidator{},
+			models.AssertionTCPConnect:      &TimingPhaseValidator{},
+			models.AssertionTLSHandshake:    &TimingPhaseValidator{},
+			models.AssertionTTFB:            &TimingPhaseValidator{},
+			models.AssertionContentTransfer: &TimingPhaseValidator{},
 		},
 	}
 }
 
+// SetSnapshots replaces the store that snapshot assertions are checked against
+func (e *Engine) SetSnapshots(store *SnapshotStore) {
+	e.validators[models.AssertionSnapshot] = store
+}
+
 // Validate checks all assertions against the result
 func (e *Engine) Validate(result *models.TestResult, assertions []models.Assertion) []models.AssertionFailure {
 	var failures []models.AssertionFailure
@@ -42,6 +67,11 @@ func (e *Engine) Validate(result *models.TestResult, assertions []models.Asserti
 			continue
 		}
 
+		if multi, ok := validator.(MultiValidator); ok {
+			failures = append(failures, multi.ValidateAll(result, assertion)...)
+			continue
+		}
+
 		if failure := validator.Validate(result, assertion); failure != nil {
 			failures = append(failures, *failure)
 		}
@@ -166,6 +166,28 @@ func TestEngine_MixedFailures(t *testing.T) {
 	}
 }
 
+func TestEngine_JSONSchemaReportsEveryViolation(t *testing.T) {
+	engine := NewEngine()
+
+	result := &models.TestResult{
+		ResponseBody: `{"id": "7", "email": 42}`,
+	}
+
+	assertions := []models.Assertion{
+		{Type: models.AssertionJSONSchema, Value: `{"properties": {"id": {"type": "integer"}, "email": {"type": "string"}}}`},
+	}
+
+	failures := engine.Validate(result, assertions)
+	if len(failures) != 2 {
+		t.Fatalf("Expected 2 failures, got %d: %v", len(failures), failures)
+	}
+	for _, failure := range failures {
+		if failure.Type != models.AssertionJSONSchema {
+			t.Errorf("Type = %s, want %s", failure.Type, models.AssertionJSONSchema)
+		}
+	}
+}
+
 func TestEngine_UnknownAssertionType(t *testing.T) {
 	engine := NewEngine()
 
@@ -0,0 +1,113 @@
+package assertion
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"curlex/internal/models"
+)
+
+// exprNames lists the names available to expr assertions
+var exprNames = map[string]bool{
+	"status":   true,
+	"headers":  true,
+	"body":     true,
+	"json":     true,
+	"duration": true,
+	"vars":     true,
+}
+
+// ExprValidator validates boolean expressions over the whole response
+type ExprValidator struct{}
+
+// Validate checks if the expression evaluates to true
+func (v *ExprValidator) Validate(result *models.TestResult, assertion models.Assertion) *models.AssertionFailure {
+	// Examples: "status == 200 && json.total == len(json.items)",
+	// "headers['Content-Type'] contains 'json' || status == 204"
+	expr := strings.TrimSpace(assertion.Value)
+
+	node, err := parseExpression(expr, exprNames, "")
+	if err != nil {
+		return &models.AssertionFailure{
+			Type:    models.AssertionExpr,
+			Message: fmt.Sprintf("invalid expression: %v", err),
+		}
+	}
+
+	evaluator := &exprEvaluator{scope: &resultScope{result: result}}
+	value, err := evaluator.evaluate(node)
+	if err != nil {
+		return &models.AssertionFailure{
+			Type:     models.AssertionExpr,
+			Expected: expr,
+			Actual:   "error",
+			Message:  fmt.Sprintf("%s failed: %v", expr, err),
+		}
+	}
+
+	ok, isBool := value.(bool)
+	if !isBool {
+		return &models.AssertionFailure{
+			Type:     models.AssertionExpr,
+			Expected: expr,
+			Actual:   formatValue(value),
+			Message:  fmt.Sprintf("%s must be true or false, got %s %s", expr, typeName(value), formatValue(value)),
+		}
+	}
+
+	if !ok {
+		// The failed comparisons show the values that were compared
+		actual := "false"
+		if len(evaluator.trace) > 0 {
+			actual = strings.Join(evaluator.trace, ", ")
+		}
+		return &models.AssertionFailure{
+			Type:     models.AssertionExpr,
+			Expected: expr,
+			Actual:   actual,
+			Message:  fmt.Sprintf("%s failed: got %s", expr, actual),
+		}
+	}
+
+	return nil // Success
+}
+
+// resultScope resolves expression names from a test result
+// The body is parsed as JSON only when json is used
+type resultScope struct {
+	result *models.TestResult
+
+	json    any
+	jsonErr error
+	parsed  bool
+}
+
+// lookup returns the value of a name
+func (s *resultScope) lookup(name string) (any, error) {
+	switch name {
+	case "status":
+		return float64(s.result.StatusCode), nil
+	case "headers":
+		return exprHeaders(s.result.Headers), nil
+	case "body":
+		return s.result.ResponseBody, nil
+	case "duration":
+		return s.result.ResponseTime, nil
+	case "vars":
+		vars := make(map[string]any, len(s.result.Variables))
+		for key, value := range s.result.Variables {
+			vars[key] = value
+		}
+		return vars, nil
+	case "json":
+		if !s.parsed {
+			s.parsed = true
+			if err := json.Unmarshal([]byte(s.result.ResponseBody), &s.json); err != nil {
+				s.jsonErr = fmt.Errorf("response body is not valid JSON: %w", err)
+			}
+		}
+		return s.json, s.jsonErr
+	}
+	return nil, fmt.Errorf("unknown name %q", name)
+}
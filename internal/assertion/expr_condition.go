@@ -0,0 +1,285 @@
+package assertion
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// conditionOperators lists the operators of single-subject conditions such as
+// "Content-Type contains json" or ".id == 1"; word operators are matched as
+// whole words, symbols longest first
+var conditionOperators = []string{"not exists", "not contains", "exists", "contains", "matches", "in", "is", "==", "!=", ">=", "<=", ">", "<"}
+
+// valueTypes lists the type names accepted by "is"
+var valueTypes = []string{"string", "number", "boolean", "array", "object", "null"}
+
+// condition compares a value with an expected value, e.g. "== 'json'",
+// "in [200, 201]" or "matches /^v\d+/"
+// Expected values are literals of the expression language or JSON documents;
+// anything else is an unquoted string, so "== application/json" works
+type condition struct {
+	operator string
+	expected any    // Parsed value; []any for in, *regexp.Regexp for matches, a type name for is
+	text     string // Value as written
+}
+
+// cutOperator returns the condition operator s starts with and the text after it
+func cutOperator(s string) (op, rest string, ok bool) {
+	for _, op := range conditionOperators {
+		after, found := strings.CutPrefix(s, op)
+		if !found {
+			continue
+		}
+		// Word operators must not run into the value, e.g. "containsx"
+		if isWordOperator(op) && after != "" && after[0] != ' ' {
+			continue
+		}
+		return op, strings.TrimSpace(after), true
+	}
+	return "", "", false
+}
+
+// isWordOperator reports whether op is spelled with letters
+func isWordOperator(op string) bool {
+	return op[0] >= 'a' && op[0] <= 'z'
+}
+
+// isComparison reports whether op is one of the comparison operators
+func isComparison(op string) bool {
+	switch op {
+	case "==", "!=", ">", "<", ">=", "<=":
+		return true
+	}
+	return false
+}
+
+// parseCondition parses the value of a condition for operator
+func parseCondition(operator, value string) (condition, error) {
+	c := condition{operator: operator, text: value}
+
+	switch operator {
+	case "exists", "not exists":
+		if value != "" {
+			return c, fmt.Errorf("%s takes no value", operator)
+		}
+	case "is":
+		if !slices.Contains(valueTypes, value) {
+			return c, fmt.Errorf("unknown type %q: expected %s", value, strings.Join(valueTypes, ", "))
+		}
+		c.expected = value
+	case "matches":
+		re, err := parseRegex(value)
+		if err != nil {
+			return c, err
+		}
+		c.expected = re
+	case "in":
+		items, err := parseList(value)
+		if err != nil {
+			return c, err
+		}
+		list := make([]any, len(items))
+		for i, item := range items {
+			list[i] = parseLiteral(item)
+		}
+		c.expected = list
+	default:
+		c.expected = parseLiteral(value)
+	}
+
+	return c, nil
+}
+
+// parseRegex parses the pattern of matches: a /regex/ literal, a quoted
+// string or a bare pattern
+func parseRegex(s string) (*regexp.Regexp, error) {
+	if strings.HasPrefix(s, "/") {
+		if tok, err := lexRegex(s, 0); err == nil && tok.text == s {
+			return tok.value.(*regexp.Regexp), nil
+		}
+	}
+	pattern := s
+	if str, ok := parseLiteral(s).(string); ok {
+		pattern = str
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regex %q: %w", pattern, err)
+	}
+	return re, nil
+}
+
+// parseLiteral parses a literal of the expression language (number,
+// duration, string, boolean, null or list) or a JSON document; anything
+// else is returned as an unquoted string
+func parseLiteral(s string) any {
+	if node, err := parseExpression(s, nil, ""); err == nil && isLiteral(node) {
+		if value, err := (&exprEvaluator{}).evaluate(node); err == nil {
+			return value
+		}
+	}
+	var value any
+	if err := json.Unmarshal([]byte(s), &value); err == nil {
+		return value
+	}
+	return s
+}
+
+// isLiteral reports whether node is a constant, e.g. -1 or ["a", 2]
+func isLiteral(node exprNode) bool {
+	switch n := node.(type) {
+	case literalNode:
+		return true
+	case unaryNode:
+		return n.op == "-" && isLiteral(n.operand)
+	case listNode:
+		for _, item := range n.items {
+			if !isLiteral(item) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// parseList splits the list for "in", e.g. "[a, 'b, c', 3]", into items
+func parseList(s string) ([]string, error) {
+	if len(s) < 2 || s[0] != '[' || s[len(s)-1] != ']' {
+		return nil, fmt.Errorf("in expects a list, e.g. in [a, b, c]")
+	}
+
+	var items []string
+	var item strings.Builder
+	var quote byte
+	for _, c := range []byte(s[1 : len(s)-1]) {
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+			item.WriteByte(c)
+		case c == '"' || c == '\'':
+			quote = c
+			item.WriteByte(c)
+		case c == ',':
+			items = append(items, strings.TrimSpace(item.String()))
+			item.Reset()
+		default:
+			item.WriteByte(c)
+		}
+	}
+	if last := strings.TrimSpace(item.String()); last != "" || len(items) > 0 {
+		items = append(items, last)
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("in expects at least one item")
+	}
+	return items, nil
+}
+
+// evaluate reports whether actual satisfies the condition
+// Presence operators are left to the caller, which knows whether the value exists
+func (c condition) evaluate(actual any) (bool, error) {
+	switch c.operator {
+	case "is":
+		return typeName(actual) == c.expected, nil
+	case "matches":
+		s, ok := scalarText(actual)
+		if !ok {
+			return false, fmt.Errorf("matches is not defined for %s", typeName(actual))
+		}
+		return c.expected.(*regexp.Regexp).MatchString(s), nil
+	case "in":
+		for _, item := range c.expected.([]any) {
+			if equalCoerced(actual, item) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case "contains", "not contains":
+		found, err := containsCoerced(actual, c.expected)
+		if err != nil {
+			return false, err
+		}
+		return found == (c.operator == "contains"), nil
+	}
+
+	left, right := coerce(actual, c.expected)
+	return compare(c.operator, left, right)
+}
+
+// String renders the condition as written
+func (c condition) String() string {
+	return strings.TrimSpace(c.operator + " " + c.text)
+}
+
+// coerce prepares a value and an expected value for comparison
+// Text such as a header value or XML node compares as a number with a
+// number when it is one, and as text otherwise
+func coerce(actual, expected any) (any, any) {
+	s, isString := actual.(string)
+	if !isString {
+		return actual, expected
+	}
+	switch e := expected.(type) {
+	case float64:
+		if n, err := strconv.ParseFloat(strings.TrimSpace(s), 64); err == nil {
+			return n, e
+		}
+		return s, formatValue(e)
+	case bool, time.Duration:
+		return s, formatValue(e)
+	}
+	return actual, expected
+}
+
+// equalCoerced compares values after coercion
+func equalCoerced(actual, expected any) bool {
+	left, right := coerce(actual, expected)
+	return equalValues(left, right)
+}
+
+// containsCoerced reports whether a string contains the expected text, an
+// array contains an equal element or an object has the expected key
+func containsCoerced(actual, expected any) (bool, error) {
+	switch a := actual.(type) {
+	case []any:
+		for _, element := range a {
+			if equalCoerced(element, expected) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case string, map[string]any:
+		if _, ok := expected.(string); !ok {
+			expected = formatValue(expected)
+		}
+		return containsValue(a, expected)
+	}
+	return false, fmt.Errorf("contains is not defined for %s", typeName(actual))
+}
+
+// scalarText returns strings, numbers and booleans as text
+func scalarText(value any) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case float64, bool:
+		return formatValue(v), true
+	}
+	return "", false
+}
+
+// describeValue renders a value for failure messages; strings are unquoted
+func describeValue(value any) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	return formatValue(value)
+}
@@ -0,0 +1,112 @@
+package assertion
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCondition_Evaluate(t *testing.T) {
+	tests := []struct {
+		name   string
+		expr   string
+		actual any
+		want   bool
+	}{
+		{name: "quoted string", expr: "== 'json'", actual: "json", want: true},
+		{name: "bare string", expr: "== application/json", actual: "application/json", want: true},
+		{name: "apostrophe in bare string", expr: "contains Let's Encrypt", actual: "Let's Encrypt R3", want: true},
+		{name: "number text", expr: ">= 2", actual: "10", want: true},
+		{name: "number text mismatch", expr: "== 1", actual: "1.5", want: false},
+		{name: "non-numeric text", expr: "== 2", actual: "two", want: false},
+		{name: "number", expr: "!= 1", actual: float64(2), want: true},
+		{name: "boolean text", expr: "== true", actual: "true", want: true},
+		{name: "duration", expr: "< 48h", actual: 24 * time.Hour, want: true},
+		{name: "contains", expr: "contains json", actual: "application/json", want: true},
+		{name: "not contains", expr: "not contains xml", actual: "application/json", want: true},
+		{name: "array contains", expr: "contains 2", actual: []any{float64(1), float64(2)}, want: true},
+		{name: "object key", expr: "contains id", actual: map[string]any{"id": float64(1)}, want: true},
+		{name: "regex literal", expr: `matches /^v\d+$/`, actual: "v12", want: true},
+		{name: "bare regex", expr: `matches ^v\d+$`, actual: "12", want: false},
+		{name: "regex on number", expr: `matches ^\d+$`, actual: float64(12), want: true},
+		{name: "in", expr: "in [200, '201', ok]", actual: "201", want: true},
+		{name: "in with numbers", expr: "in [1, 2]", actual: "2", want: true},
+		{name: "not in", expr: "in [a, b]", actual: "c", want: false},
+		{name: "is", expr: "is array", actual: []any{}, want: true},
+		{name: "JSON document", expr: `== {"a": [1, 2]}`, actual: map[string]any{"a": []any{float64(1), float64(2)}}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			op, value, ok := cutOperator(tt.expr)
+			if !ok {
+				t.Fatalf("cutOperator(%q) found no operator", tt.expr)
+			}
+			cond, err := parseCondition(op, value)
+			if err != nil {
+				t.Fatalf("parseCondition() error = %v", err)
+			}
+			got, err := cond.evaluate(tt.actual)
+			if err != nil {
+				t.Fatalf("evaluate() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("evaluate(%v) = %v, want %v", tt.actual, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCondition_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		actual  any
+		wantErr string
+	}{
+		{name: "unknown type", expr: "is text", wantErr: "unknown type"},
+		{name: "exists with value", expr: "exists x", wantErr: "takes no value"},
+		{name: "invalid regex", expr: "matches (", wantErr: "invalid regex"},
+		{name: "list expected", expr: "in a, b", wantErr: "expects a list"},
+		{name: "contains on number", expr: "contains 1", actual: float64(1), wantErr: "contains is not defined for number"},
+		{name: "matches on object", expr: "matches x", actual: map[string]any{}, wantErr: "matches is not defined for object"},
+		{name: "ordering booleans", expr: "> 1", actual: true, wantErr: "> is not defined for boolean"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			op, value, _ := cutOperator(tt.expr)
+			cond, err := parseCondition(op, value)
+			if err == nil {
+				_, err = cond.evaluate(tt.actual)
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCutOperator_WholeWords(t *testing.T) {
+	tests := []struct {
+		expr   string
+		wantOp string
+		wantOK bool
+	}{
+		{"contains x", "contains", true},
+		{"containsx", "", false},
+		{"is string", "is", true},
+		{"island", "", false},
+		{"not exists", "not exists", true},
+		{">=5", ">=", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			op, _, ok := cutOperator(tt.expr)
+			if op != tt.wantOp || ok != tt.wantOK {
+				t.Errorf("cutOperator(%q) = %q, %v, want %q, %v", tt.expr, op, ok, tt.wantOp, tt.wantOK)
+			}
+		})
+	}
+}
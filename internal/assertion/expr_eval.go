@@ -0,0 +1,529 @@
+package assertion
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Expression values are nil, bool, float64, string, time.Duration,
+// *regexp.Regexp, []any, map[string]any and exprHeaders
+
+// exprHeaders is the headers object; lookups ignore case and repeated
+// headers are joined with ", "
+type exprHeaders http.Header
+
+// exprScope resolves the names an expression refers to
+type exprScope interface {
+	lookup(name string) (any, error)
+}
+
+// exprEvaluator evaluates an AST, recording the operands of each comparison
+// so a false result can be explained
+type exprEvaluator struct {
+	scope exprScope
+	trace []string
+}
+
+// exprFunction is a built-in function
+type exprFunction struct {
+	arity int
+	call  func(args []any) (any, error)
+}
+
+// exprFunctions lists the built-in functions by name
+var exprFunctions = map[string]exprFunction{
+	"len":         {1, exprLen},
+	"lower":       {1, stringFunction("lower", strings.ToLower)},
+	"upper":       {1, stringFunction("upper", strings.ToUpper)},
+	"trim":        {1, stringFunction("trim", strings.TrimSpace)},
+	"starts_with": {2, stringPredicate("starts_with", strings.HasPrefix)},
+	"ends_with":   {2, stringPredicate("ends_with", strings.HasSuffix)},
+	"number":      {1, exprNumber},
+	"string":      {1, exprString},
+	"type":        {1, func(args []any) (any, error) { return typeName(args[0]), nil }},
+}
+
+// evaluate returns the value of node
+func (e *exprEvaluator) evaluate(node exprNode) (any, error) {
+	switch n := node.(type) {
+	case literalNode:
+		return n.value, nil
+	case identNode:
+		return e.scope.lookup(n.name)
+	case memberNode:
+		object, err := e.evaluate(n.object)
+		if err != nil {
+			return nil, err
+		}
+		return property(object, n.name, n)
+	case indexNode:
+		return e.evaluateIndex(n)
+	case callNode:
+		args := make([]any, len(n.args))
+		for i, arg := range n.args {
+			value, err := e.evaluate(arg)
+			if err != nil {
+				return nil, err
+			}
+			args[i] = value
+		}
+		return exprFunctions[n.name].call(args)
+	case listNode:
+		items := make([]any, len(n.items))
+		for i, item := range n.items {
+			value, err := e.evaluate(item)
+			if err != nil {
+				return nil, err
+			}
+			items[i] = value
+		}
+		return items, nil
+	case unaryNode:
+		return e.evaluateUnary(n)
+	case binaryNode:
+		switch n.op {
+		case "&&", "||":
+			return e.evaluateLogical(n)
+		}
+		return e.evaluateBinary(n)
+	}
+	return nil, fmt.Errorf("unsupported expression %s", node)
+}
+
+// evaluateIndex evaluates array[number] and object[string]
+func (e *exprEvaluator) evaluateIndex(n indexNode) (any, error) {
+	object, err := e.evaluate(n.object)
+	if err != nil {
+		return nil, err
+	}
+	index, err := e.evaluate(n.index)
+	if err != nil {
+		return nil, err
+	}
+
+	switch o := object.(type) {
+	case nil:
+		return nil, nil
+	case []any:
+		i, ok := index.(float64)
+		if !ok || i != math.Trunc(i) {
+			return nil, fmt.Errorf("%s: array index must be a whole number, got %s", n, typeName(index))
+		}
+		if i < 0 || int(i) >= len(o) {
+			return nil, nil
+		}
+		return o[int(i)], nil
+	case map[string]any, exprHeaders:
+		key, ok := index.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s: object key must be a string, got %s", n, typeName(index))
+		}
+		return property(o, key, n)
+	}
+	return nil, fmt.Errorf("%s: cannot index %s", n, typeName(object))
+}
+
+// property returns a property of an object; missing properties and
+// properties of null are null
+func property(object any, name string, node exprNode) (any, error) {
+	switch o := object.(type) {
+	case nil:
+		return nil, nil
+	case map[string]any:
+		return o[name], nil
+	case exprHeaders:
+		values := http.Header(o).Values(name)
+		if len(values) == 0 {
+			return nil, nil
+		}
+		return strings.Join(values, ", "), nil
+	}
+	return nil, fmt.Errorf("%s: cannot access property %q of %s", node, name, typeName(object))
+}
+
+func (e *exprEvaluator) evaluateUnary(n unaryNode) (any, error) {
+	operand, err := e.evaluate(n.operand)
+	if err != nil {
+		return nil, err
+	}
+	switch n.op {
+	case "!":
+		b, ok := operand.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%s: ! expects a boolean, got %s", n, typeName(operand))
+		}
+		return !b, nil
+	default: // "-"
+		switch v := operand.(type) {
+		case float64:
+			return -v, nil
+		case time.Duration:
+			return -v, nil
+		}
+		return nil, fmt.Errorf("%s: - expects a number or duration, got %s", n, typeName(operand))
+	}
+}
+
+// evaluateLogical evaluates && and || with short-circuiting
+func (e *exprEvaluator) evaluateLogical(n binaryNode) (any, error) {
+	left, err := e.evaluateBool(n.left, n.op)
+	if err != nil {
+		return nil, err
+	}
+	if (n.op == "&&" && !left) || (n.op == "||" && left) {
+		return left, nil
+	}
+	return e.evaluateBool(n.right, n.op)
+}
+
+// evaluateBool evaluates an operand that must be a boolean
+func (e *exprEvaluator) evaluateBool(node exprNode, op string) (bool, error) {
+	value, err := e.evaluate(node)
+	if err != nil {
+		return false, err
+	}
+	b, ok := value.(bool)
+	if !ok {
+		return false, fmt.Errorf("%s expects booleans, but %s is %s", op, node, typeName(value))
+	}
+	return b, nil
+}
+
+// evaluateBinary evaluates arithmetic and comparisons
+func (e *exprEvaluator) evaluateBinary(n binaryNode) (any, error) {
+	left, err := e.evaluate(n.left)
+	if err != nil {
+		return nil, err
+	}
+	right, err := e.evaluate(n.right)
+	if err != nil {
+		return nil, err
+	}
+
+	if !comparisonOperators[n.op] {
+		return arithmetic(n, left, right)
+	}
+
+	result, err := compare(n.op, left, right)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", n, err)
+	}
+	if !result {
+		e.trace = append(e.trace, fmt.Sprintf("%s %s %s", formatValue(left), n.op, formatValue(right)))
+	}
+	return result, nil
+}
+
+// arithmetic evaluates + - * / % on numbers, + on strings and + - on durations
+func arithmetic(n binaryNode, left, right any) (any, error) {
+	switch l := left.(type) {
+	case float64:
+		if r, ok := right.(float64); ok {
+			switch n.op {
+			case "+":
+				return l + r, nil
+			case "-":
+				return l - r, nil
+			case "*":
+				return l * r, nil
+			case "/", "%":
+				if r == 0 {
+					return nil, fmt.Errorf("%s: division by zero", n)
+				}
+				if n.op == "/" {
+					return l / r, nil
+				}
+				return math.Mod(l, r), nil
+			}
+		}
+	case string:
+		if r, ok := right.(string); ok && n.op == "+" {
+			return l + r, nil
+		}
+	case time.Duration:
+		if r, ok := right.(time.Duration); ok {
+			switch n.op {
+			case "+":
+				return l + r, nil
+			case "-":
+				return l - r, nil
+			}
+		}
+		if r, ok := right.(float64); ok {
+			switch n.op {
+			case "*":
+				return time.Duration(float64(l) * r), nil
+			case "/":
+				if r == 0 {
+					return nil, fmt.Errorf("%s: division by zero", n)
+				}
+				return time.Duration(float64(l) / r), nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("%s: %s is not defined for %s and %s", n, n.op, typeName(left), typeName(right))
+}
+
+// compare evaluates a comparison operator
+func compare(op string, left, right any) (bool, error) {
+	switch op {
+	case "==":
+		return equalValues(left, right), nil
+	case "!=":
+		return !equalValues(left, right), nil
+	case "contains":
+		return containsValue(left, right)
+	case "in":
+		return containsValue(right, left)
+	case "matches":
+		s, ok := left.(string)
+		if !ok {
+			return false, fmt.Errorf("matches expects a string, got %s", typeName(left))
+		}
+		switch pattern := right.(type) {
+		case *regexp.Regexp:
+			return pattern.MatchString(s), nil
+		case string:
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return false, fmt.Errorf("invalid regex %q: %w", pattern, err)
+			}
+			return re.MatchString(s), nil
+		}
+		return false, fmt.Errorf("matches expects a regex, got %s", typeName(right))
+	}
+
+	// Ordering is defined between values of the same type
+	var cmp int
+	switch l := left.(type) {
+	case float64:
+		r, ok := right.(float64)
+		if !ok {
+			return false, fmt.Errorf("cannot compare number with %s", typeName(right))
+		}
+		cmp = compareOrdered(l, r)
+	case string:
+		r, ok := right.(string)
+		if !ok {
+			return false, fmt.Errorf("cannot compare string with %s", typeName(right))
+		}
+		cmp = strings.Compare(l, r)
+	case time.Duration:
+		r, ok := right.(time.Duration)
+		if !ok {
+			return false, fmt.Errorf("cannot compare duration with %s, add a unit such as 500ms", typeName(right))
+		}
+		cmp = compareOrdered(l, r)
+	default:
+		return false, fmt.Errorf("%s is not defined for %s", op, typeName(left))
+	}
+
+	switch op {
+	case "<":
+		return cmp < 0, nil
+	case "<=":
+		return cmp <= 0, nil
+	case ">":
+		return cmp > 0, nil
+	default: // ">="
+		return cmp >= 0, nil
+	}
+}
+
+// compareOrdered returns -1, 0 or 1
+func compareOrdered[T float64 | time.Duration](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// equalValues compares values deeply; values of different types are unequal
+func equalValues(left, right any) bool {
+	if l, ok := left.(exprHeaders); ok {
+		left = headersObject(l)
+	}
+	if r, ok := right.(exprHeaders); ok {
+		right = headersObject(r)
+	}
+	return reflect.DeepEqual(left, right)
+}
+
+// containsValue reports whether a string contains a substring, an array
+// contains an equal element or an object has a key
+func containsValue(container, item any) (bool, error) {
+	switch c := container.(type) {
+	case string:
+		s, ok := item.(string)
+		if !ok {
+			return false, fmt.Errorf("a string can only contain a string, got %s", typeName(item))
+		}
+		return strings.Contains(c, s), nil
+	case []any:
+		for _, element := range c {
+			if equalValues(element, item) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case map[string]any:
+		key, ok := item.(string)
+		if !ok {
+			return false, fmt.Errorf("object keys are strings, got %s", typeName(item))
+		}
+		_, found := c[key]
+		return found, nil
+	case exprHeaders:
+		name, ok := item.(string)
+		if !ok {
+			return false, fmt.Errorf("header names are strings, got %s", typeName(item))
+		}
+		return len(http.Header(c).Values(name)) > 0, nil
+	}
+	return false, fmt.Errorf("cannot look for a value in %s", typeName(container))
+}
+
+// headersObject converts headers to an object keyed by lower-case name
+func headersObject(h exprHeaders) map[string]any {
+	object := make(map[string]any, len(h))
+	for name, values := range h {
+		object[strings.ToLower(name)] = strings.Join(values, ", ")
+	}
+	return object
+}
+
+// exprLen returns the number of characters, elements or keys
+func exprLen(args []any) (any, error) {
+	switch v := args[0].(type) {
+	case string:
+		return float64(utf8.RuneCountInString(v)), nil
+	case []any:
+		return float64(len(v)), nil
+	case map[string]any:
+		return float64(len(v)), nil
+	case exprHeaders:
+		return float64(len(v)), nil
+	}
+	return nil, fmt.Errorf("len is not defined for %s", typeName(args[0]))
+}
+
+// exprNumber converts a string, number or boolean to a number
+func exprNumber(args []any) (any, error) {
+	switch v := args[0].(type) {
+	case float64:
+		return v, nil
+	case string:
+		n, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return nil, fmt.Errorf("number: cannot convert %q", v)
+		}
+		return n, nil
+	case bool:
+		if v {
+			return 1.0, nil
+		}
+		return 0.0, nil
+	case time.Duration:
+		// Durations convert to milliseconds, as response times are usually written
+		return float64(v) / float64(time.Millisecond), nil
+	}
+	return nil, fmt.Errorf("number is not defined for %s", typeName(args[0]))
+}
+
+// exprString converts a value to a string; strings are returned unquoted
+func exprString(args []any) (any, error) {
+	if s, ok := args[0].(string); ok {
+		return s, nil
+	}
+	return formatValue(args[0]), nil
+}
+
+// stringFunction wraps a string transformation as a built-in function
+func stringFunction(name string, fn func(string) string) func([]any) (any, error) {
+	return func(args []any) (any, error) {
+		s, ok := args[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("%s expects a string, got %s", name, typeName(args[0]))
+		}
+		return fn(s), nil
+	}
+}
+
+// stringPredicate wraps a two-string test as a built-in function
+func stringPredicate(name string, fn func(string, string) bool) func([]any) (any, error) {
+	return func(args []any) (any, error) {
+		s, ok1 := args[0].(string)
+		affix, ok2 := args[1].(string)
+		if !ok1 || !ok2 {
+			return nil, fmt.Errorf("%s expects two strings, got %s and %s", name, typeName(args[0]), typeName(args[1]))
+		}
+		return fn(s, affix), nil
+	}
+}
+
+// typeName returns the expression type of a value
+func typeName(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case time.Duration:
+		return "duration"
+	case *regexp.Regexp:
+		return "regex"
+	case []any:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// formatValue renders a value for failure messages
+func formatValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case string:
+		return strconv.Quote(v)
+	case time.Duration:
+		return v.String()
+	case *regexp.Regexp:
+		return "/" + v.String() + "/"
+	case exprHeaders:
+		value = headersObject(v)
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
+
+// sortedNames returns the keys of a set in order
+func sortedNames(set map[string]bool) []string {
+	names := make([]string, 0, len(set))
+	for name := range set {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,558 @@
+package assertion
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// tokenKind classifies expression tokens
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenDuration
+	tokenString
+	tokenRegex
+	tokenIdent
+	tokenOperator // Symbols and word operators such as "contains"
+)
+
+// token is a lexical unit of an expression
+type token struct {
+	kind  tokenKind
+	text  string
+	value any // Parsed literal for numbers, durations, strings and regexes
+	pos   int // Byte offset in the expression, for error messages
+}
+
+// exprSymbols lists symbol operators, longest first
+var exprSymbols = []string{"&&", "||", "==", "!=", "<=", ">=", "<", ">", "!", "+", "-", "*", "/", "%", "(", ")", "[", "]", ",", "."}
+
+// exprWordOperators are identifiers lexed as operators
+var exprWordOperators = map[string]bool{"contains": true, "matches": true, "in": true}
+
+// lexExpression splits an expression into tokens
+func lexExpression(src string) ([]token, error) {
+	var tokens []token
+	i := 0
+	for i < len(src) {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c >= '0' && c <= '9':
+			tok, err := lexNumber(src, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, tok)
+			i += len(tok.text)
+		case c == '"' || c == '\'':
+			tok, err := lexString(src, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, tok)
+			i += len(tok.text)
+		case c == '/' && len(tokens) > 0 && tokens[len(tokens)-1].text == "matches":
+			// A slash right after matches starts a regex literal rather than a division
+			tok, err := lexRegex(src, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, tok)
+			i += len(tok.text)
+		case isIdentStart(c):
+			start := i
+			for i < len(src) && (isIdentStart(src[i]) || src[i] >= '0' && src[i] <= '9') {
+				i++
+			}
+			text := src[start:i]
+			kind := tokenIdent
+			if exprWordOperators[text] {
+				kind = tokenOperator
+			}
+			tokens = append(tokens, token{kind: kind, text: text, pos: start})
+		default:
+			matched := false
+			for _, sym := range exprSymbols {
+				if strings.HasPrefix(src[i:], sym) {
+					tokens = append(tokens, token{kind: tokenOperator, text: sym, pos: i})
+					i += len(sym)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("unexpected character %q at position %d", c, i)
+			}
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(src)}), nil
+}
+
+// isIdentStart reports whether c can start a name
+func isIdentStart(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// lexNumber reads a number, or a duration when a unit follows, e.g. "500ms"
+func lexNumber(src string, start int) (token, error) {
+	i := start
+	for i < len(src) && (src[i] >= '0' && src[i] <= '9' || src[i] == '.') {
+		i++
+	}
+	// Units and compound durations such as "1m30s"
+	if i < len(src) && isIdentStart(src[i]) {
+		for i < len(src) && (isIdentStart(src[i]) || src[i] >= '0' && src[i] <= '9' || src[i] == '.') {
+			i++
+		}
+		text := src[start:i]
+		d, err := time.ParseDuration(text)
+		if err != nil {
+			return token{}, fmt.Errorf("invalid duration %q at position %d", text, start)
+		}
+		return token{kind: tokenDuration, text: text, value: d, pos: start}, nil
+	}
+
+	text := src[start:i]
+	n, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return token{}, fmt.Errorf("invalid number %q at position %d", text, start)
+	}
+	return token{kind: tokenNumber, text: text, value: n, pos: start}, nil
+}
+
+// lexString reads a single- or double-quoted string with backslash escapes
+func lexString(src string, start int) (token, error) {
+	quote := src[start]
+	var sb strings.Builder
+	for i := start + 1; i < len(src); i++ {
+		c := src[i]
+		switch {
+		case c == quote:
+			return token{kind: tokenString, text: src[start : i+1], value: sb.String(), pos: start}, nil
+		case c == '\\' && i+1 < len(src):
+			i++
+			switch src[i] {
+			case 'n':
+				sb.WriteByte('\n')
+			case 't':
+				sb.WriteByte('\t')
+			case 'r':
+				sb.WriteByte('\r')
+			default:
+				sb.WriteByte(src[i])
+			}
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return token{}, fmt.Errorf("unterminated string at position %d", start)
+}
+
+// lexRegex reads a /regex/ literal; "\/" is an escaped slash
+func lexRegex(src string, start int) (token, error) {
+	for i := start + 1; i < len(src); i++ {
+		switch src[i] {
+		case '\\':
+			i++
+		case '/':
+			pattern := strings.ReplaceAll(src[start+1:i], `\/`, "/")
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return token{}, fmt.Errorf("invalid regex %q: %w", pattern, err)
+			}
+			return token{kind: tokenRegex, text: src[start : i+1], value: re, pos: start}, nil
+		}
+	}
+	return token{}, fmt.Errorf("unterminated regex at position %d", start)
+}
+
+// exprNode is a node of a parsed expression
+type exprNode interface {
+	String() string
+}
+
+type (
+	// literalNode is a number, duration, string, regex, boolean or null
+	literalNode struct {
+		value any
+		text  string
+	}
+
+	// identNode is a top-level name such as status or json
+	identNode struct {
+		name string
+	}
+
+	// memberNode is property access, e.g. json.items
+	memberNode struct {
+		object exprNode
+		name   string
+	}
+
+	// indexNode is index access, e.g. json.items[0] or headers["ETag"]
+	indexNode struct {
+		object exprNode
+		index  exprNode
+	}
+
+	// callNode is a function call, e.g. len(json.items)
+	callNode struct {
+		name string
+		args []exprNode
+	}
+
+	// listNode is a list literal, e.g. [200, 201]
+	listNode struct {
+		items []exprNode
+	}
+
+	// unaryNode is negation, e.g. !json.deleted or -1
+	unaryNode struct {
+		op      string
+		operand exprNode
+	}
+
+	// binaryNode is an arithmetic, comparison or logical operation
+	binaryNode struct {
+		op          string
+		left, right exprNode
+	}
+)
+
+func (n literalNode) String() string { return n.text }
+func (n identNode) String() string   { return n.name }
+func (n memberNode) String() string  { return n.object.String() + "." + n.name }
+func (n indexNode) String() string   { return n.object.String() + "[" + n.index.String() + "]" }
+func (n unaryNode) String() string   { return n.op + n.operand.String() }
+
+func (n binaryNode) String() string {
+	return n.left.String() + " " + n.op + " " + n.right.String()
+}
+
+func (n callNode) String() string {
+	args := make([]string, len(n.args))
+	for i, arg := range n.args {
+		args[i] = arg.String()
+	}
+	return n.name + "(" + strings.Join(args, ", ") + ")"
+}
+
+func (n listNode) String() string {
+	items := make([]string, len(n.items))
+	for i, item := range n.items {
+		items[i] = item.String()
+	}
+	return "[" + strings.Join(items, ", ") + "]"
+}
+
+// comparisonOperators bind tighter than && and || but looser than arithmetic
+var comparisonOperators = map[string]bool{
+	"==": true, "!=": true, "<": true, "<=": true, ">": true, ">=": true,
+	"contains": true, "matches": true, "in": true,
+}
+
+// exprParser is a recursive descent parser over the token stream
+// Precedence, loosest first: ||, &&, comparisons, + -, * / %, unary ! -, postfix
+type exprParser struct {
+	tokens  []token
+	pos     int
+	idents  map[string]bool
+	subject string // Implicit left operand for comparisons such as ">= 200"
+}
+
+// parseExpression parses src into an AST
+// idents lists the names the expression may refer to; subject, when set, is
+// used as the left operand of comparisons that start with an operator
+func parseExpression(src string, idents map[string]bool, subject string) (exprNode, error) {
+	tokens, err := lexExpression(src)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 1 {
+		return nil, fmt.Errorf("empty expression")
+	}
+
+	p := &exprParser{tokens: tokens, idents: idents, subject: subject}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, p.unexpected(tok)
+	}
+	return node, nil
+}
+
+func (p *exprParser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *exprParser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// accept consumes the next token if it is the operator op
+func (p *exprParser) accept(op string) bool {
+	if tok := p.peek(); tok.kind == tokenOperator && tok.text == op {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// expect consumes the operator op or reports what was found instead
+func (p *exprParser) expect(op string) error {
+	if p.accept(op) {
+		return nil
+	}
+	tok := p.peek()
+	if tok.kind == tokenEOF {
+		return fmt.Errorf("expected %q at end of expression", op)
+	}
+	return fmt.Errorf("expected %q, got %q at position %d", op, tok.text, tok.pos)
+}
+
+// unexpected reports a token that cannot appear where it was found
+func (p *exprParser) unexpected(tok token) error {
+	if tok.kind == tokenEOF {
+		return fmt.Errorf("unexpected end of expression")
+	}
+	return fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos)
+}
+
+func (p *exprParser) parseOr() (exprNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: "||", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseAnd() (exprNode, error) {
+	left, err := p.parseComparison()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("&&") {
+		right, err := p.parseComparison()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: "&&", left: left, right: right}
+	}
+	return left, nil
+}
+
+// parseComparison parses a single, non-chained comparison
+func (p *exprParser) parseComparison() (exprNode, error) {
+	var left exprNode
+	if tok := p.peek(); p.subject != "" && tok.kind == tokenOperator && comparisonOperators[tok.text] {
+		// ">= 200" compares the implicit subject
+		left = identNode{name: p.subject}
+	} else {
+		var err error
+		if left, err = p.parseAdditive(); err != nil {
+			return nil, err
+		}
+	}
+
+	tok := p.peek()
+	if tok.kind != tokenOperator || !comparisonOperators[tok.text] {
+		return left, nil
+	}
+	p.next()
+
+	right, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	if next := p.peek(); next.kind == tokenOperator && comparisonOperators[next.text] {
+		return nil, fmt.Errorf("comparisons cannot be chained, use && at position %d", next.pos)
+	}
+	return binaryNode{op: tok.text, left: left, right: right}, nil
+}
+
+func (p *exprParser) parseAdditive() (exprNode, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok := p.peek()
+		if tok.kind != tokenOperator || (tok.text != "+" && tok.text != "-") {
+			return left, nil
+		}
+		p.next()
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: tok.text, left: left, right: right}
+	}
+}
+
+func (p *exprParser) parseMultiplicative() (exprNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok := p.peek()
+		if tok.kind != tokenOperator || (tok.text != "*" && tok.text != "/" && tok.text != "%") {
+			return left, nil
+		}
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: tok.text, left: left, right: right}
+	}
+}
+
+func (p *exprParser) parseUnary() (exprNode, error) {
+	if tok := p.peek(); tok.kind == tokenOperator && (tok.text == "!" || tok.text == "-") {
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return unaryNode{op: tok.text, operand: operand}, nil
+	}
+	return p.parsePostfix()
+}
+
+// parsePostfix parses a primary followed by any number of .name and [index]
+func (p *exprParser) parsePostfix() (exprNode, error) {
+	node, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch {
+		case p.accept("."):
+			tok := p.next()
+			// Word operators are valid property names, e.g. json.in
+			if tok.kind != tokenIdent && !(tok.kind == tokenOperator && exprWordOperators[tok.text]) {
+				return nil, fmt.Errorf("expected a property name after \".\" at position %d", tok.pos)
+			}
+			node = memberNode{object: node, name: tok.text}
+		case p.accept("["):
+			index, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			node = indexNode{object: node, index: index}
+		default:
+			return node, nil
+		}
+	}
+}
+
+func (p *exprParser) parsePrimary() (exprNode, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokenNumber, tokenDuration, tokenString, tokenRegex:
+		return literalNode{value: tok.value, text: tok.text}, nil
+	case tokenIdent:
+		switch tok.text {
+		case "true":
+			return literalNode{value: true, text: tok.text}, nil
+		case "false":
+			return literalNode{value: false, text: tok.text}, nil
+		case "null":
+			return literalNode{value: nil, text: tok.text}, nil
+		}
+		if p.accept("(") {
+			return p.parseCall(tok)
+		}
+		if !p.idents[tok.text] {
+			return nil, fmt.Errorf("unknown name %q at position %d: expected one of %s", tok.text, tok.pos, strings.Join(sortedNames(p.idents), ", "))
+		}
+		return identNode{name: tok.text}, nil
+	case tokenOperator:
+		switch tok.text {
+		case "(":
+			node, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			return node, p.expect(")")
+		case "[":
+			return p.parseList()
+		}
+	}
+	return nil, p.unexpected(tok)
+}
+
+// parseCall parses the arguments of a function call and checks its arity
+func (p *exprParser) parseCall(name token) (exprNode, error) {
+	fn, ok := exprFunctions[name.text]
+	if !ok {
+		return nil, fmt.Errorf("unknown function %q at position %d", name.text, name.pos)
+	}
+
+	var args []exprNode
+	if !p.accept(")") {
+		for {
+			arg, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+			if p.accept(")") {
+				break
+			}
+			if err := p.expect(","); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if len(args) != fn.arity {
+		return nil, fmt.Errorf("%s expects %d argument(s), got %d", name.text, fn.arity, len(args))
+	}
+	return callNode{name: name.text, args: args}, nil
+}
+
+// parseList parses the items of a list literal after "["
+func (p *exprParser) parseList() (exprNode, error) {
+	var items []exprNode
+	if p.accept("]") {
+		return listNode{}, nil
+	}
+	for {
+		item, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+		if p.accept("]") {
+			return listNode{items: items}, nil
+		}
+		if err := p.expect(","); err != nil {
+			return nil, err
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package assertion
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"curlex/internal/models"
+)
+
+func exprResult() *models.TestResult {
+	return &models.TestResult{
+		StatusCode:   200,
+		ResponseTime: 120 * time.Millisecond,
+		Headers: http.Header{
+			"Content-Type": {"application/json; charset=utf-8"},
+			"Vary":         {"Accept", "Origin"},
+		},
+		ResponseBody: `{"total": 3, "items": [{"id": 1, "tags": ["a"]}, {"id": 2}, {"id": 3}], "name": "Widget", "deleted": false, "next": null}`,
+		Variables:    map[string]string{"user_id": "2"},
+	}
+}
+
+func TestParseExpression_Precedence(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string // Fully parenthesized by the parse tree
+	}{
+		{"a || b && c", "(a || (b && c))"},
+		{"a && b || c", "((a && b) || c)"},
+		{"!a && b", "((!a) && b)"},
+		{"1 + 2 * 3 == 7", "((1 + (2 * 3)) == 7)"},
+		{"(1 + 2) * 3", "((1 + 2) * 3)"},
+		{"-a.b[0] > 1", "((-a.b[0]) > 1)"},
+		{"len(a) - 1 >= b % 2", "((len(a) - 1) >= (b % 2))"},
+	}
+
+	names := map[string]bool{"a": true, "b": true, "c": true}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			node, err := parseExpression(tt.expr, names, "")
+			if err != nil {
+				t.Fatalf("parseExpression() error = %v", err)
+			}
+			if got := parenthesize(node); got != tt.want {
+				t.Errorf("parse tree = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// parenthesize renders a parse tree with explicit grouping
+func parenthesize(node exprNode) string {
+	switch n := node.(type) {
+	case binaryNode:
+		return "(" + parenthesize(n.left) + " " + n.op + " " + parenthesize(n.right) + ")"
+	case unaryNode:
+		return "(" + n.op + parenthesize(n.operand) + ")"
+	}
+	return node.String()
+}
+
+func TestParseExpression_Errors(t *testing.T) {
+	tests := []struct {
+		expr    string
+		wantErr string
+	}{
+		{"", "empty expression"},
+		{"status ==", "unexpected end of expression"},
+		{"(status == 200", `expected ")" at end of expression`},
+		{"status == 200)", `unexpected ")" at position 13`},
+		{"stats == 200", `unknown name "stats"`},
+		{"size(json) > 1", `unknown function "size"`},
+		{"len(body, 1) > 1", "len expects 1 argument(s), got 2"},
+		{"1 < status < 3", "comparisons cannot be chained"},
+		{"body == 'abc", "unterminated string"},
+		{"body matches /[/", "invalid regex"},
+		{"duration < 5parsecs", `invalid duration "5parsecs"`},
+		{"status # 2", "unexpected character '#'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := parseExpression(tt.expr, exprNames, "")
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("parseExpression() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExprValidator(t *testing.T) {
+	validator := &ExprValidator{}
+
+	tests := []struct {
+		name       string
+		expr       string
+		shouldPass bool
+	}{
+		{"status and json", "json.total == len(json.items) && status == 200", true},
+		{"or with precedence", "status == 500 || status == 200 && json.name == 'Widget'", true},
+		{"parentheses", "(status == 500 || status == 200) && !json.deleted", true},
+		{"arithmetic", "json.items[2].id - json.items[0].id == 2", true},
+		{"header case-insensitive", "headers['content-type'] contains 'json'", true},
+		{"header member", "headers.vary == 'Accept, Origin'", true},
+		{"header presence", "'ETag' in headers", false},
+		{"body string", "body contains '\"total\"'", true},
+		{"regex literal", "json.name matches /^Wid/", true},
+		{"regex string", "lower(json.name) matches '^wid'", true},
+		{"duration literal", "duration < 500ms", true},
+		{"duration arithmetic", "duration * 2 > 200ms", true},
+		{"variables", "number(vars.user_id) == json.items[1].id", true},
+		{"missing property is null", "json.missing == null && json.next == null", true},
+		{"null property chain", "json.missing.deeper == null", true},
+		{"out of range index", "json.items[10] == null", true},
+		{"list membership", "status in [200, 201]", true},
+		{"array contains", "json.items[0].tags contains 'a'", true},
+		{"object key", "json contains 'total'", true},
+		{"deep equality", "json.items[0] == json.items[0] && json.items[0].tags == ['a']", true},
+		{"string functions", "starts_with(upper(json.name), 'WID') && ends_with(trim(' x '), 'x')", true},
+		{"type function", "type(json.items) == 'array' && type(json.next) == 'null'", true},
+		{"string concatenation", "json.name + '-' + string(json.total) == 'Widget-3'", true},
+		{"false comparison", "json.total > 5", false},
+		{"short circuit", "status == 500 && json.total.nope > 1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failure := validator.Validate(exprResult(), models.Assertion{Type: models.AssertionExpr, Value: tt.expr})
+			if tt.shouldPass && failure != nil {
+				t.Errorf("Expected to pass, but failed: %v", failure)
+			}
+			if !tt.shouldPass && failure == nil {
+				t.Errorf("Expected to fail, but passed")
+			}
+		})
+	}
+}
+
+func TestExprValidator_FailureMessages(t *testing.T) {
+	validator := &ExprValidator{}
+
+	tests := []struct {
+		name        string
+		expr        string
+		wantActual  string
+		wantMessage string
+	}{
+		{"shows compared values", "json.total == len(json.items) + 1 && status == 200", "3 == 4", "got 3 == 4"},
+		{"every failed comparison", "status == 201 || json.name == 'Gadget'", `200 == 201, "Widget" == "Gadget"`, ""},
+		{"type error", "json.name > 3", "error", "cannot compare string with number"},
+		{"boolean operands", "json.total && true", "error", "&& expects booleans, but json.total is number"},
+		{"non-boolean result", "json.total + 1", "4", "must be true or false, got number 4"},
+		{"duration without unit", "duration < 500", "error", "add a unit such as 500ms"},
+		{"invalid expression", "status ==", "", "invalid expression: unexpected end of expression"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failure := validator.Validate(exprResult(), models.Assertion{Type: models.AssertionExpr, Value: tt.expr})
+			if failure == nil {
+				t.Fatal("Expected failure, got nil")
+			}
+			if failure.Actual != tt.wantActual {
+				t.Errorf("Actual = %q, want %q", failure.Actual, tt.wantActual)
+			}
+			if !strings.Contains(failure.Message, tt.wantMessage) {
+				t.Errorf("Message = %q, want it to contain %q", failure.Message, tt.wantMessage)
+			}
+		})
+	}
+
+	// json is only parsed when the expression uses it
+	result := exprResult()
+	result.ResponseBody = "<html>"
+	if failure := validator.Validate(result, models.Assertion{Value: "status == 200"}); failure != nil {
+		t.Errorf("Expected non-JSON body to be ignored, got %v", failure)
+	}
+	failure := validator.Validate(result, models.Assertion{Value: "json.total == 3"})
+	if failure == nil || !strings.Contains(failure.Message, "response body is not valid JSON") {
+		t.Errorf("Expected invalid JSON failure, got %v", failure)
+	}
+}
@@ -11,15 +11,24 @@ import (
 // HeaderValidator validates response header assertions
 type HeaderValidator struct{}
 
+// headerExpression is a parsed header assertion
+type headerExpression struct {
+	mode  string // "any" or "all" for repeated headers, "" for the first value
+	name  string
+	count bool // Compare the number of values instead of the values
+	cond  condition
+}
+
 // Validate checks if the response headers match the assertion
 func (v *HeaderValidator) Validate(result *models.TestResult, assertion models.Assertion) *models.AssertionFailure {
-	// Parse the assertion: "Header-Name operator value"
-	// Examples: "Content-Type == 'application/json'", "Content-Type contains json"
+	// Parse the assertion: "[any|all] Header-Name operator [value]"
+	// Examples: "Content-Type == 'application/json'", "Server not exists",
+	// "all Set-Cookie contains Secure", "Set-Cookie count >= 2"
 
 	expr := strings.TrimSpace(assertion.Value)
 
 	// Parse expression
-	headerName, operator, expectedValue, err := v.parseExpression(expr)
+	he, err := v.parseExpression(expr)
 	if err != nil {
 		return &models.AssertionFailure{
 			Type:    models.AssertionHeader,
@@ -27,26 +36,97 @@ func (v *HeaderValidator) Validate(result *models.TestResult, assertion models.A
 		}
 	}
 
-	// Get actual header value (case-insensitive)
-	actualValue := v.getHeader(result.Headers, headerName)
+	// Get every value of the header (case-insensitive)
+	values, found := v.getHeader(result.Headers, he.name)
+
+	if he.count {
+		count := len(values)
+		if ok, _ := he.cond.evaluate(float64(count)); !ok {
+			return &models.AssertionFailure{
+				Type:     models.AssertionHeader,
+				Expected: fmt.Sprintf("%s count %s", he.name, he.cond),
+				Actual:   fmt.Sprintf("%s count = %d", he.name, count),
+				Message:  fmt.Sprintf("%s count %s failed: got %d", he.name, he.cond, count),
+			}
+		}
+		return nil
+	}
+
+	switch he.cond.operator {
+	case "exists":
+		if !found {
+			return &models.AssertionFailure{
+				Type:     models.AssertionHeader,
+				Expected: fmt.Sprintf("header %q to exist", he.name),
+				Actual:   "header not found",
+				Message:  fmt.Sprintf("header %q not found in response", he.name),
+			}
+		}
+		return nil
+	case "not exists":
+		if found {
+			actual := strings.Join(values, ", ")
+			return &models.AssertionFailure{
+				Type:     models.AssertionHeader,
+				Expected: fmt.Sprintf("header %q not to exist", he.name),
+				Actual:   fmt.Sprintf("%s = %s", he.name, actual),
+				Message:  fmt.Sprintf("header %q should not be present: got %s", he.name, actual),
+			}
+		}
+		return nil
+	}
 
-	// Check if header exists
-	if actualValue == "" {
+	// Check if header exists; a missing header is never equal to a value
+	if !found {
+		if he.cond.operator == "!=" || he.cond.operator == "not contains" {
+			return nil
+		}
 		return &models.AssertionFailure{
 			Type:     models.AssertionHeader,
-			Expected: fmt.Sprintf("header %q to exist", headerName),
+			Expected: fmt.Sprintf("header %q to exist", he.name),
 			Actual:   "header not found",
-			Message:  fmt.Sprintf("header %q not found in response", headerName),
+			Message:  fmt.Sprintf("header %q not found in response", he.name),
 		}
 	}
 
-	// Evaluate the condition
-	if !v.evaluateCondition(actualValue, operator, expectedValue) {
+	// Without a mode only the first value is checked
+	candidates := values[:1]
+	if he.mode != "" {
+		candidates = values
+	}
+
+	matched := 0
+	for _, actual := range candidates {
+		ok, err := he.cond.evaluate(actual)
+		if err != nil {
+			return &models.AssertionFailure{
+				Type:     models.AssertionHeader,
+				Expected: fmt.Sprintf("%s %s", he.name, he.cond),
+				Actual:   fmt.Sprintf("%s = %s", he.name, actual),
+				Message:  fmt.Sprintf("%s %s failed: %v", he.name, he.cond, err),
+			}
+		}
+		if ok {
+			matched++
+		}
+	}
+
+	ok := matched == len(candidates)
+	if he.mode == "any" {
+		ok = matched > 0
+	}
+
+	if !ok {
+		condition := fmt.Sprintf("%s %s", he.name, he.cond)
+		if he.mode != "" {
+			condition = he.mode + " " + condition
+		}
+		actual := strings.Join(candidates, ", ")
 		return &models.AssertionFailure{
 			Type:     models.AssertionHeader,
-			Expected: fmt.Sprintf("%s %s %s", headerName, operator, expectedValue),
-			Actual:   fmt.Sprintf("%s = %s", headerName, actualValue),
-			Message:  fmt.Sprintf("%s %s %s failed: got %s", headerName, operator, expectedValue, actualValue),
+			Expected: condition,
+			Actual:   fmt.Sprintf("%s = %s", he.name, actual),
+			Message:  fmt.Sprintf("%s failed: got %s", condition, actual),
 		}
 	}
 
@@ -54,77 +134,55 @@ func (v *HeaderValidator) Validate(result *models.TestResult, assertion models.A
 }
 
 // parseExpression parses a header assertion expression
-// Format: "Header-Name operator value"
-// Returns: headerName, operator, value, error
-func (v *HeaderValidator) parseExpression(expr string) (string, string, string, error) {
-	// Operators in order (longest first)
-	operators := []string{" contains ", " == ", " != ", " > ", " < ", " >= ", " <= "}
-
-	for _, op := range operators {
-		if idx := strings.Index(expr, op); idx != -1 {
-			headerName := strings.TrimSpace(expr[:idx])
-			value := strings.TrimSpace(expr[idx+len(op):])
-			// Remove quotes from value if present
-			value = strings.Trim(value, `"'`)
-			return headerName, strings.TrimSpace(op), value, nil
-		}
+// Format: "[any|all] Header-Name [count] operator [value]"
+// Header names cannot contain spaces, so the operator always follows the
+// first space and the value may itself contain operators
+func (v *HeaderValidator) parseExpression(expr string) (headerExpression, error) {
+	var he headerExpression
+
+	rest := expr
+	if mode, after, found := strings.Cut(rest, " "); found && (strings.EqualFold(mode, "any") || strings.EqualFold(mode, "all")) {
+		he.mode = strings.ToLower(mode)
+		rest = strings.TrimSpace(after)
 	}
 
-	return "", "", "", fmt.Errorf("no valid operator found in expression: %s", expr)
-}
+	name, rest, found := strings.Cut(rest, " ")
+	if !found || name == "" {
+		return he, fmt.Errorf("no valid operator found in expression: %s", expr)
+	}
+	he.name = name
+	rest = strings.TrimSpace(rest)
 
-// getHeader retrieves a header value (case-insensitive)
-func (v *HeaderValidator) getHeader(headers map[string][]string, name string) string {
-	for key, values := range headers {
-		if strings.EqualFold(key, name) {
-			if len(values) > 0 {
-				return values[0] // Return first value
-			}
+	if after, ok := strings.CutPrefix(rest, "count"); ok && (after == "" || after[0] == ' ') {
+		he.count = true
+		op, value, ok := cutOperator(strings.TrimSpace(after))
+		if _, err := strconv.Atoi(value); !ok || err != nil || !isComparison(op) {
+			return he, fmt.Errorf("count expects a comparison and a number, e.g. count >= 2: %s", expr)
 		}
+		he.cond, _ = parseCondition(op, value)
+		return he, nil
+	}
+
+	op, value, ok := cutOperator(rest)
+	if !ok {
+		return he, fmt.Errorf("no valid operator found in expression: %s", expr)
+	}
+	cond, err := parseCondition(op, value)
+	if err != nil {
+		return he, fmt.Errorf("%v: %s", err, expr)
 	}
-	return ""
+	he.cond = cond
+	return he, nil
 }
 
-// evaluateCondition evaluates a comparison between actual and expected header values
-func (v *HeaderValidator) evaluateCondition(actual, operator, expected string) bool {
-	switch operator {
-	case "==":
-		return actual == expected
-	case "!=":
-		return actual != expected
-	case "contains":
-		return strings.Contains(actual, expected)
-	case ">":
-		// Try numeric comparison
-		actualNum, err1 := strconv.ParseFloat(actual, 64)
-		expectedNum, err2 := strconv.ParseFloat(expected, 64)
-		if err1 == nil && err2 == nil {
-			return actualNum > expectedNum
-		}
-		// Fall back to string comparison
-		return actual > expected
-	case "<":
-		actualNum, err1 := strconv.ParseFloat(actual, 64)
-		expectedNum, err2 := strconv.ParseFloat(expected, 64)
-		if err1 == nil && err2 == nil {
-			return actualNum < expectedNum
-		}
-		return actual < expected
-	case ">=":
-		actualNum, err1 := strconv.ParseFloat(actual, 64)
-		expectedNum, err2 := strconv.ParseFloat(expected, 64)
-		if err1 == nil && err2 == nil {
-			return actualNum >= expectedNum
-		}
-		return actual >= expected
-	case "<=":
-		actualNum, err1 := strconv.ParseFloat(actual, 64)
-		expectedNum, err2 := strconv.ParseFloat(expected, 64)
-		if err1 == nil && err2 == nil {
-			return actualNum <= expectedNum
+// getHeader retrieves every value of a header (case-insensitive)
+// found is true when the header is present, even with an empty value
+func (v *HeaderValidator) getHeader(headers map[string][]string, name string) (values []string, found bool) {
+	for key, vals := range headers {
+		if strings.EqualFold(key, name) && len(vals) > 0 {
+			values = append(values, vals...)
+			found = true
 		}
-		return actual <= expected
-	default:
-		return false
 	}
+	return values, found
 }
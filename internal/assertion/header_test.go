@@ -2,6 +2,7 @@ package assertion
 
 import (
 	"net/http"
+	"strings"
 	"testing"
 
 	"curlex/internal/models"
@@ -189,3 +190,88 @@ func TestHeaderValidator_AllOperators(t *testing.T) {
 		})
 	}
 }
+
+func TestHeaderValidator_ExistenceAndRepeatedValues(t *testing.T) {
+	result := &models.TestResult{
+		Headers: http.Header{
+			"Content-Type":   []string{"application/json"},
+			"Set-Cookie":     []string{"a=1; Secure; HttpOnly", "b=2; Secure"},
+			"X-Empty":        []string{""},
+			"X-Expression":   []string{"a == b"},
+			"Cache-Control":  []string{"no-store"},
+			"X-Request-Id":   []string{"req-8f3a2c"},
+			"Content-Length": []string{"42"},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		expression string
+		shouldPass bool
+	}{
+		{"exists", "Content-Type exists", true},
+		{"exists with empty value", "X-Empty exists", true},
+		{"exists missing", "Server exists", false},
+		{"not exists", "X-Powered-By not exists", true},
+		{"not exists present", "Content-Type not exists", false},
+		{"not equal passes when missing", "Server != nginx", true},
+		{"equal fails when missing", "Server == nginx", false},
+		{"value containing operator", "X-Expression == 'a == b'", true},
+		{"value containing contains", "X-Expression contains ==", true},
+		{"matches slashes", "X-Request-Id matches /^req-[0-9a-f]{6}$/", true},
+		{"matches bare pattern", "Cache-Control matches no-(store|cache)", true},
+		{"matches fails", "X-Request-Id matches /^id-/", false},
+		{"first value only by default", "Set-Cookie contains b=2", false},
+		{"any value", "any Set-Cookie contains b=2", true},
+		{"any value fails", "any Set-Cookie contains c=3", false},
+		{"all values", "all Set-Cookie contains Secure", true},
+		{"all values fails", "all Set-Cookie contains HttpOnly", false},
+		{"all values not equal", "all Set-Cookie != c=3", true},
+		{"count", "Set-Cookie count == 2", true},
+		{"count comparison", "Set-Cookie count >= 3", false},
+		{"count missing header", "Server count == 0", true},
+		{"numeric after fix", "Content-Length >= 42", true},
+	}
+
+	validator := &HeaderValidator{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failure := validator.Validate(result, models.Assertion{Type: models.AssertionHeader, Value: tt.expression})
+			if tt.shouldPass && failure != nil {
+				t.Errorf("Expected pass, got failure: %v", failure.Message)
+			}
+			if !tt.shouldPass && failure == nil {
+				t.Error("Expected failure, got pass")
+			}
+		})
+	}
+}
+
+func TestHeaderValidator_InvalidExpressions(t *testing.T) {
+	result := &models.TestResult{Headers: http.Header{"X-Value": []string{"1"}}}
+
+	tests := []struct {
+		name       string
+		expression string
+		wantErr    string
+	}{
+		{"exists with value", "X-Value exists yes", "takes no value"},
+		{"count without comparison", "X-Value count 2", "count expects a comparison"},
+		{"count without number", "X-Value count > many", "count expects a comparison"},
+		{"invalid regex", "X-Value matches /[/", "invalid regex"},
+		{"unknown operator", "X-Value like 1", "no valid operator"},
+	}
+
+	validator := &HeaderValidator{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failure := validator.Validate(result, models.Assertion{Type: models.AssertionHeader, Value: tt.expression})
+			if failure == nil {
+				t.Fatal("Expected failure, got pass")
+			}
+			if !strings.Contains(failure.Message, tt.wantErr) {
+				t.Errorf("Message = %q, want it to contain %q", failure.Message, tt.wantErr)
+			}
+		})
+	}
+}
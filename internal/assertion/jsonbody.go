@@ -0,0 +1,407 @@
+package assertion
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"math/big"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"curlex/internal/models"
+	"curlex/internal/parser"
+	"gopkg.in/yaml.v3"
+)
+
+// JSONBodyValidator compares the whole response body against an expected
+// JSON document, ignoring key order and number formatting
+type JSONBodyValidator struct{}
+
+// Validate checks if the response body equals the expected document
+// Only the first difference is returned; the engine uses ValidateAll
+func (v *JSONBodyValidator) Validate(result *models.TestResult, assertion models.Assertion) *models.AssertionFailure {
+	failures := v.ValidateAll(result, assertion)
+	if len(failures) == 0 {
+		return nil
+	}
+	return &failures[0]
+}
+
+// ValidateAll compares the response body against the expected document and
+// reports every differing path with its expected and actual values
+func (v *JSONBodyValidator) ValidateAll(result *models.TestResult, assertion models.Assertion) []models.AssertionFailure {
+	comparer, expected, err := v.load(result.Test.BaseDir, assertion.Value)
+	if err != nil {
+		return []models.AssertionFailure{{
+			Type:    models.AssertionJSONBody,
+			Message: fmt.Sprintf("invalid json_body: %v", err),
+		}}
+	}
+
+	actual, err := decodeJSON([]byte(result.ResponseBody))
+	if err != nil {
+		return []models.AssertionFailure{{
+			Type:     models.AssertionJSONBody,
+			Expected: "JSON body",
+			Actual:   excerpt(result.ResponseBody, 0, 0),
+			Message:  fmt.Sprintf("response body is not valid JSON: %v", err),
+		}}
+	}
+
+	return comparer.diff(expected, actual, nil)
+}
+
+// load reads the assertion's options and expected document
+func (v *JSONBodyValidator) load(baseDir, value string) (*jsonComparer, any, error) {
+	body, err := models.ParseJSONBody(value)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data := []byte(body.Expected)
+	if body.File != "" {
+		path := parser.ResolvePath(baseDir, body.File)
+		data, err = os.ReadFile(path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read expected body file: %w", err)
+		}
+		// YAML files are converted to JSON
+		if strings.HasSuffix(path, ".yaml") || strings.HasSuffix(path, ".yml") {
+			var doc any
+			if err := yaml.Unmarshal(data, &doc); err != nil {
+				return nil, nil, fmt.Errorf("failed to parse expected body file: %w", err)
+			}
+			if data, err = json.Marshal(doc); err != nil {
+				return nil, nil, fmt.Errorf("failed to parse expected body file: %w", err)
+			}
+		}
+	}
+
+	expected, err := decodeJSON(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("expected body is not valid JSON: %w", err)
+	}
+
+	comparer := &jsonComparer{
+		allowExtraFields: body.AllowExtraFields,
+		unorderedArrays:  body.UnorderedArrays,
+	}
+	for _, path := range body.Ignore {
+		pattern, err := parseJSONBodyPath(path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid ignore path %q: %w", path, err)
+		}
+		comparer.ignore = append(comparer.ignore, pattern)
+	}
+	return comparer, expected, nil
+}
+
+// decodeJSON parses a single JSON document, keeping numbers exact
+func decodeJSON(data []byte) (any, error) {
+	decoder := json.NewDecoder(strings.NewReader(string(data)))
+	decoder.UseNumber()
+
+	var doc any
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, fmt.Errorf("unexpected data after the JSON document")
+	}
+	return doc, nil
+}
+
+// jsonSegment is one step of a path into a JSON document: an object key or
+// an array index. In ignore paths "*" and [*] match any key or index
+type jsonSegment struct {
+	key   string
+	index int // -1 for object keys
+}
+
+// wildcardIndex is the index of [*] in ignore paths
+const wildcardIndex = -2
+
+// parseJSONBodyPath parses an ignore path such as ".id", ".items[*].id" or
+// `.headers["content-type"]`
+func parseJSONBodyPath(path string) ([]jsonSegment, error) {
+	if !strings.HasPrefix(path, ".") && !strings.HasPrefix(path, "[") {
+		return nil, fmt.Errorf("must start with . or [")
+	}
+
+	var segments []jsonSegment
+	for i := 0; i < len(path); {
+		switch {
+		case path[i] == '.' && (i+1 == len(path) || path[i+1] != '['):
+			end := i + 1
+			for end < len(path) && path[end] != '.' && path[end] != '[' {
+				end++
+			}
+			if end == i+1 {
+				return nil, fmt.Errorf("empty key at position %d", i)
+			}
+			segments = append(segments, jsonSegment{key: path[i+1 : end], index: -1})
+			i = end
+		case path[i] == '.':
+			i++ // ".[0]" is the same as "[0]"
+		case path[i] == '[':
+			end := strings.IndexByte(path[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("missing ] after position %d", i)
+			}
+			inner := path[i+1 : i+end]
+			segment, err := parseJSONBodyBracket(inner)
+			if err != nil {
+				return nil, err
+			}
+			segments = append(segments, segment)
+			i += end + 1
+		default:
+			return nil, fmt.Errorf("unexpected %q at position %d", path[i], i)
+		}
+	}
+	return segments, nil
+}
+
+// parseJSONBodyBracket parses the inside of [0], [*] or ["key"]
+func parseJSONBodyBracket(inner string) (jsonSegment, error) {
+	if inner == "*" {
+		return jsonSegment{index: wildcardIndex}, nil
+	}
+	if strings.HasPrefix(inner, `"`) {
+		key, err := strconv.Unquote(inner)
+		if err != nil {
+			return jsonSegment{}, fmt.Errorf("invalid quoted key %s", inner)
+		}
+		return jsonSegment{key: key, index: -1}, nil
+	}
+	index, err := strconv.Atoi(inner)
+	if err != nil || index < 0 {
+		return jsonSegment{}, fmt.Errorf("invalid index [%s]", inner)
+	}
+	return jsonSegment{index: index}, nil
+}
+
+// formatJSONPath renders a path as ".items[0].id"; the root is "."
+func formatJSONPath(path []jsonSegment) string {
+	if len(path) == 0 {
+		return "."
+	}
+	var sb strings.Builder
+	for _, segment := range path {
+		switch {
+		case segment.index >= 0:
+			sb.WriteString("[" + strconv.Itoa(segment.index) + "]")
+		case isJSONIdentifier(segment.key):
+			sb.WriteString("." + segment.key)
+		default:
+			sb.WriteString("[" + strconv.Quote(segment.key) + "]")
+		}
+	}
+	return sb.String()
+}
+
+// isJSONIdentifier reports whether a key can be written after a dot
+func isJSONIdentifier(key string) bool {
+	if key == "" || key == "*" {
+		return false
+	}
+	for _, r := range key {
+		if r == '.' || r == '[' || r == ']' || r == '"' || r == ' ' {
+			return false
+		}
+	}
+	return true
+}
+
+// jsonComparer compares decoded JSON documents
+type jsonComparer struct {
+	ignore           [][]jsonSegment
+	allowExtraFields bool
+	unorderedArrays  bool
+}
+
+// ignored reports whether a path matches one of the ignore paths
+func (c *jsonComparer) ignored(path []jsonSegment) bool {
+	for _, pattern := range c.ignore {
+		if len(pattern) != len(path) {
+			continue
+		}
+		matched := true
+		for i, segment := range pattern {
+			switch {
+			case segment.index == wildcardIndex:
+				matched = path[i].index >= 0
+			case segment.index >= 0:
+				matched = path[i].index == segment.index
+			default:
+				matched = path[i].index < 0 && (segment.key == "*" || segment.key == path[i].key)
+			}
+			if !matched {
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// diff returns a failure for every path where actual differs from expected
+func (c *jsonComparer) diff(expected, actual any, path []jsonSegment) []models.AssertionFailure {
+	if c.ignored(path) {
+		return nil
+	}
+
+	switch expectedValue := expected.(type) {
+	case map[string]any:
+		if actualValue, ok := actual.(map[string]any); ok {
+			return c.diffObjects(expectedValue, actualValue, path)
+		}
+	case []any:
+		if actualValue, ok := actual.([]any); ok {
+			if c.unorderedArrays {
+				return c.diffUnordered(expectedValue, actualValue, path)
+			}
+			return c.diffArrays(expectedValue, actualValue, path)
+		}
+	default:
+		if jsonScalarsEqual(expected, actual) {
+			return nil
+		}
+	}
+
+	return []models.AssertionFailure{{
+		Type:     models.AssertionJSONBody,
+		Expected: formatJSONValue(expected),
+		Actual:   formatJSONValue(actual),
+		Message:  fmt.Sprintf("%s: expected %s, got %s", formatJSONPath(path), formatJSONValue(expected), formatJSONValue(actual)),
+	}}
+}
+
+// diffObjects compares objects key by key, in key order
+func (c *jsonComparer) diffObjects(expected, actual map[string]any, path []jsonSegment) []models.AssertionFailure {
+	keys := slices.Collect(maps.Keys(expected))
+	for key := range actual {
+		if _, ok := expected[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+
+	var failures []models.AssertionFailure
+	for _, key := range keys {
+		keyPath := append(slices.Clip(path), jsonSegment{key: key, index: -1})
+		expectedValue, inExpected := expected[key]
+		actualValue, inActual := actual[key]
+		switch {
+		case inExpected && inActual:
+			failures = append(failures, c.diff(expectedValue, actualValue, keyPath)...)
+		case c.ignored(keyPath):
+		case inExpected:
+			failures = append(failures, missingJSONValue(keyPath, "field", expectedValue))
+		case !c.allowExtraFields:
+			failures = append(failures, unexpectedJSONValue(keyPath, "field", actualValue))
+		}
+	}
+	return failures
+}
+
+// diffArrays compares arrays element by element
+func (c *jsonComparer) diffArrays(expected, actual []any, path []jsonSegment) []models.AssertionFailure {
+	var failures []models.AssertionFailure
+	for i := 0; i < max(len(expected), len(actual)); i++ {
+		elementPath := append(slices.Clip(path), jsonSegment{index: i})
+		switch {
+		case i < len(expected) && i < len(actual):
+			failures = append(failures, c.diff(expected[i], actual[i], elementPath)...)
+		case c.ignored(elementPath):
+		case i < len(expected):
+			failures = append(failures, missingJSONValue(elementPath, "element", expected[i]))
+		default:
+			failures = append(failures, unexpectedJSONValue(elementPath, "element", actual[i]))
+		}
+	}
+	return failures
+}
+
+// diffUnordered matches every expected element to a distinct equal element
+// of actual, in any order
+func (c *jsonComparer) diffUnordered(expected, actual []any, path []jsonSegment) []models.AssertionFailure {
+	matched := make([]bool, len(actual))
+	var failures []models.AssertionFailure
+	for i, expectedValue := range expected {
+		elementPath := append(slices.Clip(path), jsonSegment{index: i})
+		found := false
+		for j, actualValue := range actual {
+			if !matched[j] && len(c.diff(expectedValue, actualValue, elementPath)) == 0 {
+				matched[j] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			failures = append(failures, missingJSONValue(path, "element", expectedValue))
+		}
+	}
+	for j, actualValue := range actual {
+		if !matched[j] {
+			failures = append(failures, unexpectedJSONValue(append(slices.Clip(path), jsonSegment{index: j}), "element", actualValue))
+		}
+	}
+	return failures
+}
+
+// missingJSONValue reports an expected field or element absent from the response
+func missingJSONValue(path []jsonSegment, kind string, expected any) models.AssertionFailure {
+	return models.AssertionFailure{
+		Type:     models.AssertionJSONBody,
+		Expected: formatJSONValue(expected),
+		Actual:   "missing",
+		Message:  fmt.Sprintf("%s: missing %s, expected %s", formatJSONPath(path), kind, formatJSONValue(expected)),
+	}
+}
+
+// unexpectedJSONValue reports a field or element the expected document lacks
+func unexpectedJSONValue(path []jsonSegment, kind string, actual any) models.AssertionFailure {
+	return models.AssertionFailure{
+		Type:     models.AssertionJSONBody,
+		Expected: "no " + kind,
+		Actual:   formatJSONValue(actual),
+		Message:  fmt.Sprintf("%s: unexpected %s, got %s", formatJSONPath(path), kind, formatJSONValue(actual)),
+	}
+}
+
+// jsonScalarsEqual compares strings, booleans, null and numbers, treating
+// numbers such as 1, 1.0 and 1e0 as equal
+func jsonScalarsEqual(expected, actual any) bool {
+	expectedNumber, ok := expected.(json.Number)
+	if !ok {
+		return expected == actual
+	}
+	actualNumber, ok := actual.(json.Number)
+	if !ok {
+		return false
+	}
+	x, okX := new(big.Rat).SetString(string(expectedNumber))
+	y, okY := new(big.Rat).SetString(string(actualNumber))
+	if !okX || !okY {
+		return expectedNumber == actualNumber
+	}
+	return x.Cmp(y) == 0
+}
+
+// formatJSONValue renders a value as compact JSON, shortened for display
+func formatJSONValue(value any) string {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return fmt.Sprint(value)
+	}
+	return excerpt(strings.TrimSuffix(buf.String(), "\n"), 0, 0)
+}
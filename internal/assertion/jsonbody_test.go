@@ -0,0 +1,196 @@
+package assertion
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"curlex/internal/models"
+)
+
+func TestJSONBodyValidator(t *testing.T) {
+	validator := &JSONBodyValidator{}
+
+	tests := []struct {
+		name     string
+		expected string
+		body     string
+		want     []string // Failure messages
+	}{
+		{
+			name:     "key order and whitespace",
+			expected: `{"id": 1, "name": "Alice"}`,
+			body:     "{\n  \"name\": \"Alice\",\n  \"id\": 1\n}",
+		},
+		{
+			name:     "number formatting",
+			expected: `{"price": 10, "ratio": 0.5, "big": 12345678901234567890}`,
+			body:     `{"price": 10.0, "ratio": 5e-1, "big": 12345678901234567890}`,
+		},
+		{
+			name:     "large numbers stay exact",
+			expected: `{"big": 12345678901234567890}`,
+			body:     `{"big": 12345678901234567891}`,
+			want:     []string{".big: expected 12345678901234567890, got 12345678901234567891"},
+		},
+		{
+			name:     "differing paths",
+			expected: `{"id": 1, "user": {"name": "Bob", "email": "bob@example.com"}, "tags": ["a", "b"]}`,
+			body:     `{"id": "1", "user": {"name": "Robert", "role": "admin"}, "tags": ["a", "b", "c"]}`,
+			want: []string{
+				`.id: expected 1, got "1"`,
+				`.tags[2]: unexpected element, got "c"`,
+				`.user.email: missing field, expected "bob@example.com"`,
+				`.user.name: expected "Bob", got "Robert"`,
+				`.user.role: unexpected field, got "admin"`,
+			},
+		},
+		{
+			name:     "type mismatch at root",
+			expected: `[1, 2]`,
+			body:     `{"items": [1, 2]}`,
+			want:     []string{`.: expected [1,2], got {"items":[1,2]}`},
+		},
+		{
+			name:     "missing array element",
+			expected: `[{"id": 1}, {"id": 2}]`,
+			body:     `[{"id": 1}]`,
+			want:     []string{`[1]: missing element, expected {"id":2}`},
+		},
+		{
+			name:     "null",
+			expected: `{"deleted_at": null}`,
+			body:     `{"deleted_at": "2024-01-01"}`,
+			want:     []string{`.deleted_at: expected null, got "2024-01-01"`},
+		},
+		{
+			name:     "unusual keys",
+			expected: `{"content-type": "json", "a.b": 1}`,
+			body:     `{"content-type": "xml", "a.b": 2}`,
+			want: []string{
+				`["a.b"]: expected 1, got 2`,
+				`.content-type: expected "json", got "xml"`,
+			},
+		},
+		{
+			name:     "ignore paths",
+			expected: `{"expected": {"id": 1, "name": "Alice", "created_at": "x", "items": [{"id": 1, "sku": "A"}]}, "ignore": [".id", ".created_at", ".items[*].id"]}`,
+			body:     `{"id": 99, "name": "Alice", "created_at": "2024-01-01", "items": [{"id": 7, "sku": "A"}]}`,
+		},
+		{
+			name:     "ignored fields may be absent",
+			expected: `{"expected": {"id": 1, "name": "Alice"}, "ignore": [".id", ".etag"]}`,
+			body:     `{"name": "Alice", "etag": "abc"}`,
+		},
+		{
+			name:     "quoted ignore path",
+			expected: `{"expected": {"headers": {"x-request-id": "a", "accept": "json"}}, "ignore": [".headers[\"x-request-id\"]"]}`,
+			body:     `{"headers": {"x-request-id": "b", "accept": "json"}}`,
+		},
+		{
+			name:     "wildcard key",
+			expected: `{"expected": {"users": {"alice": {"id": 1, "seen": "x"}, "bob": {"id": 2, "seen": "y"}}}, "ignore": [".users.*.seen"]}`,
+			body:     `{"users": {"alice": {"id": 1, "seen": "now"}, "bob": {"id": 2, "seen": "then"}}}`,
+		},
+		{
+			name:     "extra fields allowed",
+			expected: `{"expected": {"name": "Alice", "roles": [{"name": "admin"}]}, "allow_extra_fields": true}`,
+			body:     `{"id": 1, "name": "Alice", "roles": [{"name": "admin", "since": 2020}]}`,
+		},
+		{
+			name:     "extra fields allowed but not extra elements",
+			expected: `{"expected": {"roles": ["admin"]}, "allow_extra_fields": true}`,
+			body:     `{"roles": ["admin", "user"]}`,
+			want:     []string{`.roles[1]: unexpected element, got "user"`},
+		},
+		{
+			name:     "unordered arrays",
+			expected: `{"expected": {"tags": ["a", "b", "c"], "items": [{"id": 1}, {"id": 2}]}, "unordered_arrays": true}`,
+			body:     `{"tags": ["c", "a", "b"], "items": [{"id": 2}, {"id": 1}]}`,
+		},
+		{
+			name:     "unordered arrays with duplicates",
+			expected: `{"expected": ["a", "a", "b"], "unordered_arrays": true}`,
+			body:     `["a", "b", "b"]`,
+			want: []string{
+				`.: missing element, expected "a"`,
+				`[2]: unexpected element, got "b"`,
+			},
+		},
+		{
+			name:     "ordered arrays by default",
+			expected: `["a", "b"]`,
+			body:     `["b", "a"]`,
+			want: []string{
+				`[0]: expected "a", got "b"`,
+				`[1]: expected "b", got "a"`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failures := validator.ValidateAll(schemaResult(t.TempDir(), tt.body), models.Assertion{Type: models.AssertionJSONBody, Value: tt.expected})
+
+			var got []string
+			for _, failure := range failures {
+				got = append(got, failure.Message)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("failures = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONBodyValidator_Files(t *testing.T) {
+	validator := &JSONBodyValidator{}
+	dir := t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(dir, "user.json"), []byte(`{"id": 1, "name": "Alice"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "user.yaml"), []byte("id: 1\nname: Alice\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, value := range []string{"user.json", "user.yaml", `{"file": "user.json", "ignore": [".id"]}`} {
+		if failures := validator.ValidateAll(schemaResult(dir, `{"name": "Alice", "id": 1}`), models.Assertion{Value: value}); len(failures) != 0 {
+			t.Errorf("%s: expected no failures, got %v", value, failures)
+		}
+	}
+
+	failure := validator.Validate(schemaResult(dir, `{"name": "Bob", "id": 1}`), models.Assertion{Value: "user.json"})
+	if failure == nil || failure.Expected != `"Alice"` || failure.Actual != `"Bob"` {
+		t.Errorf("Expected name difference, got %v", failure)
+	}
+}
+
+func TestJSONBodyValidator_Errors(t *testing.T) {
+	validator := &JSONBodyValidator{}
+
+	tests := []struct {
+		name    string
+		value   string
+		body    string
+		wantErr string
+	}{
+		{"missing file", "missing.json", `{}`, "invalid json_body: failed to read expected body file"},
+		{"invalid expected", `{"id": `, `{}`, "invalid json_body: expected body is not valid JSON"},
+		{"invalid ignore path", `{"expected": {}, "ignore": ["id"]}`, `{}`, `invalid ignore path "id": must start with . or [`},
+		{"invalid ignore index", `{"expected": {}, "ignore": [".items[x]"]}`, `{}`, "invalid index [x]"},
+		{"body not json", `{"id": 1}`, `<html>`, "response body is not valid JSON"},
+		{"trailing data", `{"id": 1}`, `{"id": 1} {"id": 2}`, "unexpected data after the JSON document"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failures := validator.ValidateAll(schemaResult(t.TempDir(), tt.body), models.Assertion{Value: tt.value})
+			if len(failures) != 1 || !strings.Contains(failures[0].Message, tt.wantErr) {
+				t.Errorf("failures = %v, want %q", failures, tt.wantErr)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package assertion
 
 import (
 	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -12,15 +13,26 @@ import (
 // JSONPathValidator validates JSON path assertions
 type JSONPathValidator struct{}
 
+// lengthPipe matches a trailing "| length" on a path
+var lengthPipe = regexp.MustCompile(`\s*\|\s*length$`)
+
+// jsonPathExpression is a parsed JSON path assertion
+type jsonPathExpression struct {
+	path   string
+	length bool // Compare the length of the value instead of the value
+	cond   condition
+}
+
 // Validate checks if the JSON path expression evaluates to true
 func (v *JSONPathValidator) Validate(result *models.TestResult, assertion models.Assertion) *models.AssertionFailure {
-	// Parse the assertion: ".path operator value"
-	// Examples: ".data.id == 123", ".users[0].age > 18", ".active == true"
+	// Parse the assertion: ".path operator [value]"
+	// Examples: ".data.id == 123", ".users[0].age > 18", ".active == true",
+	// ".tags contains 'new'", ".error not exists", ".items | length >= 3"
 
 	expr := strings.TrimSpace(assertion.Value)
 
 	// Split into path and condition
-	path, operator, expectedValue, err := v.parseExpression(expr)
+	je, err := v.parseExpression(expr)
 	if err != nil {
 		return &models.AssertionFailure{
 			Type:    models.AssertionJSONPath,
@@ -29,28 +41,78 @@ func (v *JSONPathValidator) Validate(result *models.TestResult, assertion models
 	}
 
 	// Remove leading dot for gjson (it doesn't use dots at the beginning)
-	path = strings.TrimPrefix(path, ".")
+	path := strings.TrimPrefix(je.path, ".")
 
 	// Extract actual value from JSON using gjson
 	jsonResult := gjson.Get(result.ResponseBody, path)
 
+	return v.check(models.AssertionJSONPath, "JSON path", path, jsonResult.Value(), jsonResult.Exists(), je)
+}
+
+// check compares the value found at a path with the expression's condition
+// kind names the path syntax in messages, e.g. "JSON path"
+func (v *JSONPathValidator) check(assertionType models.AssertionType, kind, path string, actual any, found bool, je jsonPathExpression) *models.AssertionFailure {
+	// Absence is only a success when it was asserted
+	if je.cond.operator == "not exists" {
+		if found {
+			return &models.AssertionFailure{
+				Type:     assertionType,
+				Expected: fmt.Sprintf("path %q not to exist", path),
+				Actual:   fmt.Sprintf("%s = %s", path, describeValue(actual)),
+				Message:  fmt.Sprintf("%s %q should not exist: got %s", kind, path, describeValue(actual)),
+			}
+		}
+		return nil
+	}
+
 	// Check if path exists
-	if !jsonResult.Exists() {
+	if !found {
 		return &models.AssertionFailure{
-			Type:     models.AssertionJSONPath,
+			Type:     assertionType,
 			Expected: fmt.Sprintf("path %q to exist", path),
 			Actual:   "path does not exist",
-			Message:  fmt.Sprintf("JSON path %q not found", path),
+			Message:  fmt.Sprintf("%s %q not found", kind, path),
 		}
 	}
+	if je.cond.operator == "exists" {
+		return nil
+	}
+
+	subject, described := path, describeValue(actual)
+	if je.length {
+		subject = path + " | length"
+	}
+	condition := subject + " " + je.cond.String()
 
 	// Evaluate the condition
-	if !v.evaluateCondition(jsonResult, operator, expectedValue) {
+	value := actual
+	if je.length {
+		n, err := exprLen([]any{actual})
+		if err != nil {
+			return &models.AssertionFailure{
+				Type:     assertionType,
+				Expected: condition,
+				Actual:   fmt.Sprintf("%s = %s", path, described),
+				Message:  fmt.Sprintf("%s failed: length is not defined for %s", condition, typeName(actual)),
+			}
+		}
+		value, described = n, formatValue(n)
+	}
+	ok, err := je.cond.evaluate(value)
+	if err != nil {
 		return &models.AssertionFailure{
-			Type:     models.AssertionJSONPath,
-			Expected: fmt.Sprintf("%s %s %s", path, operator, expectedValue),
-			Actual:   fmt.Sprintf("%s = %v", path, jsonResult.Value()),
-			Message:  fmt.Sprintf("%s %s %s failed: got %v", path, operator, expectedValue, jsonResult.Value()),
+			Type:     assertionType,
+			Expected: condition,
+			Actual:   fmt.Sprintf("%s = %s", path, describeValue(actual)),
+			Message:  fmt.Sprintf("%s failed: %v", condition, err),
+		}
+	}
+	if !ok {
+		return &models.AssertionFailure{
+			Type:     assertionType,
+			Expected: condition,
+			Actual:   fmt.Sprintf("%s = %s", subject, described),
+			Message:  fmt.Sprintf("%s failed: got %s", condition, described),
 		}
 	}
 
@@ -58,114 +120,71 @@ func (v *JSONPathValidator) Validate(result *models.TestResult, assertion models
 }
 
 // parseExpression parses a JSON path expression
-// Format: ".path operator value"
-// Returns: path, operator, value, error
-func (v *JSONPathValidator) parseExpression(expr string) (string, string, string, error) {
-	// Operators in order of precedence (longest first to match correctly)
-	operators := []string{"==", "!=", ">=", "<=", ">", "<"}
-
-	for _, op := range operators {
-		if idx := strings.Index(expr, " "+op+" "); idx != -1 {
-			path := strings.TrimSpace(expr[:idx])
-			value := strings.TrimSpace(expr[idx+len(op)+2:])
-			return path, op, value, nil
+// Format: ".path operator [value]" or ".path | length operator value"
+// The operator is the first one outside brackets and quotes, so gjson
+// queries such as ".items.#(price > 10).name == 'x'" keep their operators
+func (v *JSONPathValidator) parseExpression(expr string) (jsonPathExpression, error) {
+	var je jsonPathExpression
+
+	idx, op := findJSONPathOperator(expr)
+	if op == "" {
+		return je, fmt.Errorf("no valid operator found in expression: %s", expr)
+	}
+	je.path = strings.TrimSpace(expr[:idx])
+	value := strings.TrimSpace(expr[idx+len(op):])
+
+	if loc := lengthPipe.FindStringIndex(je.path); loc != nil {
+		je.path = je.path[:loc[0]]
+		je.length = true
+		if !isComparison(op) {
+			return je, fmt.Errorf("| length expects a comparison, e.g. .items | length >= 3: %s", expr)
+		}
+		if _, err := strconv.Atoi(value); err != nil {
+			return je, fmt.Errorf("| length expects a whole number, got %q: %s", value, expr)
 		}
 	}
-
-	return "", "", "", fmt.Errorf("no valid operator found in expression: %s", expr)
-}
-
-// evaluateCondition evaluates a comparison between gjson result and expected value
-func (v *JSONPathValidator) evaluateCondition(actual gjson.Result, operator, expected string) bool {
-	// Handle different types
-	switch actual.Type {
-	case gjson.String:
-		return v.evaluateString(actual.String(), operator, expected)
-	case gjson.Number:
-		return v.evaluateNumber(actual.Float(), operator, expected)
-	case gjson.True, gjson.False:
-		return v.evaluateBool(actual.Bool(), operator, expected)
-	case gjson.Null:
-		return v.evaluateNull(operator, expected)
-	default:
-		// For complex types, convert to string
-		return v.evaluateString(actual.String(), operator, expected)
+	if je.path == "" {
+		return je, fmt.Errorf("missing path in expression: %s", expr)
 	}
-}
-
-// evaluateString compares string values
-func (v *JSONPathValidator) evaluateString(actual, operator, expected string) bool {
-	// Remove quotes from expected if present
-	expected = strings.Trim(expected, `"'`)
-
-	switch operator {
-	case "==":
-		return actual == expected
-	case "!=":
-		return actual != expected
-	case ">":
-		return actual > expected
-	case "<":
-		return actual < expected
-	case ">=":
-		return actual >= expected
-	case "<=":
-		return actual <= expected
-	default:
-		return false
+	if value == "" && op != "exists" && op != "not exists" {
+		return je, fmt.Errorf("missing value in expression: %s", expr)
 	}
-}
 
-// evaluateNumber compares numeric values
-func (v *JSONPathValidator) evaluateNumber(actual float64, operator, expected string) bool {
-	expectedNum, err := strconv.ParseFloat(expected, 64)
+	cond, err := parseCondition(op, value)
 	if err != nil {
-		return false
-	}
-
-	switch operator {
-	case "==":
-		return actual == expectedNum
-	case "!=":
-		return actual != expectedNum
-	case ">":
-		return actual > expectedNum
-	case "<":
-		return actual < expectedNum
-	case ">=":
-		return actual >= expectedNum
-	case "<=":
-		return actual <= expectedNum
-	default:
-		return false
+		return je, fmt.Errorf("%v: %s", err, expr)
 	}
+	je.cond = cond
+	return je, nil
 }
 
-// evaluateBool compares boolean values
-func (v *JSONPathValidator) evaluateBool(actual bool, operator, expected string) bool {
-	expected = strings.ToLower(strings.TrimSpace(expected))
-	expectedBool := expected == "true"
-
-	switch operator {
-	case "==":
-		return actual == expectedBool
-	case "!=":
-		return actual != expectedBool
-	default:
-		return false
-	}
-}
-
-// evaluateNull checks null conditions
-func (v *JSONPathValidator) evaluateNull(operator, expected string) bool {
-	expected = strings.ToLower(strings.TrimSpace(expected))
-
-	switch operator {
-	case "==":
-		return expected == "null"
-	case "!=":
-		return expected != "null"
-	default:
-		return false
+// findJSONPathOperator returns the position and text of the first operator
+// that follows a space outside brackets and quotes
+func findJSONPathOperator(expr string) (int, string) {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(expr); i++ {
+		c := expr[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '(' || c == '[' || c == '{':
+			depth++
+		case c == ')' || c == ']' || c == '}':
+			depth--
+		case c == ' ' && depth == 0:
+			rest := strings.TrimLeft(expr[i:], " ")
+			// Operators must not run into the value, e.g. "isx" or "==5"
+			if op, _, ok := cutOperator(rest); ok && (len(rest) == len(op) || rest[len(op)] == ' ') {
+				return len(expr) - len(rest), op
+			}
+		}
 	}
+	return -1, ""
 }
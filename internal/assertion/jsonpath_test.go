@@ -1,6 +1,7 @@
 package assertion
 
 import (
+	"strings"
 	"testing"
 
 	"curlex/internal/models"
@@ -193,3 +194,116 @@ func TestJSONPathValidator_NumberComparisons(t *testing.T) {
 		})
 	}
 }
+
+func TestJSONPathValidator_Operators(t *testing.T) {
+	validator := &JSONPathValidator{}
+
+	jsonBody := `{
+		"name": "Widget Pro",
+		"status": "active",
+		"count": 3,
+		"price": 9.5,
+		"tags": ["new", "sale", 42],
+		"owner": {"id": 7, "email": "ann@example.com"},
+		"notes": null,
+		"empty": [],
+		"items": [{"price": 5, "sku": "a"}, {"price": 15, "sku": "b"}]
+	}`
+
+	tests := []struct {
+		name       string
+		expr       string
+		shouldPass bool
+	}{
+		{"string contains", ".name contains Pro", true},
+		{"string contains quoted", ".name contains 'get P'", true},
+		{"string does not contain", ".name contains Lite", false},
+		{"array contains string", ".tags contains 'sale'", true},
+		{"array contains number", ".tags contains 42", true},
+		{"array missing element", ".tags contains old", false},
+		{"object has key", ".owner contains email", true},
+		{"not contains", ".tags not contains old", true},
+		{"not contains fails", ".name not contains Widget", false},
+		{"matches", ".owner.email matches /^[a-z]+@example\\.com$/", true},
+		{"matches bare pattern", ".status matches ^act", true},
+		{"matches number", ".price matches ^9\\.", true},
+		{"matches fails", ".status matches /^inactive$/", false},
+		{"in list", ".status in [active, pending]", true},
+		{"in quoted list", `.name in ["Widget Pro", "Widget"]`, true},
+		{"in number list", ".count in [1, 2, 3]", true},
+		{"not in list", ".status in [deleted, archived]", false},
+		{"exists", ".owner.id exists", true},
+		{"null exists", ".notes exists", true},
+		{"exists fails", ".owner.phone exists", false},
+		{"not exists", ".error not exists", true},
+		{"not exists fails", ".owner not exists", false},
+		{"is string", ".name is string", true},
+		{"is number", ".price is number", true},
+		{"is array", ".tags is array", true},
+		{"is object", ".owner is object", true},
+		{"is null", ".notes is null", true},
+		{"is wrong type", ".count is string", false},
+		{"array length", ".tags | length == 3", true},
+		{"array length compare", ".items | length >= 3", false},
+		{"empty array length", ".empty | length == 0", true},
+		{"object length", ".owner | length == 2", true},
+		{"string length", ".status | length < 10", true},
+		{"gjson query with operator", ".items.#(price > 10).sku == 'b'", true},
+		{"array equality", `.tags == ["new", "sale", 42]`, true},
+		{"object equality ignores key order", `.owner == {"email": "ann@example.com", "id": 7}`, true},
+		{"object inequality", `.owner != {"id": 8}`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := &models.TestResult{
+				ResponseBody: jsonBody,
+			}
+			assertion := models.Assertion{
+				Type:  models.AssertionJSONPath,
+				Value: tt.expr,
+			}
+
+			failure := validator.Validate(result, assertion)
+
+			if tt.shouldPass && failure != nil {
+				t.Errorf("Expected to pass, but failed: %v", failure)
+			}
+			if !tt.shouldPass && failure == nil {
+				t.Errorf("Expected to fail, but passed")
+			}
+		})
+	}
+}
+
+func TestJSONPathValidator_OperatorErrors(t *testing.T) {
+	validator := &JSONPathValidator{}
+	result := &models.TestResult{ResponseBody: `{"count": 3, "name": "x"}`}
+
+	tests := []struct {
+		name    string
+		expr    string
+		wantMsg string
+	}{
+		{"invalid regex", ".name matches /[/", "invalid expression: invalid regex"},
+		{"unknown type", ".name is text", `invalid expression: unknown type "text"`},
+		{"in without list", ".name in a, b", "invalid expression: in expects a list"},
+		{"exists with value", ".name exists yes", "invalid expression: exists takes no value"},
+		{"length without comparison", ".name | length contains 2", "invalid expression: | length expects a comparison"},
+		{"length of number", ".count | length > 1", "length is not defined for number"},
+		{"contains on number", ".count contains 3", "contains is not defined for number"},
+		{"missing value", ".name ==", "invalid expression: missing value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failure := validator.Validate(result, models.Assertion{Type: models.AssertionJSONPath, Value: tt.expr})
+			if failure == nil {
+				t.Fatal("Expected failure, got nil")
+			}
+			if !strings.Contains(failure.Message, tt.wantMsg) {
+				t.Errorf("Message = %q, want it to contain %q", failure.Message, tt.wantMsg)
+			}
+		})
+	}
+}
@@ -0,0 +1,222 @@
+package assertion
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"curlex/internal/models"
+	"curlex/internal/parser"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"gopkg.in/yaml.v3"
+)
+
+// schemaPrinter renders schema violations in English
+var schemaPrinter = message.NewPrinter(language.English)
+
+// JSONSchemaValidator validates response bodies against JSON Schemas
+// Schemas are compiled on first use and cached for the lifetime of the engine,
+// so tests and retries sharing a schema only compile it once
+type JSONSchemaValidator struct {
+	mu       sync.Mutex
+	compiler *jsonschema.Compiler
+	schemas  map[string]*jsonschema.Schema // Keyed by absolute schema location
+}
+
+// Validate checks if the response body is valid against the schema
+// Only the first violation is returned; the engine uses ValidateAll
+func (v *JSONSchemaValidator) Validate(result *models.TestResult, assertion models.Assertion) *models.AssertionFailure {
+	failures := v.ValidateAll(result, assertion)
+	if len(failures) == 0 {
+		return nil
+	}
+	return &failures[0]
+}
+
+// ValidateAll checks the response body against the schema and reports every
+// violation with the JSON pointer of the offending value
+func (v *JSONSchemaValidator) ValidateAll(result *models.TestResult, assertion models.Assertion) []models.AssertionFailure {
+	schema, err := v.schema(result.Test.BaseDir, assertion.Value)
+	if err != nil {
+		return []models.AssertionFailure{{
+			Type:    models.AssertionJSONSchema,
+			Message: fmt.Sprintf("invalid schema: %v", err),
+		}}
+	}
+
+	body, err := jsonschema.UnmarshalJSON(strings.NewReader(result.ResponseBody))
+	if err != nil {
+		return []models.AssertionFailure{{
+			Type:     models.AssertionJSONSchema,
+			Expected: "JSON body",
+			Actual:   excerpt(result.ResponseBody, 0, 0),
+			Message:  fmt.Sprintf("response body is not valid JSON: %v", err),
+		}}
+	}
+
+	err = schema.Validate(body)
+	if err == nil {
+		return nil // Success
+	}
+
+	var failures []models.AssertionFailure
+	for _, violation := range SchemaViolations(err) {
+		failures = append(failures, models.AssertionFailure{
+			Type:     models.AssertionJSONSchema,
+			Expected: fmt.Sprintf("%s to satisfy %s", violation.Pointer, violation.Keyword),
+			Actual:   violation.Reason,
+			Message:  fmt.Sprintf("%s: %s", violation.Pointer, violation.Reason),
+		})
+	}
+	return failures
+}
+
+// schema returns the compiled schema for an inline schema or a schema file,
+// compiling it on first use
+func (v *JSONSchemaValidator) schema(baseDir, value string) (*jsonschema.Schema, error) {
+	value = strings.TrimSpace(value)
+
+	// Inline schemas get a location next to the test file so relative
+	// $refs resolve the same way as for schema files
+	var location string
+	var doc []byte
+	if strings.HasPrefix(value, "{") {
+		sum := sha256.Sum256([]byte(value))
+		location = parser.ResolvePath(baseDir, "inline-schema-"+hex.EncodeToString(sum[:8])+".json")
+		doc = []byte(value)
+	} else {
+		location = parser.ResolvePath(baseDir, value)
+	}
+	location, err := filepath.Abs(location)
+	if err != nil {
+		return nil, err
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if schema, ok := v.schemas[location]; ok {
+		return schema, nil
+	}
+
+	if v.compiler == nil {
+		v.compiler = jsonschema.NewCompiler()
+		// Schemas without $schema are treated as Draft 2020-12
+		v.compiler.DefaultDraft(jsonschema.Draft2020)
+		v.schemas = make(map[string]*jsonschema.Schema)
+	}
+
+	// YAML schema files are converted to JSON; JSON files are loaded by the compiler
+	if doc == nil && (strings.HasSuffix(location, ".yaml") || strings.HasSuffix(location, ".yml")) {
+		doc, err = yamlToJSON(location)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if doc != nil {
+		parsed, err := jsonschema.UnmarshalJSON(bytes.NewReader(doc))
+		if err != nil {
+			return nil, fmt.Errorf("schema is not valid JSON: %w", err)
+		}
+		if err := v.compiler.AddResource(location, parsed); err != nil {
+			return nil, err
+		}
+	}
+
+	schema, err := v.compiler.Compile(location)
+	if err != nil {
+		return nil, err
+	}
+	v.schemas[location] = schema
+	return schema, nil
+}
+
+// yamlToJSON reads a YAML schema file and re-encodes it as JSON
+func yamlToJSON(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema file: %w", err)
+	}
+	var doc any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse schema file: %w", err)
+	}
+	return json.Marshal(doc)
+}
+
+// SchemaViolation is a single reason a value failed schema validation
+type SchemaViolation struct {
+	Pointer string // JSON pointer of the offending value, e.g. "/items/0/id"
+	Keyword string // Schema location of the failed keyword, e.g. "#/properties/id/type"
+	Reason  string // e.g. "got string, want integer"
+}
+
+// SchemaViolations flattens a validation error from a compiled schema into
+// its leaf causes, ordered by the location of the offending value
+func SchemaViolations(err error) []SchemaViolation {
+	validationErr, ok := err.(*jsonschema.ValidationError)
+	if !ok {
+		return []SchemaViolation{{Pointer: "/", Reason: err.Error()}}
+	}
+
+	var violations []SchemaViolation
+	for _, leaf := range schemaLeaves(validationErr) {
+		violations = append(violations, SchemaViolation{
+			Pointer: jsonPointer(leaf.InstanceLocation),
+			Keyword: schemaKeyword(leaf),
+			Reason:  leaf.ErrorKind.LocalizedString(schemaPrinter),
+		})
+	}
+	sort.SliceStable(violations, func(i, j int) bool {
+		return violations[i].Pointer < violations[j].Pointer
+	})
+	return violations
+}
+
+// schemaLeaves returns the causes of a validation error that have no causes
+// of their own
+func schemaLeaves(err *jsonschema.ValidationError) []*jsonschema.ValidationError {
+	if len(err.Causes) == 0 {
+		return []*jsonschema.ValidationError{err}
+	}
+
+	var leaves []*jsonschema.ValidationError
+	for _, cause := range err.Causes {
+		leaves = append(leaves, schemaLeaves(cause)...)
+	}
+	return leaves
+}
+
+// schemaKeyword returns the schema location of the keyword that failed,
+// e.g. "#/properties/id/type"
+func schemaKeyword(err *jsonschema.ValidationError) string {
+	_, fragment, _ := strings.Cut(err.SchemaURL, "#")
+	for _, token := range err.ErrorKind.KeywordPath() {
+		fragment += "/" + token
+	}
+	return "#" + fragment
+}
+
+// jsonPointer renders path tokens as an RFC 6901 JSON pointer
+// The root is shown as "/" rather than an empty string
+func jsonPointer(tokens []string) string {
+	if len(tokens) == 0 {
+		return "/"
+	}
+	var sb strings.Builder
+	for _, token := range tokens {
+		token = strings.ReplaceAll(token, "~", "~0")
+		token = strings.ReplaceAll(token, "/", "~1")
+		sb.WriteString("/" + token)
+	}
+	return sb.String()
+}
@@ -0,0 +1,170 @@
+package assertion
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"curlex/internal/models"
+)
+
+const userSchema = `{
+  "type": "object",
+  "required": ["id", "name", "tags"],
+  "additionalProperties": false,
+  "properties": {
+    "id": {"type": "integer"},
+    "name": {"type": "string", "minLength": 1},
+    "tags": {"type": "array", "items": {"type": "string"}}
+  }
+}`
+
+func schemaResult(dir, body string) *models.TestResult {
+	return &models.TestResult{
+		Test:         models.Test{BaseDir: dir},
+		ResponseBody: body,
+	}
+}
+
+func TestJSONSchemaValidator_Inline(t *testing.T) {
+	validator := &JSONSchemaValidator{}
+	assertion := models.Assertion{Type: models.AssertionJSONSchema, Value: userSchema}
+
+	if failures := validator.ValidateAll(schemaResult(t.TempDir(), `{"id": 1, "name": "Ann", "tags": ["a"]}`), assertion); len(failures) != 0 {
+		t.Fatalf("Expected no failures, got %v", failures)
+	}
+
+	failures := validator.ValidateAll(schemaResult(t.TempDir(), `{"id": "1", "name": "", "tags": ["a", 2], "extra": true}`), assertion)
+	want := []string{"/", "/id", "/name", "/tags/1"}
+	if len(failures) != len(want) {
+		t.Fatalf("Expected %d failures, got %d: %v", len(want), len(failures), failures)
+	}
+	for i, pointer := range want {
+		if !strings.HasPrefix(failures[i].Message, pointer+": ") {
+			t.Errorf("failure %d = %q, want pointer %s", i, failures[i].Message, pointer)
+		}
+	}
+	if !strings.Contains(failures[0].Message, "extra") {
+		t.Errorf("Expected the unexpected property to be named, got %q", failures[0].Message)
+	}
+	if failures[1].Expected != "/id to satisfy #/properties/id/type" {
+		t.Errorf("Expected = %q", failures[1].Expected)
+	}
+}
+
+func TestJSONSchemaValidator_Drafts(t *testing.T) {
+	dir := t.TempDir()
+
+	// prefixItems is unknown to Draft-07 and ignored, while Draft 2020-12
+	// applies it, so the same body validates differently
+	draft7 := `{"$schema": "http://json-schema.org/draft-07/schema#", "type": "array", "prefixItems": [{"type": "integer"}]}`
+	draft2020 := `{"$schema": "https://json-schema.org/draft/2020-12/schema", "type": "array", "prefixItems": [{"type": "integer"}]}`
+	body := `["not a number"]`
+
+	validator := &JSONSchemaValidator{}
+	if failures := validator.ValidateAll(schemaResult(dir, body), models.Assertion{Value: draft7}); len(failures) != 0 {
+		t.Errorf("Draft-07: expected prefixItems to be ignored, got %v", failures)
+	}
+	if failures := validator.ValidateAll(schemaResult(dir, body), models.Assertion{Value: draft2020}); len(failures) != 1 {
+		t.Errorf("Draft 2020-12: expected 1 failure, got %v", failures)
+	}
+}
+
+func TestJSONSchemaValidator_Files(t *testing.T) {
+	dir := t.TempDir()
+	schemas := filepath.Join(dir, "schemas")
+	if err := os.Mkdir(schemas, 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"user.json":    `{"type": "object", "properties": {"address": {"$ref": "address.json"}}}`,
+		"address.json": `{"type": "object", "required": ["city"]}`,
+		"user.yaml":    "type: object\nrequired: [id]\nproperties:\n  id:\n    type: integer\n    minimum: 1\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(schemas, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		schema   string
+		body     string
+		failures int
+	}{
+		{"json file with relative ref", "schemas/user.json", `{"address": {"city": "Oslo"}}`, 0},
+		{"json file ref violation", "schemas/user.json", `{"address": {}}`, 1},
+		{"yaml file", "schemas/user.yaml", `{"id": 3}`, 0},
+		{"yaml file violation", "schemas/user.yaml", `{"id": 0}`, 1},
+		{"inline ref to file", `{"$ref": "schemas/address.json"}`, `{"city": 1}`, 0},
+		{"inline ref violation", `{"$ref": "schemas/address.json"}`, `{}`, 1},
+	}
+
+	validator := &JSONSchemaValidator{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failures := validator.ValidateAll(schemaResult(dir, tt.body), models.Assertion{Value: tt.schema})
+			if len(failures) != tt.failures {
+				t.Errorf("Expected %d failures, got %v", tt.failures, failures)
+			}
+		})
+	}
+}
+
+func TestJSONSchemaValidator_CachesCompiledSchemas(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "schema.json")
+	if err := os.WriteFile(path, []byte(`{"type": "integer"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	validator := &JSONSchemaValidator{}
+	assertion := models.Assertion{Value: "schema.json"}
+	if failures := validator.ValidateAll(schemaResult(dir, `1`), assertion); len(failures) != 0 {
+		t.Fatalf("Expected no failures, got %v", failures)
+	}
+
+	// A compiled schema is reused, so later edits to the file are not seen
+	if err := os.WriteFile(path, []byte(`{"type": "string"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if failures := validator.ValidateAll(schemaResult(dir, `2`), assertion); len(failures) != 0 {
+		t.Errorf("Expected cached schema to be used, got %v", failures)
+	}
+	validator.ValidateAll(schemaResult(dir, `3`), models.Assertion{Value: `{"type": "integer"}`})
+	validator.ValidateAll(schemaResult(dir, `4`), models.Assertion{Value: `{"type": "integer"}`})
+	if len(validator.schemas) != 2 {
+		t.Errorf("Expected 2 compiled schemas, got %d", len(validator.schemas))
+	}
+}
+
+func TestJSONSchemaValidator_Errors(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		schema  string
+		body    string
+		wantErr string
+	}{
+		{"body not json", `{"type": "object"}`, `<html>`, "response body is not valid JSON"},
+		{"inline schema not json", `{"type": `, `{}`, "invalid schema"},
+		{"missing file", "missing.json", `{}`, "invalid schema"},
+		{"invalid schema", `{"type": "widget"}`, `{}`, "invalid schema"},
+	}
+
+	validator := &JSONSchemaValidator{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failure := validator.Validate(schemaResult(dir, tt.body), models.Assertion{Value: tt.schema})
+			if failure == nil {
+				t.Fatal("Expected failure, got success")
+			}
+			if !strings.Contains(failure.Message, tt.wantErr) {
+				t.Errorf("Message = %q, want it to contain %q", failure.Message, tt.wantErr)
+			}
+		})
+	}
+}
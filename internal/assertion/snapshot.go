@@ -0,0 +1,391 @@
+package assertion
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"curlex/internal/models"
+	"github.com/tidwall/gjson"
+	"gopkg.in/yaml.v3"
+)
+
+// snapshotMask replaces masked values in snapshots
+const snapshotMask = "<masked>"
+
+// SnapshotStore validates responses against snapshots recorded under a
+// __snapshots__ directory next to the test file
+// A missing snapshot is recorded and passes; with update set, every
+// snapshot is rewritten from the current response
+type SnapshotStore struct {
+	suitePath string // Test file; snapshots go in __snapshots__/<file name>/
+	update    bool
+
+	mu      sync.Mutex
+	written []string
+}
+
+// NewSnapshotStore creates a snapshot store for a test file
+func NewSnapshotStore(suitePath string, update bool) *SnapshotStore {
+	return &SnapshotStore{suitePath: suitePath, update: update}
+}
+
+// Validate checks the response against its snapshot
+// Only the first difference is returned; the engine uses ValidateAll
+func (s *SnapshotStore) Validate(result *models.TestResult, assertion models.Assertion) *models.AssertionFailure {
+	failures := s.ValidateAll(result, assertion)
+	if len(failures) == 0 {
+		return nil
+	}
+	return &failures[0]
+}
+
+// ValidateAll compares the response against its snapshot and reports the
+// status, each recorded header and each body difference separately
+func (s *SnapshotStore) ValidateAll(result *models.TestResult, assertion models.Assertion) []models.AssertionFailure {
+	options, err := models.ParseSnapshot(assertion.Value)
+	if err != nil {
+		return []models.AssertionFailure{{
+			Type:    models.AssertionSnapshot,
+			Message: fmt.Sprintf("invalid snapshot: %v", err),
+		}}
+	}
+
+	current, err := newSnapshotRecord(result, options)
+	if err != nil {
+		return []models.AssertionFailure{{
+			Type:    models.AssertionSnapshot,
+			Message: fmt.Sprintf("invalid snapshot: %v", err),
+		}}
+	}
+
+	name := models.SnapshotName(result.Test, options)
+	path := filepath.Join(s.dir(result.Test.BaseDir), name+".snap")
+
+	data, err := os.ReadFile(path)
+	if s.update || errors.Is(err, fs.ErrNotExist) {
+		if err := s.write(path, data, current); err != nil {
+			return []models.AssertionFailure{{
+				Type:    models.AssertionSnapshot,
+				Message: fmt.Sprintf("failed to write snapshot: %v", err),
+			}}
+		}
+		return nil // Recorded
+	}
+	if err != nil {
+		return []models.AssertionFailure{{
+			Type:    models.AssertionSnapshot,
+			Message: fmt.Sprintf("failed to read snapshot: %v", err),
+		}}
+	}
+
+	var stored snapshotRecord
+	if err := yaml.Unmarshal(data, &stored); err != nil {
+		return []models.AssertionFailure{{
+			Type:    models.AssertionSnapshot,
+			Message: fmt.Sprintf("invalid snapshot %s: %v", path, err),
+		}}
+	}
+	return stored.diff(name, current)
+}
+
+// Written returns the snapshot files recorded or rewritten so far, sorted
+func (s *SnapshotStore) Written() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	written := slices.Clone(s.written)
+	sort.Strings(written)
+	return written
+}
+
+// dir returns the directory holding the test file's snapshots
+func (s *SnapshotStore) dir(baseDir string) string {
+	if s.suitePath == "" {
+		return filepath.Join(baseDir, "__snapshots__")
+	}
+	return SnapshotDir(s.suitePath)
+}
+
+// write records a snapshot unless the file already holds the same content
+func (s *SnapshotStore) write(path string, existing []byte, record snapshotRecord) error {
+	data, err := record.encode()
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(data, existing) {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.written = append(s.written, path)
+	s.mu.Unlock()
+	return nil
+}
+
+// SnapshotDir returns the directory holding a test file's snapshots,
+// e.g. "api/__snapshots__/users" for "api/users.yaml"
+func SnapshotDir(suitePath string) string {
+	name := strings.TrimSuffix(filepath.Base(suitePath), filepath.Ext(suitePath))
+	return filepath.Join(filepath.Dir(suitePath), "__snapshots__", name)
+}
+
+// ObsoleteSnapshots returns the snapshot files of a test file that none of
+// its tests would record
+func ObsoleteSnapshots(suitePath string, suite *models.TestSuite) ([]string, error) {
+	used := make(map[string]bool)
+	for _, tests := range [][]models.Test{suite.Setup, suite.Tests, suite.Teardown} {
+		for _, test := range tests {
+			for _, a := range test.Assertions {
+				if a.Type != models.AssertionSnapshot {
+					continue
+				}
+				// Invalid options are reported when the test runs
+				options, _ := models.ParseSnapshot(a.Value)
+				used[models.SnapshotName(test, options)+".snap"] = true
+			}
+		}
+	}
+
+	entries, err := os.ReadDir(SnapshotDir(suitePath))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var obsolete []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".snap") && !used[entry.Name()] {
+			obsolete = append(obsolete, filepath.Join(SnapshotDir(suitePath), entry.Name()))
+		}
+	}
+	return obsolete, nil
+}
+
+// snapshotRecord is the normalized response stored in a snapshot file
+type snapshotRecord struct {
+	Status  int               `yaml:"status"`
+	Headers map[string]string `yaml:"headers,omitempty"`
+	Body    string            `yaml:"body"`
+}
+
+// newSnapshotRecord normalizes a response: volatile values are masked and
+// JSON bodies are indented with sorted keys, so formatting changes don't
+// show up as differences
+func newSnapshotRecord(result *models.TestResult, options models.Snapshot) (snapshotRecord, error) {
+	patterns := make([]*regexp.Regexp, 0, len(options.MaskRegex))
+	for _, pattern := range options.MaskRegex {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return snapshotRecord{}, fmt.Errorf("invalid mask_regex %q: %w", pattern, err)
+		}
+		patterns = append(patterns, re)
+	}
+	mask := func(s string) string {
+		for _, re := range patterns {
+			s = re.ReplaceAllLiteralString(s, snapshotMask)
+		}
+		return s
+	}
+
+	record := snapshotRecord{Status: result.StatusCode}
+
+	headers := options.Headers
+	if headers == nil {
+		headers = models.DefaultSnapshotHeaders
+	}
+	for _, name := range headers {
+		if values := result.Headers.Values(name); len(values) > 0 {
+			if record.Headers == nil {
+				record.Headers = make(map[string]string)
+			}
+			record.Headers[http.CanonicalHeaderKey(name)] = mask(strings.Join(values, ", "))
+		}
+	}
+
+	body := result.ResponseBody
+	if len(options.Mask) > 0 {
+		if !gjson.Valid(body) {
+			return snapshotRecord{}, fmt.Errorf("mask requires a JSON response body")
+		}
+		masked, err := maskJSONPaths(body, options.Mask)
+		if err != nil {
+			return snapshotRecord{}, err
+		}
+		body = masked
+	}
+	record.Body = formatSnapshotBody(mask(body))
+	return record, nil
+}
+
+// maskJSONPaths replaces the values at gjson paths with snapshotMask
+// Paths that match nothing are skipped, since volatile fields may be absent
+func maskJSONPaths(body string, paths []string) (string, error) {
+	type span struct{ start, end int }
+	var spans []span
+
+	for _, path := range paths {
+		result := gjson.Get(body, strings.TrimPrefix(path, "."))
+		matches := []gjson.Result{result}
+		if result.Indexes != nil {
+			// Queries such as "items.#.id" match one value per element
+			matches = result.Array()
+			for i := range matches {
+				matches[i].Index = result.Indexes[i]
+			}
+		}
+		for _, match := range matches {
+			if !match.Exists() {
+				continue
+			}
+			end := match.Index + len(match.Raw)
+			if end > len(body) || body[match.Index:end] != match.Raw {
+				return "", fmt.Errorf("cannot mask %s: path does not refer to a value in the body", path)
+			}
+			spans = append(spans, span{match.Index, end})
+		}
+	}
+
+	// Replace from the end so earlier offsets stay valid, skipping values
+	// inside a value that is masked as a whole
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+	var kept []span
+	for _, sp := range spans {
+		if len(kept) > 0 && sp.start < kept[len(kept)-1].end {
+			continue
+		}
+		kept = append(kept, sp)
+	}
+	for i := len(kept) - 1; i >= 0; i-- {
+		body = body[:kept[i].start] + `"` + snapshotMask + `"` + body[kept[i].end:]
+	}
+	return body, nil
+}
+
+// formatSnapshotBody indents JSON bodies with sorted keys; other bodies are
+// kept as they are
+func formatSnapshotBody(body string) string {
+	doc, err := decodeJSON([]byte(body))
+	if err != nil {
+		return body
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return body
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// encode renders the record as a snapshot file
+func (r snapshotRecord) encode() ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(r); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// diff reports each difference between the stored record and the current response
+func (r snapshotRecord) diff(name string, current snapshotRecord) []models.AssertionFailure {
+	var failures []models.AssertionFailure
+	fail := func(subject, expected, actual string) {
+		failures = append(failures, models.AssertionFailure{
+			Type:     models.AssertionSnapshot,
+			Expected: expected,
+			Actual:   actual,
+			Message:  fmt.Sprintf("snapshot %s: %s: expected %s, got %s", name, subject, expected, actual),
+		})
+	}
+
+	if r.Status != current.Status {
+		fail("status", fmt.Sprint(r.Status), fmt.Sprint(current.Status))
+	}
+
+	headerNames := slices.Collect(maps.Keys(r.Headers))
+	for key := range current.Headers {
+		if _, ok := r.Headers[key]; !ok {
+			headerNames = append(headerNames, key)
+		}
+	}
+	sort.Strings(headerNames)
+	for _, key := range headerNames {
+		if r.Headers[key] != current.Headers[key] {
+			fail("header "+key, quoteOrMissing(r.Headers, key), quoteOrMissing(current.Headers, key))
+		}
+	}
+
+	if r.Body == current.Body {
+		return failures
+	}
+
+	// JSON bodies are compared structurally so each differing path is listed
+	expected, expectedErr := decodeJSON([]byte(r.Body))
+	actual, actualErr := decodeJSON([]byte(current.Body))
+	if expectedErr == nil && actualErr == nil {
+		for _, failure := range (&jsonComparer{}).diff(expected, actual, nil) {
+			failure.Type = models.AssertionSnapshot
+			failure.Message = fmt.Sprintf("snapshot %s: body %s", name, failure.Message)
+			failures = append(failures, failure)
+		}
+		return failures
+	}
+
+	line, expectedLine, actualLine := firstDifferentLine(r.Body, current.Body)
+	fail(fmt.Sprintf("body line %d", line), expectedLine, actualLine)
+	return failures
+}
+
+// quoteOrMissing quotes a header value, or returns "missing"
+func quoteOrMissing(headers map[string]string, key string) string {
+	value, ok := headers[key]
+	if !ok {
+		return "missing"
+	}
+	return fmt.Sprintf("%q", value)
+}
+
+// firstDifferentLine returns the 1-based number and quoted contents of the
+// first line that differs between two texts
+func firstDifferentLine(expected, actual string) (int, string, string) {
+	expectedLines := strings.Split(expected, "\n")
+	actualLines := strings.Split(actual, "\n")
+	quote := func(lines []string, i int) string {
+		if i >= len(lines) {
+			return "end of body"
+		}
+		return fmt.Sprintf("%q", excerpt(lines[i], 0, 0))
+	}
+
+	i := 0
+	for i < len(expectedLines) && i < len(actualLines) && expectedLines[i] == actualLines[i] {
+		i++
+	}
+	return i + 1, quote(expectedLines, i), quote(actualLines, i)
+}
@@ -0,0 +1,209 @@
+package assertion
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"curlex/internal/models"
+)
+
+func snapshotResult(dir string, status int, contentType, body string) *models.TestResult {
+	return &models.TestResult{
+		Test:         models.Test{Name: "Get user", BaseDir: dir},
+		StatusCode:   status,
+		Headers:      http.Header{"Content-Type": {contentType}, "Date": {"Mon, 01 Jan 2024 00:00:00 GMT"}},
+		ResponseBody: body,
+	}
+}
+
+func TestSnapshotStore_RecordAndCompare(t *testing.T) {
+	dir := t.TempDir()
+	suitePath := filepath.Join(dir, "users.yaml")
+	assertion := models.Assertion{Type: models.AssertionSnapshot, Value: "true"}
+
+	// The first run records the snapshot and passes
+	store := NewSnapshotStore(suitePath, false)
+	if failures := store.ValidateAll(snapshotResult(dir, 200, "application/json", `{"name":"Alice","tags":["a"],"id":1}`), assertion); len(failures) != 0 {
+		t.Fatalf("Expected first run to pass, got %v", failures)
+	}
+	path := filepath.Join(dir, "__snapshots__", "users", "get-user.snap")
+	if got := store.Written(); !reflect.DeepEqual(got, []string{path}) {
+		t.Fatalf("Written() = %v, want %v", got, []string{path})
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `status: 200
+headers:
+  Content-Type: application/json
+body: |-
+  {
+    "id": 1,
+    "name": "Alice",
+    "tags": [
+      "a"
+    ]
+  }
+`
+	if string(data) != want {
+		t.Errorf("snapshot file =\n%s\nwant\n%s", data, want)
+	}
+
+	// Formatting differences are normalized away
+	store = NewSnapshotStore(suitePath, false)
+	if failures := store.ValidateAll(snapshotResult(dir, 200, "application/json", "{\n\"id\": 1.0, \"tags\": [\"a\"], \"name\": \"Alice\"}"), assertion); len(failures) != 0 {
+		t.Errorf("Expected reformatted body to match, got %v", failures)
+	}
+	if len(store.Written()) != 0 {
+		t.Errorf("Expected nothing written, got %v", store.Written())
+	}
+
+	// Each difference is reported
+	failures := store.ValidateAll(snapshotResult(dir, 404, "text/plain", `{"name":"Bob","tags":["a","b"],"id":1}`), assertion)
+	var got []string
+	for _, failure := range failures {
+		got = append(got, failure.Message)
+	}
+	wantMessages := []string{
+		"snapshot get-user: status: expected 200, got 404",
+		`snapshot get-user: header Content-Type: expected "application/json", got "text/plain"`,
+		`snapshot get-user: body .name: expected "Alice", got "Bob"`,
+		`snapshot get-user: body .tags[1]: unexpected element, got "b"`,
+	}
+	if !reflect.DeepEqual(got, wantMessages) {
+		t.Errorf("failures = %q, want %q", got, wantMessages)
+	}
+
+	// Update mode rewrites the snapshot
+	store = NewSnapshotStore(suitePath, true)
+	if failures := store.ValidateAll(snapshotResult(dir, 404, "text/plain", `{"name":"Bob"}`), assertion); len(failures) != 0 {
+		t.Fatalf("Expected update to pass, got %v", failures)
+	}
+	if len(store.Written()) != 1 {
+		t.Errorf("Expected the snapshot to be rewritten, got %v", store.Written())
+	}
+	store = NewSnapshotStore(suitePath, false)
+	if failures := store.ValidateAll(snapshotResult(dir, 404, "text/plain", `{"name":"Bob"}`), assertion); len(failures) != 0 {
+		t.Errorf("Expected updated snapshot to match, got %v", failures)
+	}
+}
+
+func TestSnapshotStore_TextBody(t *testing.T) {
+	dir := t.TempDir()
+	assertion := models.Assertion{Type: models.AssertionSnapshot, Value: "page"}
+	store := NewSnapshotStore(filepath.Join(dir, "pages.yaml"), false)
+
+	if failures := store.ValidateAll(snapshotResult(dir, 200, "text/html", "<h1>Hi</h1>\n<p>one</p>\n"), assertion); len(failures) != 0 {
+		t.Fatalf("Expected first run to pass, got %v", failures)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "__snapshots__", "pages", "page.snap")); err != nil {
+		t.Fatalf("Expected snapshot named after the assertion: %v", err)
+	}
+
+	failures := store.ValidateAll(snapshotResult(dir, 200, "text/html", "<h1>Hi</h1>\n<p>two</p>\n"), assertion)
+	if len(failures) != 1 || failures[0].Message != `snapshot page: body line 2: expected "<p>one</p>", got "<p>two</p>"` {
+		t.Errorf("failures = %v", failures)
+	}
+}
+
+func TestSnapshotStore_Masks(t *testing.T) {
+	dir := t.TempDir()
+	store := NewSnapshotStore(filepath.Join(dir, "masks.yaml"), false)
+	assertion := models.Assertion{
+		Type:  models.AssertionSnapshot,
+		Value: `{"headers": ["Content-Type", "Date"], "mask": [".id", ".items.#.created_at", ".meta"], "mask_regex": ["\\d{2}:\\d{2}:\\d{2}", "req-[0-9a-f]+"]}`,
+	}
+
+	first := `{"id": 7, "request": "req-1a2b", "items": [{"sku": "A", "created_at": "x"}, {"sku": "B", "created_at": "y"}], "meta": {"page": 1}}`
+	if failures := store.ValidateAll(snapshotResult(dir, 200, "application/json", first), assertion); len(failures) != 0 {
+		t.Fatalf("Expected first run to pass, got %v", failures)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "__snapshots__", "masks", "get-user.snap"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`Date: Mon, 01 Jan 2024 <masked> GMT`, `"id": "<masked>"`, `"created_at": "<masked>"`, `"meta": "<masked>"`, `"request": "<masked>"`, `"sku": "B"`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("Expected %q in snapshot:\n%s", want, data)
+		}
+	}
+
+	result := snapshotResult(dir, 200, "application/json", `{"id": 8, "request": "req-ffff", "items": [{"sku": "A", "created_at": "z"}, {"sku": "B"}], "meta": {"page": 2}}`)
+	result.Headers.Set("Date", "Mon, 01 Jan 2024 10:11:12 GMT")
+	failures := store.ValidateAll(result, assertion)
+	if len(failures) != 1 || failures[0].Message != `snapshot get-user: body .items[1].created_at: missing field, expected "<masked>"` {
+		t.Errorf("Expected only the missing field to differ, got %v", failures)
+	}
+}
+
+func TestSnapshotStore_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		body    string
+		wantErr string
+	}{
+		{"invalid options", `{"masks": [".id"]}`, `{}`, "invalid snapshot options"},
+		{"invalid regex", `{"mask_regex": ["("]}`, `{}`, "invalid mask_regex"},
+		{"mask on text body", `{"mask": [".id"]}`, `plain text`, "mask requires a JSON response body"},
+		{"false", "false", `{}`, "snapshot cannot be false"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			store := NewSnapshotStore(filepath.Join(dir, "errors.yaml"), false)
+			failures := store.ValidateAll(snapshotResult(dir, 200, "application/json", tt.body), models.Assertion{Value: tt.value})
+			if len(failures) != 1 || !strings.Contains(failures[0].Message, tt.wantErr) {
+				t.Errorf("failures = %v, want %q", failures, tt.wantErr)
+			}
+			if len(store.Written()) != 0 {
+				t.Errorf("Expected nothing written, got %v", store.Written())
+			}
+		})
+	}
+}
+
+func TestObsoleteSnapshots(t *testing.T) {
+	dir := t.TempDir()
+	suitePath := filepath.Join(dir, "users.yaml")
+	snapshots := SnapshotDir(suitePath)
+
+	// No snapshot directory yet
+	suite := &models.TestSuite{}
+	if obsolete, err := ObsoleteSnapshots(suitePath, suite); err != nil || obsolete != nil {
+		t.Fatalf("ObsoleteSnapshots() = %v, %v; want nil", obsolete, err)
+	}
+
+	if err := os.MkdirAll(snapshots, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"get-user.snap", "user-list.snap", "setup-login.snap", "removed-test.snap", "notes.txt"} {
+		if err := os.WriteFile(filepath.Join(snapshots, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	suite = &models.TestSuite{
+		Setup: []models.Test{{Name: "Setup login", Assertions: []models.Assertion{{Type: models.AssertionSnapshot, Value: "true"}}}},
+		Tests: []models.Test{
+			{Name: "Get user", Assertions: []models.Assertion{{Type: models.AssertionStatus, Value: "200"}, {Type: models.AssertionSnapshot, Value: "true"}}},
+			{Name: "List users", Assertions: []models.Assertion{{Type: models.AssertionSnapshot, Value: `{"name": "user list"}`}}},
+			{Name: "Removed test", Assertions: []models.Assertion{{Type: models.AssertionStatus, Value: "200"}}},
+		},
+	}
+	obsolete, err := ObsoleteSnapshots(suitePath, suite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{filepath.Join(snapshots, "removed-test.snap")}; !reflect.DeepEqual(obsolete, want) {
+		t.Errorf("ObsoleteSnapshots() = %v, want %v", obsolete, want)
+	}
+}
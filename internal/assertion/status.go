@@ -2,31 +2,15 @@ package assertion
 
 import (
 	"fmt"
-	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 
 	"curlex/internal/models"
 )
 
-// Pre-compiled regex patterns for status code comparisons
-var (
-	// "operator number" patterns (actual is left operand)
-	statusPatternGTE = regexp.MustCompile(`^\s*>=\s*(\d+)`)
-	statusPatternLTE = regexp.MustCompile(`^\s*<=\s*(\d+)`)
-	statusPatternGT  = regexp.MustCompile(`^\s*>\s*(\d+)`)
-	statusPatternLT  = regexp.MustCompile(`^\s*<\s*(\d+)`)
-	statusPatternEQ  = regexp.MustCompile(`^\s*==\s*(\d+)`)
-	statusPatternNEQ = regexp.MustCompile(`^\s*!=\s*(\d+)`)
-
-	// "number operator number" patterns
-	statusPatternNumGTE = regexp.MustCompile(`(\d+)\s*>=\s*(\d+)`)
-	statusPatternNumLTE = regexp.MustCompile(`(\d+)\s*<=\s*(\d+)`)
-	statusPatternNumGT  = regexp.MustCompile(`(\d+)\s*>\s*(\d+)`)
-	statusPatternNumLT  = regexp.MustCompile(`(\d+)\s*<\s*(\d+)`)
-	statusPatternNumEQ  = regexp.MustCompile(`(\d+)\s*==\s*(\d+)`)
-	statusPatternNumNEQ = regexp.MustCompile(`(\d+)\s*!=\s*(\d+)`)
-)
+// statusNames lists the names available to status expressions
+var statusNames = map[string]bool{"status": true}
 
 // StatusValidator validates HTTP status code assertions
 type StatusValidator struct{}
@@ -51,7 +35,14 @@ func (v *StatusValidator) Validate(result *models.TestResult, assertion models.A
 
 	// Check if it's an expression (e.g., ">= 200 && < 300")
 	if v.isExpression(expected) {
-		if v.evaluateExpression(expected, actual) {
+		ok, err := v.evaluateExpression(expected, actual)
+		if err != nil {
+			return &models.AssertionFailure{
+				Type:    models.AssertionStatus,
+				Message: fmt.Sprintf("invalid expression: %v", err),
+			}
+		}
+		if ok {
 			return nil // Success
 		}
 		return &models.AssertionFailure{
@@ -71,7 +62,7 @@ func (v *StatusValidator) Validate(result *models.TestResult, assertion models.A
 
 // isExpression checks if the status assertion is an expression
 func (v *StatusValidator) isExpression(s string) bool {
-	operators := []string{">=", "<=", "!=", "==", ">", "<", "&&", "||"}
+	operators := []string{">=", "<=", "!=", "==", ">", "<", "&&", "||", "in "}
 	for _, op := range operators {
 		if strings.Contains(s, op) {
 			return true
@@ -81,132 +72,58 @@ func (v *StatusValidator) isExpression(s string) bool {
 }
 
 // evaluateExpression evaluates a status code expression
-// Supports: ==, !=, >, <, >=, <=, &&, ||
-func (v *StatusValidator) evaluateExpression(expr string, actual int) bool {
-	// Replace 'status' variable with actual value only if present
-	if strings.Contains(expr, "status") {
-		expr = strings.ReplaceAll(expr, "status", strconv.Itoa(actual))
-	}
-
-	// Handle compound expressions with && and ||
-	if strings.Contains(expr, "&&") {
-		parts := strings.Split(expr, "&&")
-		for _, part := range parts {
-			if !v.evaluateSingleExpression(strings.TrimSpace(part), actual) {
-				return false
-			}
-		}
-		return true
-	}
-
-	if strings.Contains(expr, "||") {
-		parts := strings.Split(expr, "||")
-		for _, part := range parts {
-			if v.evaluateSingleExpression(strings.TrimSpace(part), actual) {
-				return true
-			}
-		}
-		return false
-	}
-
-	// Single expression
-	return v.evaluateSingleExpression(expr, actual)
+// Comparisons without a left operand apply to the status code, so
+// ">= 200 && < 300", "status == 200 || status == 204" and
+// "(>= 200 && < 300) || == 404" are all valid
+func (v *StatusValidator) evaluateExpression(expr string, actual int) (bool, error) {
+	node, err := parseExpression(expr, statusNames, "status")
+	if err != nil {
+		return false, err
+	}
+	// An expression that never looks at the status, e.g. "200 >= 200",
+	// would pass or fail for every response
+	if !refersTo(node, "status") {
+		return false, fmt.Errorf("expression does not use the status code, e.g. >= 200 or status == 204")
+	}
+
+	evaluator := &exprEvaluator{scope: statusScope(actual)}
+	value, err := evaluator.evaluate(node)
+	if err != nil {
+		return false, err
+	}
+	ok, isBool := value.(bool)
+	if !isBool {
+		return false, fmt.Errorf("expression must be true or false, got %s", typeName(value))
+	}
+	return ok, nil
 }
 
-// evaluateSingleExpression evaluates a single comparison
-func (v *StatusValidator) evaluateSingleExpression(expr string, actual int) bool {
-	// Pattern can be:
-	// 1. "number operator number" e.g., "200 >= 200", "404 != 200"
-	// 2. "operator number" e.g., ">= 200", "< 300" (actual is implicit left operand)
-
-	// Try pattern: "operator number" (actual is left operand)
-	// >= operator
-	if match := v.extractComparisonRe(expr, statusPatternGTE); match != nil {
-		right, _ := strconv.Atoi(match[1])
-		return actual >= right
-	}
-
-	// <= operator
-	if match := v.extractComparisonRe(expr, statusPatternLTE); match != nil {
-		right, _ := strconv.Atoi(match[1])
-		return actual <= right
-	}
-
-	// > operator
-	if match := v.extractComparisonRe(expr, statusPatternGT); match != nil {
-		right, _ := strconv.Atoi(match[1])
-		return actual > right
-	}
-
-	// < operator
-	if match := v.extractComparisonRe(expr, statusPatternLT); match != nil {
-		right, _ := strconv.Atoi(match[1])
-		return actual < right
-	}
-
-	// == operator
-	if match := v.extractComparisonRe(expr, statusPatternEQ); match != nil {
-		right, _ := strconv.Atoi(match[1])
-		return actual == right
-	}
-
-	// != operator
-	if match := v.extractComparisonRe(expr, statusPatternNEQ); match != nil {
-		right, _ := strconv.Atoi(match[1])
-		return actual != right
-	}
-
-	// Try pattern: "number operator number"
-	// >= operator
-	if match := v.extractComparisonRe(expr, statusPatternNumGTE); match != nil {
-		left, _ := strconv.Atoi(match[1])
-		right, _ := strconv.Atoi(match[2])
-		return left >= right
-	}
-
-	// <= operator
-	if match := v.extractComparisonRe(expr, statusPatternNumLTE); match != nil {
-		left, _ := strconv.Atoi(match[1])
-		right, _ := strconv.Atoi(match[2])
-		return left <= right
-	}
-
-	// > operator
-	if match := v.extractComparisonRe(expr, statusPatternNumGT); match != nil {
-		left, _ := strconv.Atoi(match[1])
-		right, _ := strconv.Atoi(match[2])
-		return left > right
-	}
-
-	// < operator
-	if match := v.extractComparisonRe(expr, statusPatternNumLT); match != nil {
-		left, _ := strconv.Atoi(match[1])
-		right, _ := strconv.Atoi(match[2])
-		return left < right
-	}
-
-	// == operator
-	if match := v.extractComparisonRe(expr, statusPatternNumEQ); match != nil {
-		left, _ := strconv.Atoi(match[1])
-		right, _ := strconv.Atoi(match[2])
-		return left == right
+// refersTo reports whether an expression uses the identifier name, either
+// explicitly or as the implicit subject of a comparison such as ">= 200"
+func refersTo(node exprNode, name string) bool {
+	switch n := node.(type) {
+	case identNode:
+		return n.name == name
+	case memberNode:
+		return refersTo(n.object, name)
+	case indexNode:
+		return refersTo(n.object, name) || refersTo(n.index, name)
+	case callNode:
+		return slices.ContainsFunc(n.args, func(arg exprNode) bool { return refersTo(arg, name) })
+	case listNode:
+		return slices.ContainsFunc(n.items, func(item exprNode) bool { return refersTo(item, name) })
+	case unaryNode:
+		return refersTo(n.operand, name)
+	case binaryNode:
+		return refersTo(n.left, name) || refersTo(n.right, name)
 	}
-
-	// != operator
-	if match := v.extractComparisonRe(expr, statusPatternNumNEQ); match != nil {
-		left, _ := strconv.Atoi(match[1])
-		right, _ := strconv.Atoi(match[2])
-		return left != right
-	}
-
 	return false
 }
 
-// extractComparisonRe extracts comparison operands using pre-compiled regex
-func (v *StatusValidator) extractComparisonRe(expr string, re *regexp.Regexp) []string {
-	matches := re.FindStringSubmatch(expr)
-	if len(matches) >= 2 {
-		return matches
-	}
-	return nil
+// statusScope resolves "status" to the response status code
+type statusScope int
+
+// lookup returns the status code
+func (s statusScope) lookup(name string) (any, error) {
+	return float64(s), nil
 }
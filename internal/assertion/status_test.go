@@ -1,6 +1,7 @@
 package assertion
 
 import (
+	"strings"
 	"testing"
 
 	"curlex/internal/models"
@@ -60,10 +61,56 @@ func TestStatusValidator_Expression(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.expr, func(t *testing.T) {
-			result := validator.evaluateExpression(tt.expr, tt.actual)
+			result, err := validator.evaluateExpression(tt.expr, tt.actual)
+			if err != nil {
+				t.Fatalf("evaluateExpression(%q) error = %v", tt.expr, err)
+			}
+			if result != tt.expected {
+				t.Errorf("evaluateExpression(%q, %d) = %v, want %v", tt.expr, tt.actual, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestStatusValidator_CompoundExpressions(t *testing.T) {
+	validator := &StatusValidator{}
+
+	tests := []struct {
+		expr     string
+		actual   int
+		expected bool
+	}{
+		{">= 200 && < 300 || == 404", 404, true},
+		{">= 200 && < 300 || == 404", 500, false},
+		{"== 404 || >= 200 && < 300", 201, true},
+		{"(>= 200 && < 300) || (>= 400 && < 500)", 418, true},
+		{"!(>= 500)", 503, false},
+		{"status == 200 || status == 204", 204, true},
+		{"in [200, 201, 204]", 201, true},
+		{"status % 100 == 4", 404, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			result, err := validator.evaluateExpression(tt.expr, tt.actual)
+			if err != nil {
+				t.Fatalf("evaluateExpression(%q) error = %v", tt.expr, err)
+			}
 			if result != tt.expected {
 				t.Errorf("evaluateExpression(%q, %d) = %v, want %v", tt.expr, tt.actual, result, tt.expected)
 			}
 		})
 	}
+
+	// Expressions must look at the status, or they could never fail
+	for _, expr := range []string{"200 >= 200", "1 == 1 || 2 > 3"} {
+		if _, err := validator.evaluateExpression(expr, 500); err == nil || !strings.Contains(err.Error(), "does not use the status code") {
+			t.Errorf("evaluateExpression(%q) error = %v, want status code error", expr, err)
+		}
+	}
+
+	failure := validator.Validate(&models.TestResult{StatusCode: 200}, models.Assertion{Type: models.AssertionStatus, Value: ">= 200 && (< 300"})
+	if failure == nil || !strings.HasPrefix(failure.Message, "invalid expression:") {
+		t.Errorf("Expected invalid expression failure, got %v", failure)
+	}
 }
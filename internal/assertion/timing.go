@@ -19,7 +19,7 @@ func (v *ResponseTimeValidator) Validate(result *models.TestResult, assertion mo
 	expr := strings.TrimSpace(assertion.Value)
 
 	// Parse expression
-	operator, duration, err := v.parseExpression(expr)
+	operator, duration, err := parseDurationCondition(expr)
 	if err != nil {
 		return &models.AssertionFailure{
 			Type:    models.AssertionResponseTime,
@@ -30,7 +30,7 @@ func (v *ResponseTimeValidator) Validate(result *models.TestResult, assertion mo
 	actual := result.ResponseTime
 
 	// Evaluate the condition
-	if !v.evaluateCondition(actual, operator, duration) {
+	if !compareDurations(actual, operator, duration) {
 		return &models.AssertionFailure{
 			Type:     models.AssertionResponseTime,
 			Expected: fmt.Sprintf("%s %s", operator, duration),
@@ -42,10 +42,10 @@ func (v *ResponseTimeValidator) Validate(result *models.TestResult, assertion mo
 	return nil // Success
 }
 
-// parseExpression parses a response time expression
+// parseDurationCondition parses a response time or timing phase expression
 // Format: "operator duration" (e.g., "< 500ms", "<= 2s")
 // Returns: operator, duration, error
-func (v *ResponseTimeValidator) parseExpression(expr string) (string, time.Duration, error) {
+func parseDurationCondition(expr string) (string, time.Duration, error) {
 	// Pattern: operator + duration
 	// Examples: "< 500ms", "<= 2s", "> 100ms"
 
@@ -67,7 +67,7 @@ func (v *ResponseTimeValidator) parseExpression(expr string) (string, time.Durat
 	}
 
 	// Parse duration
-	duration, err := v.parseDuration(durationStr)
+	duration, err := parseDuration(durationStr)
 	if err != nil {
 		return "", 0, fmt.Errorf("invalid duration %q: %w", durationStr, err)
 	}
@@ -76,7 +76,7 @@ func (v *ResponseTimeValidator) parseExpression(expr string) (string, time.Durat
 }
 
 // parseDuration parses duration strings like "500ms", "2s", "1000ms"
-func (v *ResponseTimeValidator) parseDuration(s string) (time.Duration, error) {
+func parseDuration(s string) (time.Duration, error) {
 	// Pattern: number + unit
 	pattern := regexp.MustCompile(`^(\d+(?:\.\d+)?)(ms|s|m|h)$`)
 	matches := pattern.FindStringSubmatch(s)
@@ -106,8 +106,24 @@ func (v *ResponseTimeValidator) parseDuration(s string) (time.Duration, error) {
 	}
 }
 
-// evaluateCondition evaluates a comparison between actual and expected durations
-func (v *ResponseTimeValidator) evaluateCondition(actual time.Duration, operator string, expected time.Duration) bool {
+// daysPattern matches durations given in days, e.g. "14d"
+var daysPattern = regexp.MustCompile(`^(\d+(?:\.\d+)?)d$`)
+
+// parseLongDuration parses durations like "14d" as well as the units
+// accepted by parseDuration, for certificate and cookie lifetimes
+func parseLongDuration(s string) (time.Duration, error) {
+	if matches := daysPattern.FindStringSubmatch(s); matches != nil {
+		days, err := strconv.ParseFloat(matches[1], 64)
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(days * float64(24*time.Hour)), nil
+	}
+	return parseDuration(s)
+}
+
+// compareDurations evaluates a comparison between actual and expected durations
+func compareDurations(actual time.Duration, operator string, expected time.Duration) bool {
 	switch operator {
 	case "<":
 		return actual < expected
@@ -125,3 +141,66 @@ func (v *ResponseTimeValidator) evaluateCondition(actual time.Duration, operator
 		return false
 	}
 }
+
+// TimingPhaseValidator validates assertions on a single phase of the request
+// timing breakdown, such as ttfb or tls_handshake
+type TimingPhaseValidator struct{}
+
+// Validate checks if the timing phase named by the assertion type meets the assertion
+func (v *TimingPhaseValidator) Validate(result *models.TestResult, assertion models.Assertion) *models.AssertionFailure {
+	expr := strings.TrimSpace(assertion.Value)
+
+	operator, duration, err := parseDurationCondition(expr)
+	if err != nil {
+		return &models.AssertionFailure{
+			Type:    assertion.Type,
+			Message: fmt.Sprintf("invalid expression: %v", err),
+		}
+	}
+
+	if result.Timing == nil {
+		return &models.AssertionFailure{
+			Type:     assertion.Type,
+			Expected: fmt.Sprintf("%s %s", operator, duration),
+			Message:  "timing breakdown not available",
+		}
+	}
+
+	actual, ok := timingPhase(result.Timing, assertion.Type)
+	if !ok {
+		return &models.AssertionFailure{
+			Type:    assertion.Type,
+			Message: fmt.Sprintf("unknown timing phase: %s", assertion.Type),
+		}
+	}
+
+	if !compareDurations(actual, operator, duration) {
+		phase := strings.ReplaceAll(string(assertion.Type), "_", " ")
+		return &models.AssertionFailure{
+			Type:     assertion.Type,
+			Expected: fmt.Sprintf("%s %s", operator, duration),
+			Actual:   actual.String(),
+			Message:  fmt.Sprintf("%s %s does not satisfy %s %s", phase, actual, operator, duration),
+		}
+	}
+
+	return nil // Success
+}
+
+// timingPhase returns the duration of the phase matching the assertion type
+func timingPhase(timing *models.Timing, assertionType models.AssertionType) (time.Duration, bool) {
+	switch assertionType {
+	case models.AssertionDNSLookup:
+		return timing.DNSLookup, true
+	case models.AssertionTCPConnect:
+		return timing.TCPConnect, true
+	case models.AssertionTLSHandshake:
+		return timing.TLSHandshake, true
+	case models.AssertionTTFB:
+		return timing.TTFB, true
+	case models.AssertionContentTransfer:
+		return timing.ContentTransfer, true
+	default:
+		return 0, false
+	}
+}
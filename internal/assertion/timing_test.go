@@ -1,6 +1,7 @@
 package assertion
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -211,3 +212,56 @@ func TestResponseTimeValidator_AllOperators(t *testing.T) {
 		})
 	}
 }
+
+func TestTimingPhaseValidator(t *testing.T) {
+	timing := &models.Timing{
+		DNSLookup:       5 * time.Millisecond,
+		TCPConnect:      10 * time.Millisecond,
+		TLSHandshake:    40 * time.Millisecond,
+		TTFB:            150 * time.Millisecond,
+		ContentTransfer: 20 * time.Millisecond,
+		Total:           170 * time.Millisecond,
+	}
+
+	tests := []struct {
+		name        string
+		timing      *models.Timing
+		assertType  models.AssertionType
+		value       string
+		shouldFail  bool
+		wantMessage string
+	}{
+		{"dns lookup passes", timing, models.AssertionDNSLookup, "< 10ms", false, ""},
+		{"tcp connect passes", timing, models.AssertionTCPConnect, "<= 10ms", false, ""},
+		{"tls handshake passes", timing, models.AssertionTLSHandshake, "< 100ms", false, ""},
+		{"tls handshake fails", timing, models.AssertionTLSHandshake, "< 20ms", true, "tls handshake 40ms does not satisfy < 20ms"},
+		{"ttfb passes", timing, models.AssertionTTFB, "< 200ms", false, ""},
+		{"ttfb fails", timing, models.AssertionTTFB, "< 100ms", true, "ttfb 150ms does not satisfy < 100ms"},
+		{"content transfer passes", timing, models.AssertionContentTransfer, "< 1s", false, ""},
+		{"invalid expression", timing, models.AssertionTTFB, "fast", true, "invalid expression"},
+		{"no timing recorded", nil, models.AssertionTTFB, "< 200ms", true, "timing breakdown not available"},
+	}
+
+	validator := &TimingPhaseValidator{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := &models.TestResult{Timing: tt.timing}
+			failure := validator.Validate(result, models.Assertion{Type: tt.assertType, Value: tt.value})
+
+			if tt.shouldFail && failure == nil {
+				t.Fatal("Expected failure, got none")
+			}
+			if !tt.shouldFail && failure != nil {
+				t.Fatalf("Expected no failure, got: %v", failure.Message)
+			}
+			if failure != nil {
+				if failure.Type != tt.assertType {
+					t.Errorf("Failure type = %s, want %s", failure.Type, tt.assertType)
+				}
+				if !strings.Contains(failure.Message, tt.wantMessage) {
+					t.Errorf("Failure message = %q, want it to contain %q", failure.Message, tt.wantMessage)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package assertion
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"curlex/internal/models"
+)
+
+// TLSValidator validates assertions on the TLS connection and server certificate
+type TLSValidator struct{}
+
+// Validate checks if the response's TLS connection meets the assertion
+func (v *TLSValidator) Validate(result *models.TestResult, assertion models.Assertion) *models.AssertionFailure {
+	// Parse the assertion: "field operator value"
+	// Examples: "expires_in > 14d", "issuer contains Let's Encrypt", "version >= 1.2"
+	expr := strings.TrimSpace(assertion.Value)
+
+	field, cond, err := v.parseExpression(expr)
+	if err != nil {
+		return &models.AssertionFailure{
+			Type:    models.AssertionTLS,
+			Message: fmt.Sprintf("invalid expression: %v", err),
+		}
+	}
+
+	if result.TLS == nil {
+		return &models.AssertionFailure{
+			Type:     models.AssertionTLS,
+			Expected: expr,
+			Actual:   "no TLS connection",
+			Message:  fmt.Sprintf("%s failed: response was not received over TLS", expr),
+		}
+	}
+
+	var actual string
+	var ok bool
+	switch field {
+	case "expires_in":
+		expiresIn := result.TLS.ExpiresIn(time.Now())
+		actual = formatDays(expiresIn)
+		ok, err = cond.evaluate(expiresIn)
+	case "version":
+		actual = result.TLS.Version
+		ok, err = cond.evaluate(actual)
+	case "cipher":
+		actual = result.TLS.CipherSuite
+		ok, err = cond.evaluate(actual)
+	case "subject":
+		actual = result.TLS.Subject
+		ok, err = cond.evaluate(actual)
+	case "issuer":
+		actual = result.TLS.Issuer
+		ok, err = cond.evaluate(actual)
+	case "san":
+		actual = strings.Join(result.TLS.DNSNames, ", ")
+		ok = sanContains(result.TLS.DNSNames, describeValue(cond.expected))
+	}
+
+	if err != nil {
+		return &models.AssertionFailure{
+			Type:     models.AssertionTLS,
+			Expected: fmt.Sprintf("%s %s", field, cond),
+			Actual:   fmt.Sprintf("%s = %s", field, actual),
+			Message:  fmt.Sprintf("%s %s failed: %v", field, cond, err),
+		}
+	}
+
+	if !ok {
+		return &models.AssertionFailure{
+			Type:     models.AssertionTLS,
+			Expected: fmt.Sprintf("%s %s", field, cond),
+			Actual:   fmt.Sprintf("%s = %s", field, actual),
+			Message:  fmt.Sprintf("%s %s failed: got %s", field, cond, actual),
+		}
+	}
+
+	return nil // Success
+}
+
+// parseExpression parses a TLS assertion expression
+// Format: "field operator value"
+// Expiry values are durations such as "14d" and versions are numbers
+func (v *TLSValidator) parseExpression(expr string) (string, condition, error) {
+	field, rest, found := strings.Cut(expr, " ")
+	if !found {
+		return "", condition{}, fmt.Errorf("expected \"field operator value\", got: %s", expr)
+	}
+
+	op, value, ok := cutOperator(strings.TrimSpace(rest))
+	if !ok {
+		return "", condition{}, fmt.Errorf("no valid operator found in expression: %s", expr)
+	}
+	if value == "" {
+		return "", condition{}, fmt.Errorf("missing value in expression: %s", expr)
+	}
+	if field == "version" {
+		value = strings.TrimPrefix(value, "TLS ")
+	}
+	cond, err := parseCondition(op, value)
+	if err != nil {
+		return "", condition{}, err
+	}
+
+	switch field {
+	case "expires_in":
+		if !isComparison(op) {
+			return "", condition{}, fmt.Errorf("expires_in does not support %s", op)
+		}
+		if text, ok := cond.expected.(string); ok {
+			duration, err := parseLongDuration(text)
+			if err != nil {
+				return "", condition{}, fmt.Errorf("invalid duration %q: %w", text, err)
+			}
+			cond.expected = duration
+		}
+		if _, ok := cond.expected.(time.Duration); !ok {
+			return "", condition{}, fmt.Errorf("invalid duration %q: expected e.g. 14d or 12h", value)
+		}
+	case "version":
+		if !isComparison(op) {
+			return "", condition{}, fmt.Errorf("version does not support %s", op)
+		}
+		if _, ok := cond.expected.(float64); !ok {
+			return "", condition{}, fmt.Errorf("invalid TLS version %q: expected e.g. 1.2", value)
+		}
+	case "cipher", "subject", "issuer":
+		switch op {
+		case "==", "!=", "contains", "not contains", "matches", "in":
+		default:
+			return "", condition{}, fmt.Errorf("operator %s is not supported for text fields", op)
+		}
+	case "san":
+		if op != "contains" {
+			return "", condition{}, fmt.Errorf("san only supports contains")
+		}
+	default:
+		return "", condition{}, fmt.Errorf("unknown field %q: expected expires_in, version, cipher, subject, issuer or san", field)
+	}
+
+	return field, cond, nil
+}
+
+// sanContains reports whether a certificate's DNS names cover hostname,
+// allowing a wildcard to match a single leftmost label
+func sanContains(dnsNames []string, hostname string) bool {
+	hostname = strings.ToLower(hostname)
+	for _, name := range dnsNames {
+		name = strings.ToLower(name)
+		if name == hostname {
+			return true
+		}
+		if suffix, ok := strings.CutPrefix(name, "*."); ok {
+			label, rest, found := strings.Cut(hostname, ".")
+			if found && label != "" && rest == suffix {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// formatDays renders a duration until expiry in days
+func formatDays(d time.Duration) string {
+	return fmt.Sprintf("%.1fd", d.Hours()/24)
+}
@@ -0,0 +1,104 @@
+package assertion
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"curlex/internal/models"
+)
+
+func TestTLSValidator_Validate(t *testing.T) {
+	result := &models.TestResult{
+		TLS: &models.TLSInfo{
+			Version:     "1.3",
+			CipherSuite: "TLS_AES_128_GCM_SHA256",
+			Subject:     "api.example.com",
+			Issuer:      "Example Intermediate CA",
+			DNSNames:    []string{"api.example.com", "*.cdn.example.com"},
+			NotBefore:   time.Now().Add(-60 * 24 * time.Hour),
+			NotAfter:    time.Now().Add(30 * 24 * time.Hour),
+		},
+	}
+
+	tests := []struct {
+		name       string
+		value      string
+		shouldFail bool
+	}{
+		{"expires after 14 days", "expires_in > 14d", false},
+		{"expires within 60 days", "expires_in < 60d", false},
+		{"expires before 45 days fails", "expires_in > 45d", true},
+		{"expires in hours", "expires_in > 48h", false},
+		{"version at least 1.2", "version >= 1.2", false},
+		{"version exact", "version == 1.3", false},
+		{"version below 1.3 fails", "version < 1.3", true},
+		{"cipher contains", "cipher contains GCM", false},
+		{"subject equals", "subject == api.example.com", false},
+		{"subject mismatch fails", "subject == www.example.com", true},
+		{"issuer contains quoted", "issuer contains 'Intermediate'", false},
+		{"issuer not equal", "issuer != Other CA", false},
+		{"san exact", "san contains api.example.com", false},
+		{"san wildcard", "san contains img.cdn.example.com", false},
+		{"san wildcard single label", "san contains a.b.cdn.example.com", true},
+		{"san missing fails", "san contains www.example.com", true},
+	}
+
+	validator := &TLSValidator{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failure := validator.Validate(result, models.Assertion{Type: models.AssertionTLS, Value: tt.value})
+			if tt.shouldFail && failure == nil {
+				t.Error("Expected failure, got success")
+			}
+			if !tt.shouldFail && failure != nil {
+				t.Errorf("Expected success, got failure: %s", failure.Message)
+			}
+		})
+	}
+}
+
+func TestTLSValidator_InvalidExpression(t *testing.T) {
+	result := &models.TestResult{
+		TLS: &models.TLSInfo{Version: "1.3", NotAfter: time.Now().Add(time.Hour)},
+	}
+
+	tests := []struct {
+		name    string
+		value   string
+		wantMsg string
+	}{
+		{"unknown field", "serial == 1", "unknown field"},
+		{"missing operator", "expires_in 14d", "no valid operator"},
+		{"bad duration", "expires_in > two weeks", "invalid duration"},
+		{"ordering on text", "issuer > A", "not supported"},
+		{"san equality", "san == example.com", "san only supports contains"},
+	}
+
+	validator := &TLSValidator{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failure := validator.Validate(result, models.Assertion{Type: models.AssertionTLS, Value: tt.value})
+			if failure == nil {
+				t.Fatal("Expected failure for invalid expression")
+			}
+			if !strings.Contains(failure.Message, tt.wantMsg) {
+				t.Errorf("Message = %q, want %q", failure.Message, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestTLSValidator_PlainHTTP(t *testing.T) {
+	validator := &TLSValidator{}
+	failure := validator.Validate(&models.TestResult{StatusCode: 200}, models.Assertion{
+		Type:  models.AssertionTLS,
+		Value: "expires_in > 14d",
+	})
+	if failure == nil {
+		t.Fatal("Expected failure for a response without TLS")
+	}
+	if !strings.Contains(failure.Message, "not received over TLS") {
+		t.Errorf("Message = %q", failure.Message)
+	}
+}
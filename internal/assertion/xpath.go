@@ -0,0 +1,91 @@
+package assertion
+
+import (
+	"fmt"
+	"strings"
+
+	"curlex/internal/models"
+	"github.com/antchfx/xmlquery"
+	"github.com/antchfx/xpath"
+)
+
+// XPathValidator validates XPath assertions against XML response bodies
+type XPathValidator struct{}
+
+// Validate evaluates the XPath expression and compares its value using the
+// json_path operators
+// Examples: "//order/status == 'shipped'", "//item/@id exists",
+// "count(//item) >= 3", "//soap:Fault not exists"
+func (v *XPathValidator) Validate(result *models.TestResult, assertion models.Assertion) *models.AssertionFailure {
+	expr := strings.TrimSpace(assertion.Value)
+
+	jp := &JSONPathValidator{}
+	je, err := jp.parseExpression(expr)
+	if err == nil && je.cond.operator == "is" {
+		err = fmt.Errorf("is is not supported for XPath: %s", expr)
+	}
+	var compiled *xpath.Expr
+	if err == nil {
+		compiled, err = xpath.CompileWithNS(je.path, result.Test.Namespaces)
+	}
+	if err != nil {
+		return &models.AssertionFailure{
+			Type:    models.AssertionXPath,
+			Message: fmt.Sprintf("invalid expression: %v", err),
+		}
+	}
+
+	doc, err := parseXML(result.ResponseBody)
+	if err != nil {
+		return &models.AssertionFailure{
+			Type:     models.AssertionXPath,
+			Expected: "XML body",
+			Actual:   excerpt(result.ResponseBody, 0, 0),
+			Message:  fmt.Sprintf("response body is not valid XML: %v", err),
+		}
+	}
+
+	value, found := xpathValue(compiled.Evaluate(xmlquery.CreateXPathNavigator(doc)), je.length)
+	return jp.check(models.AssertionXPath, "XPath", je.path, value, found, je)
+}
+
+// parseXML parses an XML document, rejecting bodies without a root element
+func parseXML(body string) (*xmlquery.Node, error) {
+	doc, err := xmlquery.Parse(strings.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for n := doc.FirstChild; n != nil; n = n.NextSibling {
+		if n.Type == xmlquery.ElementNode {
+			return doc, nil
+		}
+	}
+	return nil, fmt.Errorf("no root element")
+}
+
+// xpathValue converts the result of an XPath expression to a value that is
+// compared like a json_path value: no nodes does not exist, one node is its
+// trimmed text, several nodes are a list of texts
+// Node text compares as a number with numbers, e.g. @version == 2
+// With nodeSet, nodes are always a list so "| length" counts them
+func xpathValue(result any, nodeSet bool) (any, bool) {
+	iter, ok := result.(*xpath.NodeIterator)
+	if !ok {
+		// Functions such as count() and string() return a number, string or boolean
+		return result, true
+	}
+
+	nodes := []any{}
+	for iter.MoveNext() {
+		nodes = append(nodes, strings.TrimSpace(iter.Current().Value()))
+	}
+	switch {
+	case nodeSet:
+		return nodes, true
+	case len(nodes) == 0:
+		return nil, false
+	case len(nodes) == 1:
+		return nodes[0], true
+	}
+	return nodes, true
+}
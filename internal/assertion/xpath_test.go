@@ -0,0 +1,128 @@
+package assertion
+
+import (
+	"strings"
+	"testing"
+
+	"curlex/internal/models"
+)
+
+func TestXPathValidator(t *testing.T) {
+	validator := &XPathValidator{}
+
+	rss := `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0">
+  <channel>
+    <title>Release notes</title>
+    <item><title>v1.2.0</title><category>release</category></item>
+    <item><title>v1.1.0</title><category>release</category></item>
+    <item><title>Roadmap</title><category>news</category></item>
+  </channel>
+</rss>`
+
+	soap := `<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+  <soap:Body>
+    <m:GetPriceResponse xmlns:m="urn:prices">
+      <m:Price currency="EUR">19.90</m:Price>
+    </m:GetPriceResponse>
+  </soap:Body>
+</soap:Envelope>`
+
+	atom := `<feed xmlns="http://www.w3.org/2005/Atom"><entry><id>urn:1</id></entry></feed>`
+
+	namespaces := map[string]string{
+		"soap": "http://schemas.xmlsoap.org/soap/envelope/",
+		"p":    "urn:prices",
+		"atom": "http://www.w3.org/2005/Atom",
+	}
+
+	tests := []struct {
+		name    string
+		body    string
+		expr    string
+		message string // Expected failure message, empty when the assertion passes
+	}{
+		{name: "element text", body: rss, expr: "/rss/channel/title == 'Release notes'"},
+		{name: "attribute", body: rss, expr: "/rss/@version == '2.0'"},
+		{name: "numeric attribute", body: rss, expr: "/rss/@version == 2"},
+		{name: "count", body: rss, expr: "count(//item) == 3"},
+		{name: "count comparison", body: rss, expr: "count(//item[category='release']) > 1"},
+		{name: "node set length", body: rss, expr: "//item | length == 3"},
+		{name: "node set contains", body: rss, expr: "//item/title contains 'Roadmap'"},
+		{name: "text matches", body: rss, expr: `//item[1]/title matches /^v\d+\.\d+\.\d+$/`},
+		{name: "in list", body: rss, expr: "//item[3]/category in [news, blog]"},
+		{name: "exists", body: rss, expr: "//item/category exists"},
+		{name: "not exists", body: rss, expr: "//item/author not exists"},
+		{name: "soap with namespaces", body: soap, expr: "//soap:Body/p:GetPriceResponse/p:Price == 19.9"},
+		{name: "soap attribute", body: soap, expr: "//p:Price/@currency == 'EUR'"},
+		{name: "default namespace", body: atom, expr: "/atom:feed/atom:entry/atom:id == 'urn:1'"},
+		{
+			name:    "text mismatch",
+			body:    rss,
+			expr:    "/rss/channel/title == 'Changelog'",
+			message: "/rss/channel/title == 'Changelog' failed: got Release notes",
+		},
+		{
+			name:    "count mismatch",
+			body:    rss,
+			expr:    "count(//item) == 2",
+			message: "count(//item) == 2 failed: got 3",
+		},
+		{
+			name:    "missing node",
+			body:    soap,
+			expr:    "//soap:Fault/faultstring == 'x'",
+			message: `XPath "//soap:Fault/faultstring" not found`,
+		},
+		{
+			name:    "unexpected node",
+			body:    soap,
+			expr:    "//p:Price not exists",
+			message: `XPath "//p:Price" should not exist: got 19.9`,
+		},
+		{
+			name:    "undeclared prefix",
+			body:    soap,
+			expr:    "//x:Price exists",
+			message: "invalid expression:",
+		},
+		{
+			name:    "is not supported",
+			body:    rss,
+			expr:    "//item is array",
+			message: "invalid expression: is is not supported for XPath",
+		},
+		{
+			name:    "not XML",
+			body:    `{"id": 1}`,
+			expr:    "//id exists",
+			message: "response body is not valid XML",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := &models.TestResult{
+				Test:         models.Test{Namespaces: namespaces},
+				ResponseBody: tt.body,
+			}
+			failure := validator.Validate(result, models.Assertion{Type: models.AssertionXPath, Value: tt.expr})
+
+			if tt.message == "" {
+				if failure != nil {
+					t.Fatalf("Expected to pass, but failed: %s", failure.Message)
+				}
+				return
+			}
+			if failure == nil {
+				t.Fatal("Expected to fail, but passed")
+			}
+			if failure.Type != models.AssertionXPath {
+				t.Errorf("Failure type = %s, want %s", failure.Type, models.AssertionXPath)
+			}
+			if !strings.HasPrefix(failure.Message, tt.message) {
+				t.Errorf("Message = %q, want prefix %q", failure.Message, tt.message)
+			}
+		})
+	}
+}
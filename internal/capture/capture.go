@@ -0,0 +1,160 @@
+package capture
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"curlex/internal/models"
+	"github.com/antchfx/xmlquery"
+	"github.com/antchfx/xpath"
+	"github.com/tidwall/gjson"
+)
+
+// Extractor pulls captured values out of test results
+type Extractor struct{}
+
+// NewExtractor creates a new capture extractor
+func NewExtractor() *Extractor {
+	return &Extractor{}
+}
+
+// Extract evaluates all captures against the result
+// Returns the captured values and a failure for every capture that could not be resolved
+func (e *Extractor) Extract(result *models.TestResult, captures map[string]models.Capture) (map[string]string, []models.AssertionFailure) {
+	values := make(map[string]string)
+	var failures []models.AssertionFailure
+
+	for name, c := range captures {
+		value, err := e.extractOne(result, c)
+		if err != nil {
+			failures = append(failures, models.AssertionFailure{
+				Type:     models.AssertionCapture,
+				Expected: c.String(),
+				Actual:   err.Error(),
+				Message:  fmt.Sprintf("failed to capture %q: %v", name, err),
+			})
+			continue
+		}
+		values[name] = value
+	}
+
+	return values, failures
+}
+
+// extractOne extracts a single value from the result
+func (e *Extractor) extractOne(result *models.TestResult, c models.Capture) (string, error) {
+	switch c.Source {
+	case models.CaptureStatus:
+		return strconv.Itoa(result.StatusCode), nil
+
+	case models.CaptureJSONPath:
+		// Remove leading dot for gjson (it doesn't use dots at the beginning)
+		path := strings.TrimPrefix(strings.TrimSpace(c.Expression), ".")
+		jsonResult := gjson.Get(result.ResponseBody, path)
+		if !jsonResult.Exists() {
+			return "", fmt.Errorf("JSON path %q not found", path)
+		}
+		return jsonResult.String(), nil
+
+	case models.CaptureXPath:
+		return extractXPath(result, strings.TrimSpace(c.Expression))
+
+	case models.CaptureHeader:
+		name := strings.TrimSpace(c.Expression)
+		for key, values := range result.Headers {
+			if strings.EqualFold(key, name) && len(values) > 0 {
+				return values[0], nil
+			}
+		}
+		return "", fmt.Errorf("header %q not found", name)
+
+	case models.CaptureRegex:
+		re, err := regexp.Compile(c.Expression)
+		if err != nil {
+			return "", fmt.Errorf("invalid regex: %w", err)
+		}
+		matches := re.FindStringSubmatch(result.ResponseBody)
+		if matches == nil {
+			return "", fmt.Errorf("regex %q did not match body", c.Expression)
+		}
+		// Prefer the first capture group, fall back to the whole match
+		if len(matches) > 1 {
+			return matches[1], nil
+		}
+		return matches[0], nil
+
+	case models.CaptureCookie:
+		name := strings.TrimSpace(c.Expression)
+		resp := &http.Response{Header: result.Headers}
+		for _, cookie := range resp.Cookies() {
+			if cookie.Name == name {
+				return cookie.Value, nil
+			}
+		}
+		return "", fmt.Errorf("cookie %q not set", name)
+
+	default:
+		return "", fmt.Errorf("unsupported capture source: %s", c.Source)
+	}
+}
+
+// ExtractNamedGroups returns the named groups, e.g. (?P<token>\w+), of every
+// body_matches assertion whose pattern matches the body
+// Groups that did not participate in the match are left out
+func (e *Extractor) ExtractNamedGroups(result *models.TestResult, assertions []models.Assertion) map[string]string {
+	var values map[string]string
+
+	for _, a := range assertions {
+		if a.Type != models.AssertionBodyMatches {
+			continue
+		}
+		re, err := regexp.Compile(a.Value)
+		if err != nil {
+			// Reported by the assertion itself
+			continue
+		}
+		loc := re.FindStringSubmatchIndex(result.ResponseBody)
+		if loc == nil {
+			continue
+		}
+		for i, name := range re.SubexpNames() {
+			if name == "" || loc[2*i] < 0 {
+				continue
+			}
+			if values == nil {
+				values = make(map[string]string)
+			}
+			values[name] = result.ResponseBody[loc[2*i]:loc[2*i+1]]
+		}
+	}
+
+	return values
+}
+
+// extractXPath returns the trimmed text of the first node an XPath selects,
+// or the value of an expression such as count(//item)
+func extractXPath(result *models.TestResult, expr string) (string, error) {
+	compiled, err := xpath.CompileWithNS(expr, result.Test.Namespaces)
+	if err != nil {
+		return "", fmt.Errorf("invalid XPath: %w", err)
+	}
+	doc, err := xmlquery.Parse(strings.NewReader(result.ResponseBody))
+	if err != nil {
+		return "", fmt.Errorf("response body is not valid XML: %w", err)
+	}
+
+	switch value := compiled.Evaluate(xmlquery.CreateXPathNavigator(doc)).(type) {
+	case *xpath.NodeIterator:
+		if value.MoveNext() {
+			return strings.TrimSpace(value.Current().Value()), nil
+		}
+		return "", fmt.Errorf("XPath %q not found", expr)
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64), nil
+	default:
+		return fmt.Sprint(value), nil
+	}
+}
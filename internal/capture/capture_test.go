@@ -0,0 +1,191 @@
+package capture
+
+import (
+	"net/http"
+	"testing"
+
+	"curlex/internal/models"
+)
+
+func TestExtractor_Extract(t *testing.T) {
+	result := &models.TestResult{
+		StatusCode:   201,
+		ResponseBody: `{"data": {"token": "abc123", "id": 42}} order-9876`,
+		Headers: http.Header{
+			"X-Request-Id": []string{"req-1"},
+			"Set-Cookie":   []string{"session_id=s3cr3t; Path=/; HttpOnly", "theme=dark"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		capture  models.Capture
+		expected string
+		fails    bool
+	}{
+		{
+			name:     "json path string",
+			capture:  models.Capture{Source: models.CaptureJSONPath, Expression: ".data.token"},
+			expected: "abc123",
+		},
+		{
+			name:     "json path number",
+			capture:  models.Capture{Source: models.CaptureJSONPath, Expression: "data.id"},
+			expected: "42",
+		},
+		{
+			name:    "json path missing",
+			capture: models.Capture{Source: models.CaptureJSONPath, Expression: ".data.missing"},
+			fails:   true,
+		},
+		{
+			name:     "header case-insensitive",
+			capture:  models.Capture{Source: models.CaptureHeader, Expression: "x-request-id"},
+			expected: "req-1",
+		},
+		{
+			name:    "header missing",
+			capture: models.Capture{Source: models.CaptureHeader, Expression: "X-Missing"},
+			fails:   true,
+		},
+		{
+			name:     "regex group",
+			capture:  models.Capture{Source: models.CaptureRegex, Expression: `order-(\d+)`},
+			expected: "9876",
+		},
+		{
+			name:     "regex whole match",
+			capture:  models.Capture{Source: models.CaptureRegex, Expression: `order-\d+`},
+			expected: "order-9876",
+		},
+		{
+			name:    "regex no match",
+			capture: models.Capture{Source: models.CaptureRegex, Expression: `invoice-\d+`},
+			fails:   true,
+		},
+		{
+			name:     "status",
+			capture:  models.Capture{Source: models.CaptureStatus},
+			expected: "201",
+		},
+		{
+			name:     "cookie",
+			capture:  models.Capture{Source: models.CaptureCookie, Expression: "session_id"},
+			expected: "s3cr3t",
+		},
+		{
+			name:    "cookie missing",
+			capture: models.Capture{Source: models.CaptureCookie, Expression: "missing"},
+			fails:   true,
+		},
+	}
+
+	extractor := NewExtractor()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, failures := extractor.Extract(result, map[string]models.Capture{"value": tt.capture})
+
+			if tt.fails {
+				if len(failures) != 1 {
+					t.Fatalf("Expected 1 failure, got %d", len(failures))
+				}
+				if failures[0].Type != models.AssertionCapture {
+					t.Errorf("Failure type = %s, want %s", failures[0].Type, models.AssertionCapture)
+				}
+				if _, ok := values["value"]; ok {
+					t.Error("Failed capture should not produce a value")
+				}
+				return
+			}
+
+			if len(failures) != 0 {
+				t.Fatalf("Unexpected failures: %v", failures)
+			}
+			if values["value"] != tt.expected {
+				t.Errorf("Captured = %q, want %q", values["value"], tt.expected)
+			}
+		})
+	}
+}
+
+func TestExtractor_ExtractNamedGroups(t *testing.T) {
+	result := &models.TestResult{
+		ResponseBody: `<form><input name="csrf" value="tok-42"></form><p>Order 9876</p>`,
+	}
+
+	assertions := []models.Assertion{
+		{Type: models.AssertionBodyMatches, Value: `name="csrf" value="(?P<csrf>[^"]+)"`},
+		{Type: models.AssertionBodyMatches, Value: `Order (?P<order>\d+)(?P<suffix>-\w+)?`},
+		{Type: models.AssertionBodyMatches, Value: `(?P<missing>never present)`},
+		{Type: models.AssertionBodyContains, Value: `(?P<ignored>Order)`},
+	}
+
+	values := NewExtractor().ExtractNamedGroups(result, assertions)
+
+	expected := map[string]string{"csrf": "tok-42", "order": "9876"}
+	if len(values) != len(expected) {
+		t.Fatalf("ExtractNamedGroups() = %v, want %v", values, expected)
+	}
+	for name, want := range expected {
+		if values[name] != want {
+			t.Errorf("%s = %q, want %q", name, values[name], want)
+		}
+	}
+
+	if got := NewExtractor().ExtractNamedGroups(result, []models.Assertion{{Type: models.AssertionStatus, Value: "200"}}); got != nil {
+		t.Errorf("ExtractNamedGroups() without body_matches = %v, want nil", got)
+	}
+}
+
+func TestExtractor_ExtractXPath(t *testing.T) {
+	result := &models.TestResult{
+		Test: models.Test{Namespaces: map[string]string{
+			"soap": "http://schemas.xmlsoap.org/soap/envelope/",
+			"m":    "urn:orders",
+		}},
+		ResponseBody: `<?xml version="1.0"?>
+<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+  <soap:Body>
+    <m:CreateOrderResponse xmlns:m="urn:orders">
+      <m:Order id="ord-7">
+        <m:Total> 19.90 </m:Total>
+      </m:Order>
+    </m:CreateOrderResponse>
+  </soap:Body>
+</soap:Envelope>`,
+	}
+
+	tests := []struct {
+		name       string
+		expression string
+		expected   string
+		fails      bool
+	}{
+		{name: "element text", expression: "//m:Order/m:Total", expected: "19.90"},
+		{name: "attribute", expression: "//m:Order/@id", expected: "ord-7"},
+		{name: "function", expression: "count(//m:Order)", expected: "1"},
+		{name: "missing", expression: "//m:Invoice", fails: true},
+		{name: "undeclared prefix", expression: "//x:Order", fails: true},
+	}
+
+	extractor := NewExtractor()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			capture := models.Capture{Source: models.CaptureXPath, Expression: tt.expression}
+			values, failures := extractor.Extract(result, map[string]models.Capture{"value": capture})
+
+			if tt.fails {
+				if len(failures) != 1 {
+					t.Fatalf("Expected 1 failure, got %d", len(failures))
+				}
+				return
+			}
+			if len(failures) != 0 {
+				t.Fatalf("Unexpected failures: %v", failures)
+			}
+			if values["value"] != tt.expected {
+				t.Errorf("Captured = %q, want %q", values["value"], tt.expected)
+			}
+		})
+	}
+}
@@ -5,29 +5,55 @@ import (
 	"fmt"
 	"os"
 	"time"
+
+	"curlex/internal/models"
 )
 
 // Config holds the CLI configuration
 type Config struct {
-	TestFile     string
-	Timeout      time.Duration
-	NoColor      bool
-	Version      bool
-	Verbose      bool
-	LogDir       string
-	TestFilter   string
-	TestPattern  string
-	SkipTests    string
-	Parallel     bool
-	Concurrency  int
-	FailFast     bool
-	OutputFormat string
-	Quiet        bool
+	TestFile        string
+	Timeout         time.Duration
+	NoColor         bool
+	Version         bool
+	Verbose         bool
+	LogDir          string
+	TestFilter      string
+	TestPattern     string
+	SkipTests       string
+	Parallel        bool
+	Concurrency     int
+	FailFast        bool
+	OutputFormat    string
+	Quiet           bool
+	Strict          bool
+	Proxy           string
+	Resolve         map[string]string // --resolve host:port:addr entries
+	CookieJar       string            // File to save the suite's cookie jar to
+	Coverage        bool              // Print the OpenAPI coverage report
+	UpdateSnapshots bool              // Rewrite snapshots and remove obsolete ones
+}
+
+// resolveFlag collects repeated --resolve flags
+type resolveFlag map[string]string
+
+// String returns the collected entries
+func (r resolveFlag) String() string {
+	return fmt.Sprint(map[string]string(r))
+}
+
+// Set parses a host:port:addr entry
+func (r resolveFlag) Set(value string) error {
+	hostPort, addr, err := models.ParseResolve(value)
+	if err != nil {
+		return err
+	}
+	r[hostPort] = addr
+	return nil
 }
 
 // ParseFlags parses command-line flags and returns configuration
 func ParseFlags() (*Config, error) {
-	cfg := &Config{}
+	cfg := &Config{Resolve: make(map[string]string)}
 
 	flag.DurationVar(&cfg.Timeout, "timeout", 30*time.Second, "Request timeout (e.g., 30s, 1m)")
 	flag.BoolVar(&cfg.NoColor, "no-color", false, "Disable colored output")
@@ -42,6 +68,12 @@ func ParseFlags() (*Config, error) {
 	flag.BoolVar(&cfg.FailFast, "fail-fast", false, "Stop on first test failure")
 	flag.StringVar(&cfg.OutputFormat, "output", "human", "Output format: human, json, junit, quiet")
 	flag.BoolVar(&cfg.Quiet, "quiet", false, "Minimal output (summary only)")
+	flag.StringVar(&cfg.Proxy, "proxy", "", "Proxy URL (http, https or socks5) for tests without their own proxy setting")
+	flag.Var(resolveFlag(cfg.Resolve), "resolve", "Connect to addr for host:port, as host:port:addr (repeatable)")
+	flag.StringVar(&cfg.CookieJar, "cookie-jar", "", "Write cookies collected with 'cookies: jar' to a Netscape cookie file")
+	flag.BoolVar(&cfg.Coverage, "openapi-coverage", false, "Report which operations in the suite's OpenAPI document were never exercised")
+	flag.BoolVar(&cfg.UpdateSnapshots, "update-snapshots", false, "Record snapshot assertions from the current responses and remove obsolete snapshots")
+	flag.BoolVar(&cfg.Strict, "strict", false, "Fail on curl flags curlex does not support instead of ignoring them")
 
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "Usage: curlex [options] <test-file.yaml>\n\n")
@@ -69,6 +101,13 @@ func ParseFlags() (*Config, error) {
 
 	cfg.TestFile = flag.Arg(0)
 
+	// Validate proxy URL
+	if cfg.Proxy != "" {
+		if _, err := models.ParseProxyURL(cfg.Proxy); err != nil {
+			return nil, fmt.Errorf("--proxy: %w", err)
+		}
+	}
+
 	// Validate test file exists
 	if _, err := os.Stat(cfg.TestFile); os.IsNotExist(err) {
 		return nil, fmt.Errorf("test file does not exist: %s", cfg.TestFile)
@@ -246,3 +246,96 @@ func TestParseFlags_LogDir(t *testing.T) {
 		t.Errorf("LogDir = %s, want %s", cfg.LogDir, logDir)
 	}
 }
+
+func TestParseFlags_Proxy(t *testing.T) {
+	tmpDir := t.TempDir()
+	testFile := filepath.Join(tmpDir, "test.yaml")
+	if err := os.WriteFile(testFile, []byte("version: 1.0\ntests: []"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Reset flag state
+	flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+	os.Args = []string{"curlex", "--proxy", "socks5://proxy.corp:1080", testFile}
+
+	cfg, err := ParseFlags()
+	if err != nil {
+		t.Fatalf("ParseFlags() unexpected error: %v", err)
+	}
+	if cfg.Proxy != "socks5://proxy.corp:1080" {
+		t.Errorf("Proxy = %s, want socks5://proxy.corp:1080", cfg.Proxy)
+	}
+
+	// Reset flag state
+	flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+	os.Args = []string{"curlex", "--proxy", "ftp://proxy.corp", testFile}
+
+	if _, err := ParseFlags(); err == nil {
+		t.Error("ParseFlags() should reject an unsupported proxy scheme")
+	}
+}
+
+func TestParseFlags_Resolve(t *testing.T) {
+	tmpDir := t.TempDir()
+	testFile := filepath.Join(tmpDir, "test.yaml")
+	if err := os.WriteFile(testFile, []byte("version: 1.0\ntests: []"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Reset flag state
+	flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+	os.Args = []string{
+		"curlex",
+		"--resolve", "api.example.com:443:10.0.0.1",
+		"--resolve", "cdn.example.com:443:[::1]",
+		testFile,
+	}
+
+	cfg, err := ParseFlags()
+	if err != nil {
+		t.Fatalf("ParseFlags() unexpected error: %v", err)
+	}
+	if cfg.Resolve["api.example.com:443"] != "10.0.0.1" || cfg.Resolve["cdn.example.com:443"] != "::1" {
+		t.Errorf("Resolve = %v", cfg.Resolve)
+	}
+}
+
+func TestParseFlags_OpenAPICoverage(t *testing.T) {
+	tmpDir := t.TempDir()
+	testFile := filepath.Join(tmpDir, "test.yaml")
+	if err := os.WriteFile(testFile, []byte("version: 1.0\ntests: []"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Reset flag state
+	flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+	os.Args = []string{"curlex", "--openapi-coverage", testFile}
+
+	cfg, err := ParseFlags()
+	if err != nil {
+		t.Fatalf("ParseFlags() unexpected error: %v", err)
+	}
+	if !cfg.Coverage {
+		t.Error("Coverage = false, want true")
+	}
+}
+
+func TestParseFlags_UpdateSnapshots(t *testing.T) {
+	tmpDir := t.TempDir()
+	testFile := filepath.Join(tmpDir, "test.yaml")
+	if err := os.WriteFile(testFile, []byte("version: 1.0\ntests: []"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Reset flag state
+	flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+	os.Args = []string{"curlex", "--update-snapshots", testFile}
+
+	cfg, err := ParseFlags()
+	if err != nil {
+		t.Fatalf("ParseFlags() unexpected error: %v", err)
+	}
+	if !cfg.UpdateSnapshots {
+		t.Error("UpdateSnapshots = false, want true")
+	}
+}
@@ -0,0 +1,91 @@
+package executor
+
+import (
+	"bytes"
+	"fmt"
+	"mime/multipart"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+
+	"curlex/internal/formdata"
+	"curlex/internal/models"
+)
+
+// encodeFormBody encodes fields as application/x-www-form-urlencoded
+// Keys are sorted so the body is stable across runs
+func encodeFormBody(fields map[string]string) []byte {
+	values := make(url.Values, len(fields))
+	for key, value := range fields {
+		values.Set(key, value)
+	}
+	return []byte(values.Encode())
+}
+
+// addQueryParams appends URL-encoded query parameters to a URL, keeping any
+// query string and fragment already present
+// Parameters are sorted by name; repeated values keep their order
+func addQueryParams(rawURL string, params models.QueryParams) string {
+	if len(params) == 0 {
+		return rawURL
+	}
+
+	base, fragment, hasFragment := strings.Cut(rawURL, "#")
+
+	separator := "?"
+	if strings.Contains(base, "?") {
+		separator = "&"
+		if strings.HasSuffix(base, "?") || strings.HasSuffix(base, "&") {
+			separator = ""
+		}
+	}
+
+	result := base + separator + url.Values(params).Encode()
+	if hasFragment {
+		result += "#" + fragment
+	}
+	return result
+}
+
+// encodeMultipartBody encodes fields and files as multipart/form-data and
+// returns the body with its Content-Type, including the boundary
+// File paths must already be resolved
+func encodeMultipartBody(body *models.MultipartBody) ([]byte, string, error) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+
+	// Fields first, in a stable order, then files in the order given
+	names := make([]string, 0, len(body.Fields))
+	for name := range body.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := writer.WriteField(name, body.Fields[name]); err != nil {
+			return nil, "", fmt.Errorf("failed to encode multipart field %s: %w", name, err)
+		}
+	}
+
+	for _, file := range body.Files {
+		if err := writeMultipartFile(writer, file); err != nil {
+			return nil, "", err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, "", fmt.Errorf("failed to encode multipart body: %w", err)
+	}
+
+	return buf.Bytes(), writer.FormDataContentType(), nil
+}
+
+// writeMultipartFile reads a file and adds it as a part of a multipart body
+func writeMultipartFile(writer *multipart.Writer, file models.MultipartFile) error {
+	content, err := os.ReadFile(file.Path)
+	if err != nil {
+		return fmt.Errorf("failed to read multipart file %s: %w", file.Name, err)
+	}
+	return formdata.WriteFile(writer, file, content)
+}
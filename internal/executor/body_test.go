@@ -0,0 +1,60 @@
+package executor
+
+import (
+	"testing"
+
+	"curlex/internal/models"
+)
+
+func TestAddQueryParams(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		params   models.QueryParams
+		expected string
+	}{
+		{
+			name:     "no params",
+			url:      "https://example.com/search",
+			expected: "https://example.com/search",
+		},
+		{
+			name:     "special characters are encoded",
+			url:      "https://example.com/search",
+			params:   models.QueryParams{"q": {"a&b=c d"}, "lang": {"日本"}},
+			expected: "https://example.com/search?lang=%E6%97%A5%E6%9C%AC&q=a%26b%3Dc+d",
+		},
+		{
+			name:     "repeated keys keep their order",
+			url:      "https://example.com/items",
+			params:   models.QueryParams{"tag": {"b", "a"}},
+			expected: "https://example.com/items?tag=b&tag=a",
+		},
+		{
+			name:     "existing query string is kept",
+			url:      "https://example.com/items?page=2",
+			params:   models.QueryParams{"sort": {"name"}},
+			expected: "https://example.com/items?page=2&sort=name",
+		},
+		{
+			name:     "trailing question mark",
+			url:      "https://example.com/items?",
+			params:   models.QueryParams{"sort": {"name"}},
+			expected: "https://example.com/items?sort=name",
+		},
+		{
+			name:     "fragment stays last",
+			url:      "https://example.com/page#section",
+			params:   models.QueryParams{"v": {"1"}},
+			expected: "https://example.com/page?v=1#section",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := addQueryParams(tt.url, tt.params); got != tt.expected {
+				t.Errorf("addQueryParams() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
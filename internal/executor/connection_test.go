@@ -0,0 +1,126 @@
+package executor
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"curlex/internal/models"
+)
+
+func TestExecutor_Execute_ResolveMap(t *testing.T) {
+	var gotHost string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHost = r.Host
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	_, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	writeCA(t, server, dir)
+
+	// The test certificate is issued for example.com, so verification only
+	// passes if the real hostname is kept for SNI
+	executor := NewExecutor(5 * time.Second)
+	result, err := executor.Execute(context.Background(), models.Test{
+		Name:    "resolve",
+		Request: &models.StructuredRequest{Method: "GET", URL: "https://example.com:" + port + "/"},
+		Resolve: map[string]string{"example.com": "127.0.0.1"},
+		TLS:     &models.TLSConfig{CAFile: "ca.pem"},
+		BaseDir: dir,
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Error != nil || result.StatusCode != http.StatusOK {
+		t.Fatalf("StatusCode = %d, Error = %v", result.StatusCode, result.Error)
+	}
+	if gotHost != "example.com:"+port {
+		t.Errorf("Host = %q, want the original hostname", gotHost)
+	}
+}
+
+func TestExecutor_ResolvePrecedence(t *testing.T) {
+	executor := NewExecutor(5 * time.Second)
+	executor.SetResolve(map[string]string{"api.example.com:443": "10.0.0.1", "cdn.example.com:443": "10.0.0.2"})
+
+	req, err := executor.prepareRequest(models.Test{
+		Curl:    "curl --resolve api.example.com:443:10.0.0.3 https://api.example.com/",
+		Resolve: map[string]string{"api.example.com:443": "10.0.0.4", "cdn.example.com:443": "10.0.0.5"},
+	})
+	if err != nil {
+		t.Fatalf("prepareRequest() error = %v", err)
+	}
+
+	// curl --resolve > test resolve > --resolve
+	if req.Resolve["api.example.com:443"] != "10.0.0.3" {
+		t.Errorf("api.example.com = %q, want the curl flag's address", req.Resolve["api.example.com:443"])
+	}
+	if req.Resolve["cdn.example.com:443"] != "10.0.0.5" {
+		t.Errorf("cdn.example.com = %q, want the test's address", req.Resolve["cdn.example.com:443"])
+	}
+}
+
+func TestExecutor_Execute_UnixSocket(t *testing.T) {
+	// Socket paths are limited to about 100 bytes, so avoid long temp paths
+	dir, err := os.MkdirTemp("", "curlex")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	listener, err := net.Listen("unix", filepath.Join(dir, "api.sock"))
+	if err != nil {
+		t.Skipf("unix sockets not available: %v", err)
+	}
+
+	var gotHost, gotPath string
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHost = r.Host
+		gotPath = r.URL.Path
+		_, _ = w.Write([]byte(`{"ApiVersion":"1.43"}`))
+	})}
+	go func() { _ = server.Serve(listener) }()
+	defer func() { _ = server.Close() }()
+
+	executor := NewExecutor(5 * time.Second)
+
+	tests := []models.Test{
+		{
+			Name:       "structured",
+			Request:    &models.StructuredRequest{Method: "GET", URL: "http://localhost/version"},
+			UnixSocket: "api.sock",
+			BaseDir:    dir,
+		},
+		{
+			Name:    "curl",
+			Curl:    "curl --unix-socket api.sock http://localhost/version",
+			BaseDir: dir,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			gotHost, gotPath = "", ""
+			result, err := executor.Execute(context.Background(), test)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if result.Error != nil || result.ResponseBody != `{"ApiVersion":"1.43"}` {
+				t.Fatalf("Body = %q, Error = %v", result.ResponseBody, result.Error)
+			}
+			if gotHost != "localhost" || gotPath != "/version" {
+				t.Errorf("Host = %q, Path = %q", gotHost, gotPath)
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package executor
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// cookieJar is an http.CookieJar that also remembers each cookie's attributes
+// so the jar can be written out; net/http/cookiejar only returns names and values
+type cookieJar struct {
+	jar *cookiejar.Jar
+
+	mu      sync.Mutex
+	entries map[cookieKey]*jarCookie
+}
+
+// cookieKey identifies a stored cookie as RFC 6265 does
+type cookieKey struct {
+	domain string
+	path   string
+	name   string
+}
+
+// jarCookie is a stored cookie with its effective domain and path
+type jarCookie struct {
+	cookieKey
+	hostOnly bool      // Domain attribute was absent, so subdomains don't match
+	secure   bool      // Only sent over HTTPS
+	httpOnly bool      // Not exposed to scripts
+	expires  time.Time // Zero for session cookies
+	value    string
+}
+
+// newCookieJar creates an empty cookie jar
+func newCookieJar() *cookieJar {
+	// cookiejar.New only fails for invalid options
+	jar, _ := cookiejar.New(nil)
+	return &cookieJar{
+		jar:     jar,
+		entries: make(map[cookieKey]*jarCookie),
+	}
+}
+
+// SetCookies stores cookies received in a response from u
+func (j *cookieJar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	j.jar.SetCookies(u, cookies)
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	now := time.Now()
+	host := strings.ToLower(u.Hostname())
+	for _, cookie := range cookies {
+		entry := &jarCookie{
+			cookieKey: cookieKey{domain: host, path: cookie.Path, name: cookie.Name},
+			hostOnly:  true,
+			secure:    cookie.Secure,
+			httpOnly:  cookie.HttpOnly,
+			value:     cookie.Value,
+		}
+
+		if cookie.Domain != "" {
+			domain := strings.TrimPrefix(strings.ToLower(cookie.Domain), ".")
+			// The jar ignores cookies for domains the host doesn't belong to
+			if host != domain && !strings.HasSuffix(host, "."+domain) {
+				continue
+			}
+			entry.domain = domain
+			entry.hostOnly = false
+		}
+		if entry.path == "" || !strings.HasPrefix(entry.path, "/") {
+			entry.path = defaultCookiePath(u.Path)
+		}
+
+		switch {
+		case cookie.MaxAge < 0:
+			entry.expires = now
+		case cookie.MaxAge > 0:
+			entry.expires = now.Add(time.Duration(cookie.MaxAge) * time.Second)
+		case !cookie.Expires.IsZero():
+			entry.expires = cookie.Expires
+		}
+
+		// A cookie that has already expired deletes any stored one
+		if !entry.expires.IsZero() && !entry.expires.After(now) {
+			delete(j.entries, entry.cookieKey)
+			continue
+		}
+		j.entries[entry.cookieKey] = entry
+	}
+}
+
+// Cookies returns the cookies to send in a request to u
+func (j *cookieJar) Cookies(u *url.URL) []*http.Cookie {
+	return j.jar.Cookies(u)
+}
+
+// defaultCookiePath returns the default cookie path for a request path
+// per RFC 6265 section 5.1.4: the directory of the path, or "/"
+func defaultCookiePath(path string) string {
+	if !strings.HasPrefix(path, "/") {
+		return "/"
+	}
+	i := strings.LastIndex(path, "/")
+	if i == 0 {
+		return "/"
+	}
+	return path[:i]
+}
+
+// WriteNetscape writes the unexpired cookies in the Netscape cookie file
+// format read and written by curl's -b and -c
+func (j *cookieJar) WriteNetscape(w io.Writer) error {
+	j.mu.Lock()
+	entries := make([]*jarCookie, 0, len(j.entries))
+	now := time.Now()
+	for _, entry := range j.entries {
+		if entry.expires.IsZero() || entry.expires.After(now) {
+			entries = append(entries, entry)
+		}
+	}
+	j.mu.Unlock()
+
+	sort.Slice(entries, func(a, b int) bool {
+		if entries[a].domain != entries[b].domain {
+			return entries[a].domain < entries[b].domain
+		}
+		if entries[a].path != entries[b].path {
+			return entries[a].path < entries[b].path
+		}
+		return entries[a].name < entries[b].name
+	})
+
+	buf := bufio.NewWriter(w)
+	fmt.Fprintln(buf, "# Netscape HTTP Cookie File")
+	fmt.Fprintln(buf, "# https://curl.se/docs/http-cookies.html")
+	fmt.Fprintln(buf, "# This file was generated by curlex! Edit at your own risk.")
+	fmt.Fprintln(buf)
+
+	for _, entry := range entries {
+		// Domain cookies are written with a leading dot and match subdomains
+		domain := entry.domain
+		includeSubdomains := "FALSE"
+		if !entry.hostOnly {
+			domain = "." + domain
+			includeSubdomains = "TRUE"
+		}
+		if entry.httpOnly {
+			domain = "#HttpOnly_" + domain
+		}
+
+		secure := "FALSE"
+		if entry.secure {
+			secure = "TRUE"
+		}
+
+		var expires int64
+		if !entry.expires.IsZero() {
+			expires = entry.expires.Unix()
+		}
+
+		fmt.Fprintf(buf, "%s\t%s\t%s\t%s\t%d\t%s\t%s\n",
+			domain, includeSubdomains, entry.path, secure, expires, entry.name, entry.value)
+	}
+
+	return buf.Flush()
+}
+
+// UseCookieJar starts a fresh cookie jar shared by all subsequent requests,
+// or stops using one when enabled is false
+// Tests opt out of the jar with cookie_jar: false
+func (e *Executor) UseCookieJar(enabled bool) {
+	if enabled {
+		e.jar = newCookieJar()
+	} else {
+		e.jar = nil
+	}
+}
+
+// WriteCookieJar saves the cookie jar to path as a Netscape cookie file
+func (e *Executor) WriteCookieJar(path string) error {
+	if e.jar == nil {
+		return fmt.Errorf("no cookie jar in use: set 'cookies: jar' in the test file")
+	}
+
+	// Cookies often hold session credentials, so keep the file private
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create cookie file: %w", err)
+	}
+	if err := e.jar.WriteNetscape(file); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to write cookie file: %w", err)
+	}
+	return file.Close()
+}
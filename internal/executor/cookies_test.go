@@ -0,0 +1,152 @@
+package executor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"curlex/internal/models"
+)
+
+func TestExecutor_Execute_CookieJar(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc123", Path: "/", HttpOnly: true})
+		case "/profile":
+			if cookie, err := r.Cookie("session"); err == nil && cookie.Value == "abc123" {
+				_, _ = w.Write([]byte("logged in"))
+				return
+			}
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer server.Close()
+
+	get := func(path string) models.Test {
+		return models.Test{Name: path, Request: &models.StructuredRequest{Method: "GET", URL: server.URL + path}}
+	}
+
+	executor := NewExecutor(5 * time.Second)
+
+	// Without a jar, cookies are not kept between requests
+	_, _ = executor.Execute(context.Background(), get("/login"))
+	result, _ := executor.Execute(context.Background(), get("/profile"))
+	if result.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("StatusCode = %d without a jar, want 401", result.StatusCode)
+	}
+
+	executor.UseCookieJar(true)
+	_, _ = executor.Execute(context.Background(), get("/login"))
+	result, _ = executor.Execute(context.Background(), get("/profile"))
+	if result.StatusCode != http.StatusOK {
+		t.Errorf("StatusCode = %d with a jar, want 200", result.StatusCode)
+	}
+
+	// A test can opt out of the jar
+	optOut := get("/profile")
+	disabled := false
+	optOut.CookieJar = &disabled
+	result, _ = executor.Execute(context.Background(), optOut)
+	if result.StatusCode != http.StatusUnauthorized {
+		t.Errorf("StatusCode = %d with cookie_jar: false, want 401", result.StatusCode)
+	}
+
+	// Static -b cookies are sent alongside the jar's
+	var gotCookie string
+	echo := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotCookie = r.Header.Get("Cookie")
+	}))
+	defer echo.Close()
+	executor.jar.SetCookies(mustParseURL(t, echo.URL), []*http.Cookie{{Name: "session", Value: "abc123"}})
+	_, _ = executor.Execute(context.Background(), models.Test{Name: "static", Curl: "curl -b theme=dark " + echo.URL})
+	if gotCookie != "theme=dark; session=abc123" {
+		t.Errorf("Cookie = %q, want static and jar cookies", gotCookie)
+	}
+}
+
+func TestCookieJar_WriteNetscape(t *testing.T) {
+	jar := newCookieJar()
+	expires := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+
+	jar.SetCookies(mustParseURL(t, "https://app.example.com/account/login"), []*http.Cookie{
+		{Name: "session", Value: "abc123", HttpOnly: true, Secure: true},
+		{Name: "theme", Value: "dark", Domain: "example.com", Path: "/", Expires: expires},
+		{Name: "tracking", Value: "x", Domain: "other.com"},
+		{Name: "stale", Value: "y", MaxAge: -1},
+	})
+	jar.SetCookies(mustParseURL(t, "https://app.example.com/"), []*http.Cookie{
+		{Name: "prefs", Value: "1", MaxAge: 3600},
+	})
+	// A later expired cookie deletes the stored one
+	jar.SetCookies(mustParseURL(t, "https://app.example.com/"), []*http.Cookie{
+		{Name: "prefs", Value: "", MaxAge: -1},
+	})
+
+	var sb strings.Builder
+	if err := jar.WriteNetscape(&sb); err != nil {
+		t.Fatalf("WriteNetscape() error = %v", err)
+	}
+	output := sb.String()
+
+	if !strings.HasPrefix(output, "# Netscape HTTP Cookie File\n") {
+		t.Errorf("Missing Netscape header:\n%s", output)
+	}
+
+	var lines []string
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		if line != "" && (!strings.HasPrefix(line, "#") || strings.HasPrefix(line, "#HttpOnly_")) {
+			lines = append(lines, line)
+		}
+	}
+	want := []string{
+		"#HttpOnly_app.example.com\tFALSE\t/account\tTRUE\t0\tsession\tabc123",
+		".example.com\tTRUE\t/\tFALSE\t" + strconv.FormatInt(expires.Unix(), 10) + "\ttheme\tdark",
+	}
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Cookie lines =\n%s\nwant\n%s", strings.Join(lines, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestExecutor_WriteCookieJar(t *testing.T) {
+	executor := NewExecutor(5 * time.Second)
+	path := filepath.Join(t.TempDir(), "cookies.txt")
+
+	if err := executor.WriteCookieJar(path); err == nil {
+		t.Error("Expected an error when no cookie jar is in use")
+	}
+
+	executor.UseCookieJar(true)
+	executor.jar.SetCookies(mustParseURL(t, "http://localhost/"), []*http.Cookie{{Name: "a", Value: "1"}})
+	if err := executor.WriteCookieJar(path); err != nil {
+		t.Fatalf("WriteCookieJar() error = %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Cookie file mode = %v, want 0600", info.Mode().Perm())
+	}
+	content, _ := os.ReadFile(path)
+	if !strings.Contains(string(content), "localhost\tFALSE\t/\tFALSE\t0\ta\t1") {
+		t.Errorf("Cookie file = %q", content)
+	}
+}
+
+func mustParseURL(t *testing.T, rawURL string) *url.URL {
+	t.Helper()
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u
+}
@@ -3,9 +3,14 @@ package executor
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"os"
+	"strings"
+	"sync"
 	"time"
 
 	"curlex/internal/models"
@@ -16,20 +21,42 @@ import (
 type Executor struct {
 	client     *http.Client
 	curlParser *parser.CurlParser
+	proxy      *models.ProxyConfig // Proxy for tests that don't set one, from --proxy
+	resolve    map[string]string   // Host resolution overrides from --resolve
+	jar        *cookieJar          // Cookies shared across tests, nil unless enabled
+
+	// Transports for requests with TLS, proxy or connection settings, keyed by
+	// those settings so tests sharing a configuration reuse connections
+	transportsMu sync.Mutex
+	transports   map[transportKey]*http.Transport
 }
 
 // NewExecutor creates a new HTTP executor with default settings
 func NewExecutor(timeout time.Duration) *Executor {
 	return &Executor{
 		client: &http.Client{
-			Timeout: timeout,
+			Timeout:   timeout,
+			Transport: http.DefaultTransport.(*http.Transport).Clone(),
 			// Default: follow up to 10 redirects
 			CheckRedirect: nil,
 		},
 		curlParser: parser.NewCurlParser(),
+		transports: make(map[transportKey]*http.Transport),
 	}
 }
 
+// SetProxy sets the proxy used by tests without their own proxy settings
+// Without one, requests honor HTTP_PROXY, HTTPS_PROXY and NO_PROXY
+func (e *Executor) SetProxy(proxy *models.ProxyConfig) {
+	e.proxy = proxy
+}
+
+// SetResolve sets host resolution overrides applied to every test
+// Entries from a test's resolve map or curl --resolve flags take precedence
+func (e *Executor) SetResolve(resolve map[string]string) {
+	e.resolve = resolve
+}
+
 // Execute runs a single test and returns the result
 func (e *Executor) Execute(ctx context.Context, test models.Test) (*models.TestResult, error) {
 	result := &models.TestResult{
@@ -47,53 +74,153 @@ func (e *Executor) Execute(ctx context.Context, test models.Test) (*models.TestR
 	// Store prepared request for logging
 	result.PreparedRequest = preparedReq
 
+	// A curl -m/--max-time takes precedence over the test's timeout
+	if preparedReq.Timeout > 0 {
+		test.Timeout = preparedReq.Timeout
+	}
+
+	// Apply per-test timeout as a deadline covering the request and body read
+	reqCtx := ctx
+	if test.Timeout > 0 {
+		var cancel context.CancelFunc
+		reqCtx, cancel = context.WithTimeout(ctx, test.Timeout)
+		defer cancel()
+	}
+
+	// Record per-phase timings for the request
+	start := time.Now()
+	trace := newTimingTrace(start)
+	reqCtx = trace.withContext(reqCtx)
+
 	// Create HTTP request
-	httpReq, err := e.createHTTPRequest(ctx, preparedReq)
+	httpReq, err := e.createHTTPRequest(reqCtx, preparedReq)
 	if err != nil {
 		result.Error = fmt.Errorf("failed to create HTTP request: %w", err)
 		result.Success = false
 		return result, nil
 	}
 
-	// Configure redirect policy if specified
-	client := e.client
-	if test.MaxRedirects != nil {
-		client = e.createClientWithRedirects(*test.MaxRedirects)
+	client, err := e.clientFor(test, preparedReq)
+	if err != nil {
+		result.Error = fmt.Errorf("failed to configure client: %w", err)
+		result.Success = false
+		return result, nil
 	}
-
 	// Execute the request
-	start := time.Now()
 	resp, err := client.Do(httpReq)
 	result.ResponseTime = time.Since(start)
 
 	if err != nil {
-		result.Error = fmt.Errorf("request failed: %w", err)
+		result.Error = e.classifyError(ctx, test, "request failed", err)
 		result.Success = false
 		return result, nil
 	}
 	defer func() { _ = resp.Body.Close() }()
 
-	// Read response body
-	body, err := io.ReadAll(resp.Body)
+	// Read response body, decoding it ourselves for --compressed
+	var bodyReader io.Reader = resp.Body
+	if preparedReq.Compressed {
+		bodyReader, err = decodeBody(resp)
+		if err != nil {
+			result.Error = fmt.Errorf("failed to decode response body: %w", err)
+			result.Success = false
+			return result, nil
+		}
+	}
+	body, err := io.ReadAll(bodyReader)
 	if err != nil {
-		result.Error = fmt.Errorf("failed to read response body: %w", err)
+		result.Error = e.classifyError(ctx, test, "failed to read response body", err)
 		result.Success = false
 		return result, nil
 	}
 
+	// Response time covers the full exchange, including content transfer
+	result.Timing = trace.finish(time.Now())
+	result.ResponseTime = result.Timing.Total
+
 	// Populate result
 	result.StatusCode = resp.StatusCode
 	result.ResponseBody = string(body)
 	result.Headers = resp.Header
+	result.TLS = tlsInfo(resp.TLS)
 
 	return result, nil
 }
 
+// clientFor returns the HTTP client to use for a test
+// Settings from curl flags on the prepared request override the test's
+func (e *Executor) clientFor(test models.Test, req *models.PreparedRequest) (*http.Client, error) {
+	// Configure redirect policy if specified
+	client := e.client
+	maxRedirects := test.MaxRedirects
+	if req.MaxRedirects != nil {
+		maxRedirects = req.MaxRedirects
+	}
+	if maxRedirects != nil {
+		client = e.createClientWithRedirects(*maxRedirects)
+	}
+
+	// TLS, proxy, resolve and Unix socket settings need a dedicated transport
+	if needsTransport(req) {
+		transport, err := e.transportFor(req)
+		if err != nil {
+			return nil, err
+		}
+		custom := *client
+		custom.Transport = transport
+		client = &custom
+	}
+
+	// Share the suite's cookie jar unless the test opts out
+	if e.jar != nil && (test.CookieJar == nil || *test.CookieJar) {
+		withJar := *client
+		withJar.Jar = e.jar
+		client = &withJar
+	}
+
+	// A per-test timeout replaces the global client timeout; it is enforced
+	// through the request context instead
+	if test.Timeout > 0 {
+		perTest := *client
+		perTest.Timeout = 0
+		client = &perTest
+	}
+
+	return client, nil
+}
+
+// classifyError wraps a request error, reporting timeouts as *models.TimeoutError
+// ctx is the caller's context: if it was cancelled (fail-fast, SIGINT, suite
+// max_duration) the request was aborted rather than timing out on its own
+func (e *Executor) classifyError(ctx context.Context, test models.Test, msg string, err error) error {
+	if ctx.Err() != nil {
+		return fmt.Errorf("request aborted: %w", context.Cause(ctx))
+	}
+
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		timeout := test.Timeout
+		if timeout == 0 {
+			timeout = e.client.Timeout
+		}
+		return &models.TimeoutError{Timeout: timeout, Err: err}
+	}
+
+	return fmt.Errorf("%s: %w", msg, err)
+}
+
 // prepareRequest converts a Test to a PreparedRequest
 func (e *Executor) prepareRequest(test models.Test) (*models.PreparedRequest, error) {
 	// If curl command is specified, parse it
 	if test.Curl != "" {
-		return e.curlParser.ParseCurl(test.Curl)
+		preparedReq, err := e.curlParser.ParseCurlRelativeTo(test.Curl, test.BaseDir)
+		if err != nil {
+			return nil, err
+		}
+		applyTestTLS(preparedReq, test)
+		e.applyProxy(preparedReq, test)
+		e.applyConnection(preparedReq, test)
+		return preparedReq, nil
 	}
 
 	// Otherwise use structured request
@@ -103,11 +230,20 @@ func (e *Executor) prepareRequest(test models.Test) (*models.PreparedRequest, er
 
 	preparedReq := &models.PreparedRequest{
 		Method:  test.Request.Method,
-		URL:     test.Request.URL,
-		Body:    test.Request.Body,
+		URL:     addQueryParams(test.Request.URL, test.Request.Query),
+		Body:    []byte(test.Request.Body),
 		Headers: make(map[string]string),
 	}
 
+	// Read the body from a file, sent byte for byte
+	if test.Request.BodyFile != "" {
+		body, err := os.ReadFile(parser.ResolvePath(test.BaseDir, test.Request.BodyFile))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read body_file: %w", err)
+		}
+		preparedReq.Body = body
+	}
+
 	// Copy headers
 	if test.Request.Headers != nil {
 		for k, v := range test.Request.Headers {
@@ -115,15 +251,100 @@ func (e *Executor) prepareRequest(test models.Test) (*models.PreparedRequest, er
 		}
 	}
 
+	// Form bodies are encoded when the HTTP request is built
+	preparedReq.Form = test.Request.Form
+	if test.Request.Multipart != nil {
+		multipartBody := *test.Request.Multipart
+		multipartBody.Files = make([]models.MultipartFile, len(test.Request.Multipart.Files))
+		for i, file := range test.Request.Multipart.Files {
+			file.Path = parser.ResolvePath(test.BaseDir, file.Path)
+			multipartBody.Files[i] = file
+		}
+		preparedReq.Multipart = &multipartBody
+	}
+
+	applyTestTLS(preparedReq, test)
+	e.applyProxy(preparedReq, test)
+	e.applyConnection(preparedReq, test)
+
 	return preparedReq, nil
 }
 
+// applyTestTLS merges the test's tls settings into the request
+// TLS flags from a curl command take precedence over the test's settings
+func applyTestTLS(req *models.PreparedRequest, test models.Test) {
+	if test.TLS == nil {
+		return
+	}
+	settings := *test.TLS
+	for _, path := range []*string{&settings.CAFile, &settings.CertFile, &settings.KeyFile} {
+		if *path != "" {
+			*path = parser.ResolvePath(test.BaseDir, *path)
+		}
+	}
+	req.TLS = req.TLS.Merge(&settings)
+}
+
+// applyProxy sets the request's proxy from the test, falling back to --proxy
+// A proxy from a curl -x flag takes precedence over both
+func (e *Executor) applyProxy(req *models.PreparedRequest, test models.Test) {
+	if req.Proxy != "" {
+		return
+	}
+	proxy := test.Proxy
+	if proxy == nil {
+		proxy = e.proxy
+	}
+	if proxy == nil {
+		return
+	}
+	req.Proxy = proxy.URL
+	if req.NoProxy == nil {
+		req.NoProxy = proxy.NoProxy
+	}
+}
+
+// applyConnection adds the test's resolve overrides and Unix socket to the
+// request, keeping any set by curl flags
+func (e *Executor) applyConnection(req *models.PreparedRequest, test models.Test) {
+	for _, overrides := range []map[string]string{test.Resolve, e.resolve} {
+		for host, addr := range overrides {
+			if req.Resolve == nil {
+				req.Resolve = make(map[string]string)
+			}
+			if _, exists := req.Resolve[host]; !exists {
+				req.Resolve[host] = addr
+			}
+		}
+	}
+
+	if req.UnixSocket == "" && test.UnixSocket != "" {
+		req.UnixSocket = parser.ResolvePath(test.BaseDir, test.UnixSocket)
+	}
+}
+
 // createHTTPRequest creates an http.Request from a PreparedRequest
+// Form and multipart bodies are encoded into preparedReq.Body so logs and
+// output show what was sent
 func (e *Executor) createHTTPRequest(ctx context.Context, preparedReq *models.PreparedRequest) (*http.Request, error) {
+	// Encode structured form bodies
+	var contentType string
+	switch {
+	case preparedReq.Multipart != nil:
+		body, multipartType, err := encodeMultipartBody(preparedReq.Multipart)
+		if err != nil {
+			return nil, err
+		}
+		preparedReq.Body = body
+		contentType = multipartType
+	case len(preparedReq.Form) > 0:
+		preparedReq.Body = encodeFormBody(preparedReq.Form)
+	}
+
 	// Create request body reader
 	var bodyReader io.Reader
-	if preparedReq.Body != "" {
-		bodyReader = bytes.NewBufferString(preparedReq.Body)
+	if len(preparedReq.Body) > 0 {
+		bodyReader = bytes.NewReader(preparedReq.Body)
 	}
 
 	// Create HTTP request
@@ -137,13 +358,29 @@ func (e *Executor) createHTTPRequest(ctx context.Context, preparedReq *models.Pr
 		req.Header.Set(key, value)
 	}
 
+	// The multipart boundary must match the body, so it always wins; a form
+	// body only gets a Content-Type if none was given
+	if contentType == "" && len(preparedReq.Form) > 0 && req.Header.Get("Content-Type") == "" {
+		contentType = "application/x-www-form-urlencoded"
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+		setHeader(preparedReq.Headers, "Content-Type", contentType)
+	}
+
+	// --compressed asks for the encodings decodeBody understands
+	if preparedReq.Compressed {
+		req.Header.Set("Accept-Encoding", "gzip, deflate")
+	}
+
 	return req, nil
 }
 
 // createClientWithRedirects creates an HTTP client with custom redirect policy
 func (e *Executor) createClientWithRedirects(maxRedirects int) *http.Client {
 	client := &http.Client{
-		Timeout: e.client.Timeout,
+		Timeout:   e.client.Timeout,
+		Transport: e.client.Transport,
 	}
 
 	if maxRedirects == 0 {
@@ -172,3 +409,13 @@ func (e *Executor) createClientWithRedirects(maxRedirects int) *http.Client {
 
 	return client
 }
+
+// setHeader sets a header in a header map, replacing any differently-cased key
+func setHeader(headers map[string]string, key, value string) {
+	for existing := range headers {
+		if strings.EqualFold(existing, key) {
+			delete(headers, existing)
+		}
+	}
+	headers[key] = value
+}
@@ -1,10 +1,14 @@
 package executor
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -425,7 +429,7 @@ func TestExecutor_PrepareRequest_Structured(t *testing.T) {
 	if prepared.Headers["Content-Type"] != "application/json" {
 		t.Errorf("Content-Type header = %v, want application/json", prepared.Headers["Content-Type"])
 	}
-	if prepared.Body != `{"name": "test"}` {
+	if string(prepared.Body) != `{"name": "test"}` {
 		t.Errorf("Body = %v, want {\"name\": \"test\"}", prepared.Body)
 	}
 }
@@ -439,7 +443,7 @@ func TestExecutor_CreateHTTPRequest(t *testing.T) {
 			"Authorization": "Bearer token",
 			"Content-Type":  "application/json",
 		},
-		Body: `{"test": "data"}`,
+		Body: []byte(`{"test": "data"}`),
 	}
 
 	req, err := executor.createHTTPRequest(context.Background(), prepared)
@@ -507,3 +511,367 @@ func TestExecutor_CreateClientWithRedirects(t *testing.T) {
 		})
 	}
 }
+
+func TestExecutor_Execute_PerTestTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(200 * time.Millisecond):
+		case <-r.Context().Done():
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name          string
+		globalTimeout time.Duration
+		testTimeout   time.Duration
+		wantTimeout   time.Duration
+	}{
+		{
+			name:          "per-test timeout shorter than global",
+			globalTimeout: 5 * time.Second,
+			testTimeout:   50 * time.Millisecond,
+			wantTimeout:   50 * time.Millisecond,
+		},
+		{
+			name:          "global timeout without per-test override",
+			globalTimeout: 50 * time.Millisecond,
+			wantTimeout:   50 * time.Millisecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			executor := NewExecutor(tt.globalTimeout)
+			result, err := executor.Execute(context.Background(), models.Test{
+				Name:    "Slow",
+				Timeout: tt.testTimeout,
+				Request: &models.StructuredRequest{Method: "GET", URL: server.URL},
+			})
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			var timeoutErr *models.TimeoutError
+			if !errors.As(result.Error, &timeoutErr) {
+				t.Fatalf("Expected TimeoutError, got %v", result.Error)
+			}
+			if timeoutErr.Timeout != tt.wantTimeout {
+				t.Errorf("Timeout = %v, want %v", timeoutErr.Timeout, tt.wantTimeout)
+			}
+			if !result.IsTimeout() {
+				t.Error("IsTimeout() should be true")
+			}
+		})
+	}
+}
+
+func TestExecutor_Execute_PerTestTimeoutExceedsGlobal(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// A slow endpoint with a generous per-test timeout must not be cut off
+	// by the shorter global timeout
+	executor := NewExecutor(20 * time.Millisecond)
+	result, err := executor.Execute(context.Background(), models.Test{
+		Name:    "Slow report",
+		Timeout: 2 * time.Second,
+		Request: &models.StructuredRequest{Method: "GET", URL: server.URL},
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Error != nil {
+		t.Fatalf("Unexpected error: %v", result.Error)
+	}
+	if result.StatusCode != 200 {
+		t.Errorf("StatusCode = %d, want 200", result.StatusCode)
+	}
+}
+
+func TestExecutor_Execute_CancelledIsNotTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	executor := NewExecutor(5 * time.Second)
+	result, _ := executor.Execute(ctx, models.Test{
+		Name:    "Cancelled",
+		Timeout: time.Second,
+		Request: &models.StructuredRequest{Method: "GET", URL: server.URL},
+	})
+	if result.Error == nil {
+		t.Fatal("Expected error for cancelled context")
+	}
+	if result.IsTimeout() {
+		t.Error("Cancelled request should not be reported as a timeout")
+	}
+	if !strings.Contains(result.Error.Error(), "aborted") {
+		t.Errorf("Error = %v, want request aborted", result.Error)
+	}
+}
+
+func TestExecutor_Execute_TimingBreakdown(t *testing.T) {
+	delay := 50 * time.Millisecond
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(delay)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	executor := NewExecutor(5 * time.Second)
+	executor.client = server.Client()
+
+	test := models.Test{
+		Name: "Timing Test",
+		Request: &models.StructuredRequest{
+			Method: "GET",
+			URL:    server.URL,
+		},
+	}
+
+	result, err := executor.Execute(context.Background(), test)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Error != nil {
+		t.Fatalf("Unexpected result error: %v", result.Error)
+	}
+
+	timing := result.Timing
+	if timing == nil {
+		t.Fatal("Expected timing breakdown to be recorded")
+	}
+	if timing.TCPConnect <= 0 {
+		t.Errorf("TCPConnect = %v, expected a new connection to be timed", timing.TCPConnect)
+	}
+	if timing.TLSHandshake <= 0 {
+		t.Errorf("TLSHandshake = %v, expected a handshake against a TLS server", timing.TLSHandshake)
+	}
+	if timing.TTFB < delay {
+		t.Errorf("TTFB = %v, should be >= %v", timing.TTFB, delay)
+	}
+	if timing.Total != result.ResponseTime {
+		t.Errorf("Total = %v, want ResponseTime %v", timing.Total, result.ResponseTime)
+	}
+	if timing.TTFB+timing.ContentTransfer != timing.Total {
+		t.Errorf("TTFB %v + ContentTransfer %v != Total %v", timing.TTFB, timing.ContentTransfer, timing.Total)
+	}
+}
+
+func TestExecutor_Execute_BodyFile(t *testing.T) {
+	dir := t.TempDir()
+	payload := []byte{0x00, 0x01, 0xfe, 0xff, '\r', '\n', 'x'}
+	if err := os.WriteFile(filepath.Join(dir, "payload.bin"), payload, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var received []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	executor := NewExecutor(5 * time.Second)
+
+	tests := []struct {
+		name string
+		test models.Test
+	}{
+		{
+			name: "structured body_file",
+			test: models.Test{
+				Name:    "body_file",
+				BaseDir: dir,
+				Request: &models.StructuredRequest{Method: "POST", URL: server.URL, BodyFile: "payload.bin"},
+			},
+		},
+		{
+			name: "curl data-binary",
+			test: models.Test{
+				Name:    "data-binary",
+				BaseDir: dir,
+				Curl:    "curl --data-binary @payload.bin " + server.URL,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			received = nil
+			result, err := executor.Execute(context.Background(), tt.test)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if result.Error != nil {
+				t.Fatalf("Unexpected error: %v", result.Error)
+			}
+			if !bytes.Equal(received, payload) {
+				t.Errorf("Server received %v, want %v", received, payload)
+			}
+		})
+	}
+
+	// Missing files are reported on the result
+	result, _ := executor.Execute(context.Background(), models.Test{
+		Name:    "missing",
+		BaseDir: dir,
+		Request: &models.StructuredRequest{Method: "POST", URL: server.URL, BodyFile: "missing.bin"},
+	})
+	if result.Error == nil || !strings.Contains(result.Error.Error(), "failed to read body_file") {
+		t.Errorf("Error = %v, want body_file read error", result.Error)
+	}
+}
+
+func TestExecutor_Execute_FormBodies(t *testing.T) {
+	dir := t.TempDir()
+	logo := []byte{0x89, 'P', 'N', 'G', 0x00, 0xff}
+	if err := os.WriteFile(filepath.Join(dir, "logo.png"), logo, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		gotContentType string
+		gotValues      map[string][]string
+		gotFile        []byte
+		gotFilename    string
+		gotFileType    string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotContentType = r.Header.Get("Content-Type")
+		if strings.HasPrefix(gotContentType, "multipart/") {
+			if err := r.ParseMultipartForm(1 << 20); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			gotValues = r.MultipartForm.Value
+			if files := r.MultipartForm.File["avatar"]; len(files) == 1 {
+				gotFilename = files[0].Filename
+				gotFileType = files[0].Header.Get("Content-Type")
+				f, _ := files[0].Open()
+				gotFile, _ = io.ReadAll(f)
+				_ = f.Close()
+			}
+		} else {
+			_ = r.ParseForm()
+			gotValues = r.PostForm
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	executor := NewExecutor(5 * time.Second)
+
+	t.Run("form", func(t *testing.T) {
+		result, err := executor.Execute(context.Background(), models.Test{
+			Name: "form",
+			Request: &models.StructuredRequest{
+				Method: "POST",
+				URL:    server.URL,
+				Form:   map[string]string{"name": "Ada Lovelace", "query": "a&b=c"},
+			},
+		})
+		if err != nil || result.Error != nil {
+			t.Fatalf("Execute() error = %v, result error = %v", err, result.Error)
+		}
+		if gotContentType != "application/x-www-form-urlencoded" {
+			t.Errorf("Content-Type = %q", gotContentType)
+		}
+		if gotValues["name"][0] != "Ada Lovelace" || gotValues["query"][0] != "a&b=c" {
+			t.Errorf("Form values = %v", gotValues)
+		}
+		if string(result.PreparedRequest.Body) != "name=Ada+Lovelace&query=a%26b%3Dc" {
+			t.Errorf("PreparedRequest.Body = %q", result.PreparedRequest.Body)
+		}
+	})
+
+	t.Run("multipart", func(t *testing.T) {
+		result, err := executor.Execute(context.Background(), models.Test{
+			Name:    "multipart",
+			BaseDir: dir,
+			Request: &models.StructuredRequest{
+				Method: "POST",
+				URL:    server.URL,
+				// A stale Content-Type cannot carry the right boundary and is replaced
+				Headers: map[string]string{"content-type": "multipart/form-data"},
+				Multipart: &models.MultipartBody{
+					Fields: map[string]string{"title": "Profile picture"},
+					Files: []models.MultipartFile{
+						{Name: "avatar", Path: "logo.png", Filename: "me.png"},
+					},
+				},
+			},
+		})
+		if err != nil || result.Error != nil {
+			t.Fatalf("Execute() error = %v, result error = %v", err, result.Error)
+		}
+		if !strings.HasPrefix(gotContentType, "multipart/form-data; boundary=") {
+			t.Errorf("Content-Type = %q", gotContentType)
+		}
+		if gotValues["title"][0] != "Profile picture" {
+			t.Errorf("Fields = %v", gotValues)
+		}
+		if gotFilename != "me.png" || gotFileType != "image/png" || !bytes.Equal(gotFile, logo) {
+			t.Errorf("File = %q (%q) %v", gotFilename, gotFileType, gotFile)
+		}
+		if len(result.PreparedRequest.Headers) != 1 {
+			t.Errorf("PreparedRequest.Headers = %v, want only the generated Content-Type", result.PreparedRequest.Headers)
+		}
+	})
+
+	t.Run("missing multipart file", func(t *testing.T) {
+		result, _ := executor.Execute(context.Background(), models.Test{
+			Name:    "missing",
+			BaseDir: dir,
+			Request: &models.StructuredRequest{
+				Method:    "POST",
+				URL:       server.URL,
+				Multipart: &models.MultipartBody{Files: []models.MultipartFile{{Name: "f", Path: "missing.bin"}}},
+			},
+		})
+		if result.Error == nil || !strings.Contains(result.Error.Error(), "failed to read multipart file f") {
+			t.Errorf("Error = %v, want multipart file error", result.Error)
+		}
+	})
+}
+
+func TestExecutor_Execute_QueryParams(t *testing.T) {
+	var gotQuery map[string][]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotQuery = r.URL.Query()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	executor := NewExecutor(5 * time.Second)
+	result, err := executor.Execute(context.Background(), models.Test{
+		Name: "query",
+		Request: &models.StructuredRequest{
+			Method: "GET",
+			URL:    server.URL + "/search?page=1",
+			Query:  models.QueryParams{"q": {"a&b c"}, "tag": {"x", "y"}},
+		},
+	})
+	if err != nil || result.Error != nil {
+		t.Fatalf("Execute() error = %v, result error = %v", err, result.Error)
+	}
+
+	if gotQuery["q"][0] != "a&b c" || len(gotQuery["tag"]) != 2 || gotQuery["page"][0] != "1" {
+		t.Errorf("Server received query %v", gotQuery)
+	}
+
+	// The prepared request reports the final URL for verbose and JSON output
+	if want := server.URL + "/search?page=1&q=a%26b+c&tag=x&tag=y"; result.PreparedRequest.URL != want {
+		t.Errorf("PreparedRequest.URL = %q, want %q", result.PreparedRequest.URL, want)
+	}
+}
@@ -0,0 +1,312 @@
+package executor
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"curlex/internal/models"
+)
+
+func TestExecutor_Execute_HTTPProxy(t *testing.T) {
+	var gotHost, gotAuth string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Proxied requests carry the absolute target URL
+		gotHost = r.URL.Host
+		gotAuth = r.Header.Get("Proxy-Authorization")
+		_, _ = w.Write([]byte("via proxy"))
+	}))
+	defer proxy.Close()
+
+	executor := NewExecutor(5 * time.Second)
+	result, err := executor.Execute(context.Background(), models.Test{
+		Name:    "proxied",
+		Request: &models.StructuredRequest{Method: "GET", URL: "http://upstream.example.test/status"},
+		Proxy:   &models.ProxyConfig{URL: "http://ci:s3cret@" + proxy.Listener.Addr().String()},
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Error != nil || result.ResponseBody != "via proxy" {
+		t.Fatalf("Body = %q, Error = %v", result.ResponseBody, result.Error)
+	}
+	if gotHost != "upstream.example.test" {
+		t.Errorf("proxy saw host %q, want upstream.example.test", gotHost)
+	}
+	if want := "Basic " + base64.StdEncoding.EncodeToString([]byte("ci:s3cret")); gotAuth != want {
+		t.Errorf("Proxy-Authorization = %q, want %q", gotAuth, want)
+	}
+}
+
+func TestExecutor_Execute_ProxyIgnoresResolve(t *testing.T) {
+	var gotHost string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHost = r.URL.Host
+		_, _ = w.Write([]byte("via proxy"))
+	}))
+	defer proxy.Close()
+
+	_, port, err := net.SplitHostPort(proxy.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Nothing listens on 127.0.0.2, so the request only succeeds if the
+	// override is not applied to the proxy's own address
+	executor := NewExecutor(5 * time.Second)
+	result, err := executor.Execute(context.Background(), models.Test{
+		Name:    "proxied",
+		Request: &models.StructuredRequest{Method: "GET", URL: "http://upstream.example.test/status"},
+		Proxy:   &models.ProxyConfig{URL: "http://localhost:" + port},
+		Resolve: map[string]string{"localhost": "127.0.0.2", "upstream.example.test": "127.0.0.2"},
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Error != nil || result.ResponseBody != "via proxy" {
+		t.Fatalf("Body = %q, Error = %v", result.ResponseBody, result.Error)
+	}
+	if gotHost != "upstream.example.test" {
+		t.Errorf("proxy saw host %q, want upstream.example.test", gotHost)
+	}
+}
+
+func TestExecutor_Execute_NoProxy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("direct"))
+	}))
+	defer server.Close()
+
+	// Nothing listens on the proxy address, so only a bypassed request succeeds
+	unused, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	deadProxy := "http://" + unused.Addr().String()
+	_ = unused.Close()
+
+	executor := NewExecutor(5 * time.Second)
+	result, err := executor.Execute(context.Background(), models.Test{
+		Name:    "bypassed",
+		Request: &models.StructuredRequest{Method: "GET", URL: server.URL},
+		Proxy:   &models.ProxyConfig{URL: deadProxy, NoProxy: []string{"localhost", "127.0.0.0/8"}},
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Error != nil || result.ResponseBody != "direct" {
+		t.Errorf("Body = %q, Error = %v", result.ResponseBody, result.Error)
+	}
+
+	result, _ = executor.Execute(context.Background(), models.Test{
+		Name:    "proxied",
+		Request: &models.StructuredRequest{Method: "GET", URL: server.URL},
+		Proxy:   &models.ProxyConfig{URL: deadProxy, NoProxy: []string{"example.com"}},
+	})
+	if result.Error == nil {
+		t.Error("Expected the request to fail through the unreachable proxy")
+	}
+}
+
+func TestExecutor_Execute_SOCKS5Proxy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("via socks"))
+	}))
+	defer server.Close()
+
+	socks, requested := startSOCKS5(t, "ci", "s3cret")
+
+	executor := NewExecutor(5 * time.Second)
+	result, err := executor.Execute(context.Background(), models.Test{
+		Name: "socks",
+		Curl: "curl -x socks5://ci:s3cret@" + socks + " " + server.URL,
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Error != nil || result.ResponseBody != "via socks" {
+		t.Fatalf("Body = %q, Error = %v", result.ResponseBody, result.Error)
+	}
+	if got := <-requested; got != server.Listener.Addr().String() {
+		t.Errorf("SOCKS5 CONNECT to %q, want %q", got, server.Listener.Addr().String())
+	}
+}
+
+func TestExecutor_ProxyPrecedence(t *testing.T) {
+	executor := NewExecutor(5 * time.Second)
+	executor.SetProxy(&models.ProxyConfig{URL: "http://cli-proxy:3128"})
+
+	tests := []struct {
+		name string
+		test models.Test
+		want string
+	}{
+		{
+			name: "--proxy applies without test proxy",
+			test: models.Test{Curl: "curl http://example.test"},
+			want: "http://cli-proxy:3128",
+		},
+		{
+			name: "test proxy overrides --proxy",
+			test: models.Test{Curl: "curl http://example.test", Proxy: &models.ProxyConfig{URL: "socks5://test-proxy:1080"}},
+			want: "socks5://test-proxy:1080",
+		},
+		{
+			name: "curl -x overrides test proxy",
+			test: models.Test{Curl: "curl -x curl-proxy:8080 http://example.test", Proxy: &models.ProxyConfig{URL: "socks5://test-proxy:1080"}},
+			want: "curl-proxy:8080",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := executor.prepareRequest(tt.test)
+			if err != nil {
+				t.Fatalf("prepareRequest() error = %v", err)
+			}
+			if req.Proxy != tt.want {
+				t.Errorf("Proxy = %q, want %q", req.Proxy, tt.want)
+			}
+		})
+	}
+}
+
+func TestBypassProxy(t *testing.T) {
+	noProxy := []string{"localhost", ".internal.example", "*.svc.cluster", "10.0.0.0/8", " api.partner.test "}
+
+	tests := []struct {
+		host string
+		want bool
+	}{
+		{"localhost", true},
+		{"internal.example", true},
+		{"db.internal.example", true},
+		{"notinternal.example", false},
+		{"web.svc.cluster", true},
+		{"10.1.2.3", true},
+		{"192.168.1.1", false},
+		{"API.partner.test", true},
+		{"example.com", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			if got := bypassProxy(tt.host, noProxy); got != tt.want {
+				t.Errorf("bypassProxy(%q) = %v, want %v", tt.host, got, tt.want)
+			}
+		})
+	}
+
+	if !bypassProxy("anything.test", []string{"*"}) {
+		t.Error("Expected * to bypass the proxy for every host")
+	}
+}
+
+// startSOCKS5 runs a minimal SOCKS5 proxy requiring username/password
+// authentication and reports each CONNECT target on the returned channel
+func startSOCKS5(t *testing.T, username, password string) (string, <-chan string) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+
+	requested := make(chan string, 10)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveSOCKS5(conn, username, password, requested)
+		}
+	}()
+
+	return listener.Addr().String(), requested
+}
+
+// serveSOCKS5 handles a single SOCKS5 client connection
+func serveSOCKS5(conn net.Conn, username, password string, requested chan<- string) {
+	defer func() { _ = conn.Close() }()
+
+	// Greeting: version, method count, methods; select username/password
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return
+	}
+	if _, err := io.ReadFull(conn, make([]byte, header[1])); err != nil {
+		return
+	}
+	if _, err := conn.Write([]byte{5, 2}); err != nil {
+		return
+	}
+
+	// Username/password subnegotiation
+	readField := func() string {
+		length := make([]byte, 1)
+		if _, err := io.ReadFull(conn, length); err != nil {
+			return ""
+		}
+		field := make([]byte, length[0])
+		if _, err := io.ReadFull(conn, field); err != nil {
+			return ""
+		}
+		return string(field)
+	}
+	if _, err := io.ReadFull(conn, make([]byte, 1)); err != nil {
+		return
+	}
+	if readField() != username || readField() != password {
+		_, _ = conn.Write([]byte{1, 1})
+		return
+	}
+	if _, err := conn.Write([]byte{1, 0}); err != nil {
+		return
+	}
+
+	// CONNECT request: version, command, reserved, address type, address, port
+	request := make([]byte, 4)
+	if _, err := io.ReadFull(conn, request); err != nil {
+		return
+	}
+	var host string
+	switch request[3] {
+	case 1:
+		addr := make([]byte, 4)
+		if _, err := io.ReadFull(conn, addr); err != nil {
+			return
+		}
+		host = net.IP(addr).String()
+	case 3:
+		host = readField()
+	default:
+		return
+	}
+	port := make([]byte, 2)
+	if _, err := io.ReadFull(conn, port); err != nil {
+		return
+	}
+	target := net.JoinHostPort(host, strconv.Itoa(int(binary.BigEndian.Uint16(port))))
+	requested <- target
+
+	upstream, err := net.Dial("tcp", target)
+	if err != nil {
+		_, _ = conn.Write([]byte{5, 5, 0, 1, 0, 0, 0, 0, 0, 0})
+		return
+	}
+	defer func() { _ = upstream.Close() }()
+	if _, err := conn.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0}); err != nil {
+		return
+	}
+
+	go func() { _, _ = io.Copy(upstream, conn) }()
+	_, _ = io.Copy(conn, upstream)
+}
@@ -0,0 +1,105 @@
+package executor
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"curlex/internal/models"
+)
+
+// timingTrace records connection and response phases via httptrace
+// Hooks may fire from dialer goroutines, so all fields are guarded by mu
+// With redirects, connection phases accumulate across hops and TTFB is
+// measured to the first byte of the final response
+type timingTrace struct {
+	mu sync.Mutex
+
+	start        time.Time
+	dnsStart     time.Time
+	connectStart time.Time
+	tlsStart     time.Time
+	firstByte    time.Time
+
+	dnsLookup    time.Duration
+	tcpConnect   time.Duration
+	tlsHandshake time.Duration
+}
+
+// newTimingTrace creates a trace anchored at the given start time
+func newTimingTrace(start time.Time) *timingTrace {
+	return &timingTrace{start: start}
+}
+
+// withContext attaches the trace hooks to ctx
+func (t *timingTrace) withContext(ctx context.Context) context.Context {
+	return httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			t.mu.Lock()
+			t.dnsStart = time.Now()
+			t.mu.Unlock()
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			t.mu.Lock()
+			if !t.dnsStart.IsZero() {
+				t.dnsLookup += time.Since(t.dnsStart)
+			}
+			t.mu.Unlock()
+		},
+		ConnectStart: func(string, string) {
+			t.mu.Lock()
+			// Keep the earliest start when dialing several addresses in parallel
+			if t.connectStart.IsZero() {
+				t.connectStart = time.Now()
+			}
+			t.mu.Unlock()
+		},
+		ConnectDone: func(_, _ string, err error) {
+			t.mu.Lock()
+			if err == nil && !t.connectStart.IsZero() {
+				t.tcpConnect += time.Since(t.connectStart)
+				t.connectStart = time.Time{}
+			}
+			t.mu.Unlock()
+		},
+		TLSHandshakeStart: func() {
+			t.mu.Lock()
+			t.tlsStart = time.Now()
+			t.mu.Unlock()
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			t.mu.Lock()
+			if !t.tlsStart.IsZero() {
+				t.tlsHandshake += time.Since(t.tlsStart)
+			}
+			t.mu.Unlock()
+		},
+		GotFirstResponseByte: func() {
+			t.mu.Lock()
+			t.firstByte = time.Now()
+			t.mu.Unlock()
+		},
+	})
+}
+
+// finish builds the timing breakdown once the body has been read at end
+func (t *timingTrace) finish(end time.Time) *models.Timing {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	timing := &models.Timing{
+		DNSLookup:    t.dnsLookup,
+		TCPConnect:   t.tcpConnect,
+		TLSHandshake: t.tlsHandshake,
+		Total:        end.Sub(t.start),
+	}
+
+	if !t.firstByte.IsZero() {
+		timing.TTFB = t.firstByte.Sub(t.start)
+		timing.ContentTransfer = end.Sub(t.firstByte)
+	}
+
+	return timing
+}
@@ -0,0 +1,342 @@
+package executor
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"curlex/internal/models"
+)
+
+// needsTransport reports whether a request needs its own transport
+func needsTransport(req *models.PreparedRequest) bool {
+	return req.TLS != nil || req.Proxy != "" || len(req.NoProxy) > 0 || len(req.Resolve) > 0 || req.UnixSocket != ""
+}
+
+// transportKey identifies the settings a transport was built for
+type transportKey struct {
+	tls        models.TLSConfig
+	proxy      string
+	noProxy    string
+	resolve    string // Sorted "host:port=addr" entries
+	unixSocket string
+}
+
+// newTransportKey returns the key for a request's transport settings
+func newTransportKey(req *models.PreparedRequest) transportKey {
+	key := transportKey{
+		proxy:      req.Proxy,
+		noProxy:    strings.Join(req.NoProxy, ","),
+		unixSocket: req.UnixSocket,
+	}
+	if req.TLS != nil {
+		key.tls = *req.TLS
+	}
+
+	entries := make([]string, 0, len(req.Resolve))
+	for hostPort, addr := range req.Resolve {
+		entries = append(entries, hostPort+"="+addr)
+	}
+	sort.Strings(entries)
+	key.resolve = strings.Join(entries, ",")
+
+	return key
+}
+
+// transportFor returns the transport for a request's settings, building it
+// on first use so later requests with the same settings share connections
+func (e *Executor) transportFor(req *models.PreparedRequest) (*http.Transport, error) {
+	key := newTransportKey(req)
+
+	e.transportsMu.Lock()
+	defer e.transportsMu.Unlock()
+
+	if transport, ok := e.transports[key]; ok {
+		return transport, nil
+	}
+
+	transport, err := newTransport(req)
+	if err != nil {
+		return nil, err
+	}
+	e.transports[key] = transport
+	return transport, nil
+}
+
+// newTransport builds a transport applying the request's TLS, proxy, host
+// resolution and Unix socket settings on top of the default transport
+func newTransport(req *models.PreparedRequest) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if req.TLS != nil {
+		tlsConfig, err := buildTLSConfig(req.TLS)
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	if req.Proxy != "" || len(req.NoProxy) > 0 {
+		var proxyURL *url.URL
+		if req.Proxy != "" {
+			var err error
+			proxyURL, err = models.ParseProxyURL(req.Proxy)
+			if err != nil {
+				return nil, err
+			}
+		}
+		// Like curl, an explicit proxy still honors NO_PROXY unless
+		// a no_proxy list is given
+		noProxy := req.NoProxy
+		if noProxy == nil && proxyURL != nil {
+			noProxy = noProxyFromEnvironment()
+		}
+		transport.Proxy = proxyFunc(proxyURL, noProxy)
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+
+	if req.UnixSocket != "" {
+		// Every connection goes to the socket; the URL only supplies the
+		// Host header and path
+		socket := req.UnixSocket
+		transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return dialer.DialContext(ctx, "unix", socket)
+		}
+	} else if len(req.Resolve) > 0 {
+		// Like curl's --resolve, overrides only apply when dialling the
+		// target directly; a proxy is dialled at its own address and
+		// resolves the target itself
+		overrides := req.Resolve
+		proxies := &proxyAddrs{}
+		transport.Proxy = proxies.record(transport.Proxy)
+		transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+			if proxies.contains(addr) {
+				return dialer.DialContext(ctx, network, addr)
+			}
+			// Connect to the override address while keeping the original
+			// host for the Host header and TLS server name
+			host, port, err := net.SplitHostPort(addr)
+			if err != nil {
+				return nil, err
+			}
+			target, ok := overrides[addr]
+			if !ok {
+				// Entries without a port apply to every port
+				target, ok = overrides[host]
+			}
+			if ok {
+				addr = net.JoinHostPort(target, port)
+			}
+			return dialer.DialContext(ctx, network, addr)
+		}
+	}
+
+	return transport, nil
+}
+
+// proxyAddrs records the addresses of the proxies a transport connects
+// through, so its dialer can tell a proxy connection from a direct one
+type proxyAddrs struct {
+	addrs sync.Map // "host:port" -> struct{}
+}
+
+// record wraps a transport proxy function to remember the proxies it returns
+func (p *proxyAddrs) record(proxy func(*http.Request) (*url.URL, error)) func(*http.Request) (*url.URL, error) {
+	if proxy == nil {
+		return nil
+	}
+	return func(req *http.Request) (*url.URL, error) {
+		proxyURL, err := proxy(req)
+		if proxyURL != nil {
+			p.addrs.Store(proxyAddr(proxyURL), struct{}{})
+		}
+		return proxyURL, err
+	}
+}
+
+// contains reports whether addr is the address of a proxy in use
+func (p *proxyAddrs) contains(addr string) bool {
+	_, ok := p.addrs.Load(addr)
+	return ok
+}
+
+// proxyAddr returns the address dialled for a proxy URL, adding the
+// scheme's default port as the transport does
+func proxyAddr(proxyURL *url.URL) string {
+	port := proxyURL.Port()
+	if port == "" {
+		switch proxyURL.Scheme {
+		case "https":
+			port = "443"
+		case "socks5", "socks5h":
+			port = "1080"
+		default:
+			port = "80"
+		}
+	}
+	return net.JoinHostPort(proxyURL.Hostname(), port)
+}
+
+// buildTLSConfig converts TLS settings into a crypto/tls configuration
+func buildTLSConfig(settings *models.TLSConfig) (*tls.Config, error) {
+	config := &tls.Config{
+		InsecureSkipVerify: settings.InsecureSkipVerify,
+		ServerName:         settings.ServerName,
+	}
+
+	minVersion, err := settings.MinTLSVersion()
+	if err != nil {
+		return nil, err
+	}
+	config.MinVersion = minVersion
+
+	if settings.CAFile != "" {
+		pem, err := os.ReadFile(settings.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA file %s", settings.CAFile)
+		}
+		config.RootCAs = pool
+	}
+
+	if settings.CertFile != "" {
+		// curl reads the key from the certificate file when --key is omitted
+		keyFile := settings.KeyFile
+		if keyFile == "" {
+			keyFile = settings.CertFile
+		}
+		cert, err := tls.LoadX509KeyPair(settings.CertFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	} else if settings.KeyFile != "" {
+		return nil, fmt.Errorf("client key %s given without a certificate", settings.KeyFile)
+	}
+
+	return config, nil
+}
+
+// tlsInfo summarizes a response's TLS connection state, or returns nil for
+// plain HTTP responses
+func tlsInfo(state *tls.ConnectionState) *models.TLSInfo {
+	if state == nil {
+		return nil
+	}
+
+	info := &models.TLSInfo{
+		Version:     models.TLSVersionName(state.Version),
+		CipherSuite: tls.CipherSuiteName(state.CipherSuite),
+	}
+
+	// The first peer certificate is the server's leaf certificate
+	if len(state.PeerCertificates) > 0 {
+		leaf := state.PeerCertificates[0]
+		info.Subject = leaf.Subject.CommonName
+		info.Issuer = leaf.Issuer.CommonName
+		info.DNSNames = leaf.DNSNames
+		info.NotBefore = leaf.NotBefore
+		info.NotAfter = leaf.NotAfter
+	}
+
+	return info
+}
+
+// proxyFunc returns a transport proxy function that sends requests through
+// proxyURL, or the environment's proxy if nil, except for hosts in noProxy
+func proxyFunc(proxyURL *url.URL, noProxy []string) func(*http.Request) (*url.URL, error) {
+	return func(req *http.Request) (*url.URL, error) {
+		if bypassProxy(req.URL.Hostname(), noProxy) {
+			return nil, nil
+		}
+		if proxyURL == nil {
+			return http.ProxyFromEnvironment(req)
+		}
+		return proxyURL, nil
+	}
+}
+
+// noProxyFromEnvironment returns the NO_PROXY (or no_proxy) host list
+func noProxyFromEnvironment() []string {
+	value := os.Getenv("NO_PROXY")
+	if value == "" {
+		value = os.Getenv("no_proxy")
+	}
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+// bypassProxy reports whether host matches a no_proxy entry
+// Entries are "*", host names matching themselves and their subdomains
+// (with an optional leading "." or "*."), IP addresses and CIDR ranges
+func bypassProxy(host string, noProxy []string) bool {
+	host = strings.ToLower(host)
+	ip := net.ParseIP(host)
+
+	for _, entry := range noProxy {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+		if entry == "*" {
+			return true
+		}
+		if _, network, err := net.ParseCIDR(entry); err == nil {
+			if ip != nil && network.Contains(ip) {
+				return true
+			}
+			continue
+		}
+
+		entry = strings.TrimPrefix(entry, "*")
+		entry = strings.TrimPrefix(entry, ".")
+		if host == entry || strings.HasSuffix(host, "."+entry) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// decodeBody wraps a response body according to its Content-Encoding
+// Used with --compressed, where curlex sets Accept-Encoding itself and the
+// transport therefore leaves decompression to the caller
+func decodeBody(resp *http.Response) (io.Reader, error) {
+	switch strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding"))) {
+	case "gzip", "x-gzip":
+		return gzip.NewReader(resp.Body)
+	case "deflate":
+		// "deflate" is zlib-wrapped per the spec, but some servers send raw deflate
+		buffered := bufio.NewReader(resp.Body)
+		header, err := buffered.Peek(2)
+		if err == nil && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 && header[0]&0x0f == 8 {
+			return zlib.NewReader(buffered)
+		}
+		return flate.NewReader(buffered), nil
+	default:
+		return resp.Body, nil
+	}
+}
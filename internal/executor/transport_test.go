@@ -0,0 +1,464 @@
+package executor
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"curlex/internal/models"
+)
+
+func TestExecutor_Execute_CurlInsecure(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	executor := NewExecutor(5 * time.Second)
+
+	// The test server's certificate is self-signed, so verification fails
+	result, err := executor.Execute(context.Background(), models.Test{Name: "verify", Curl: "curl " + server.URL})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Error == nil {
+		t.Fatal("Expected certificate verification error without -k")
+	}
+
+	result, err = executor.Execute(context.Background(), models.Test{Name: "insecure", Curl: "curl -k " + server.URL})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Error != nil || result.StatusCode != http.StatusOK {
+		t.Errorf("StatusCode = %d, Error = %v", result.StatusCode, result.Error)
+	}
+}
+
+func TestExecutor_Execute_CurlCACert(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caFile, certPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	executor := NewExecutor(5 * time.Second)
+	result, err := executor.Execute(context.Background(), models.Test{
+		Name: "cacert",
+		Curl: "curl --cacert " + caFile + " " + server.URL,
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Error != nil || result.StatusCode != http.StatusOK {
+		t.Errorf("StatusCode = %d, Error = %v", result.StatusCode, result.Error)
+	}
+
+	// A missing CA file is reported on the result
+	result, _ = executor.Execute(context.Background(), models.Test{
+		Name: "missing cacert",
+		Curl: "curl --cacert " + filepath.Join(t.TempDir(), "missing.pem") + " " + server.URL,
+	})
+	if result.Error == nil || !strings.Contains(result.Error.Error(), "failed to read CA file") {
+		t.Errorf("Error = %v, want CA file error", result.Error)
+	}
+}
+
+// writeCA writes the test server's certificate to dir as a PEM CA bundle
+func writeCA(t *testing.T, server *httptest.Server, dir string) {
+	t.Helper()
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(filepath.Join(dir, "ca.pem"), certPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// writeClientCert writes a self-signed client certificate and key to dir
+func writeClientCert(t *testing.T, dir string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "curlex-client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	if err := os.WriteFile(filepath.Join(dir, "client.pem"), certPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "client-key.pem"), keyPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExecutor_Execute_TLSClientCertificate(t *testing.T) {
+	var gotClient string
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.TLS.PeerCertificates) > 0 {
+			gotClient = r.TLS.PeerCertificates[0].Subject.CommonName
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	server.StartTLS()
+	defer server.Close()
+
+	dir := t.TempDir()
+	writeCA(t, server, dir)
+	writeClientCert(t, dir)
+
+	executor := NewExecutor(5 * time.Second)
+
+	// Relative paths resolve against the test file's directory
+	result, err := executor.Execute(context.Background(), models.Test{
+		Name:    "mtls",
+		Request: &models.StructuredRequest{Method: "GET", URL: server.URL},
+		TLS: &models.TLSConfig{
+			CAFile:   "ca.pem",
+			CertFile: "client.pem",
+			KeyFile:  "client-key.pem",
+		},
+		BaseDir: dir,
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Error != nil || result.StatusCode != http.StatusOK {
+		t.Fatalf("StatusCode = %d, Error = %v", result.StatusCode, result.Error)
+	}
+	if gotClient != "curlex-client" {
+		t.Errorf("client certificate CN = %q, want curlex-client", gotClient)
+	}
+
+	// Without a client certificate the handshake is rejected
+	result, _ = executor.Execute(context.Background(), models.Test{
+		Name:    "no client cert",
+		Request: &models.StructuredRequest{Method: "GET", URL: server.URL},
+		TLS:     &models.TLSConfig{CAFile: "ca.pem"},
+		BaseDir: dir,
+	})
+	if result.Error == nil {
+		t.Error("Expected handshake failure without a client certificate")
+	}
+}
+
+func TestExecutor_Execute_TLSInfo(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	executor := NewExecutor(5 * time.Second)
+	result, err := executor.Execute(context.Background(), models.Test{Name: "tls info", Curl: "curl -k " + server.URL})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.TLS == nil {
+		t.Fatal("Expected TLS info for an HTTPS response")
+	}
+	if result.TLS.Version != "1.3" || result.TLS.CipherSuite == "" {
+		t.Errorf("Version = %q, CipherSuite = %q", result.TLS.Version, result.TLS.CipherSuite)
+	}
+	if !result.TLS.NotAfter.Equal(server.Certificate().NotAfter) {
+		t.Errorf("NotAfter = %v, want %v", result.TLS.NotAfter, server.Certificate().NotAfter)
+	}
+	if len(result.TLS.DNSNames) == 0 || result.TLS.DNSNames[0] != "example.com" {
+		t.Errorf("DNSNames = %v, want the test certificate's names", result.TLS.DNSNames)
+	}
+
+	plain := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer plain.Close()
+	result, _ = executor.Execute(context.Background(), models.Test{Name: "plain", Curl: "curl " + plain.URL})
+	if result.TLS != nil {
+		t.Errorf("Expected no TLS info for plain HTTP, got %+v", result.TLS)
+	}
+}
+
+func TestExecutor_Execute_TLSServerName(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	writeCA(t, server, dir)
+
+	tests := []struct {
+		name       string
+		serverName string
+		wantErr    bool
+	}{
+		// The httptest certificate is issued for example.com
+		{"matching name", "example.com", false},
+		{"mismatched name", "other.example.test", true},
+	}
+
+	executor := NewExecutor(5 * time.Second)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := executor.Execute(context.Background(), models.Test{
+				Name:    tt.name,
+				Request: &models.StructuredRequest{Method: "GET", URL: server.URL},
+				TLS:     &models.TLSConfig{CAFile: "ca.pem", ServerName: tt.serverName},
+				BaseDir: dir,
+			})
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if (result.Error != nil) != tt.wantErr {
+				t.Errorf("Error = %v, wantErr %v", result.Error, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExecutor_Execute_CurlFlagsOverrideTestTLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	executor := NewExecutor(5 * time.Second)
+	result, err := executor.Execute(context.Background(), models.Test{
+		Name: "override",
+		Curl: "curl -k " + server.URL,
+		TLS:  &models.TLSConfig{MinVersion: "1.2"},
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Error != nil || result.StatusCode != http.StatusOK {
+		t.Fatalf("StatusCode = %d, Error = %v", result.StatusCode, result.Error)
+	}
+	if tlsConfig := result.PreparedRequest.TLS; !tlsConfig.InsecureSkipVerify || tlsConfig.MinVersion != "1.2" {
+		t.Errorf("TLS = %+v, want -k merged with the test's min_version", tlsConfig)
+	}
+}
+
+func TestExecutor_TransportReuse(t *testing.T) {
+	executor := NewExecutor(5 * time.Second)
+
+	first, err := executor.transportFor(&models.PreparedRequest{
+		TLS:     &models.TLSConfig{InsecureSkipVerify: true},
+		Resolve: map[string]string{"a.test:443": "127.0.0.1", "b.test:443": "127.0.0.2"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := executor.transportFor(&models.PreparedRequest{
+		TLS:     &models.TLSConfig{InsecureSkipVerify: true},
+		Resolve: map[string]string{"b.test:443": "127.0.0.2", "a.test:443": "127.0.0.1"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first != second {
+		t.Error("Expected requests with the same settings to share a transport")
+	}
+
+	other, err := executor.transportFor(&models.PreparedRequest{
+		TLS: &models.TLSConfig{MinVersion: "1.3"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other == first {
+		t.Error("Expected different TLS settings to use a separate transport")
+	}
+	if other.TLSClientConfig.MinVersion != tls.VersionTLS13 {
+		t.Errorf("MinVersion = %x, want TLS 1.3", other.TLSClientConfig.MinVersion)
+	}
+}
+
+func TestExecutor_Execute_CurlResolve(t *testing.T) {
+	var gotHost string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHost = r.Host
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	_, port, err := net.SplitHostPort(strings.TrimPrefix(server.URL, "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	executor := NewExecutor(5 * time.Second)
+	result, err := executor.Execute(context.Background(), models.Test{
+		Name: "resolve",
+		Curl: "curl --resolve app.example.test:" + port + ":127.0.0.1 http://app.example.test:" + port + "/",
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Error != nil || result.StatusCode != http.StatusOK {
+		t.Fatalf("StatusCode = %d, Error = %v", result.StatusCode, result.Error)
+	}
+	if gotHost != "app.example.test:"+port {
+		t.Errorf("Host = %q, want the original hostname", gotHost)
+	}
+}
+
+func TestExecutor_Execute_CurlCompressed(t *testing.T) {
+	var gotEncoding string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotEncoding = r.Header.Get("Accept-Encoding")
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		_, _ = gz.Write([]byte(`{"ok":true}`))
+		_ = gz.Close()
+		w.Header().Set("Content-Encoding", "gzip")
+		_, _ = w.Write(buf.Bytes())
+	}))
+	defer server.Close()
+
+	executor := NewExecutor(5 * time.Second)
+	// Browser devtools copy the browser's Accept-Encoding along with --compressed
+	result, err := executor.Execute(context.Background(), models.Test{
+		Name: "compressed",
+		Curl: "curl -H 'Accept-Encoding: gzip, deflate, br' --compressed " + server.URL,
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Error != nil {
+		t.Fatalf("Unexpected error: %v", result.Error)
+	}
+	if gotEncoding != "gzip, deflate" {
+		t.Errorf("Accept-Encoding = %q, want gzip, deflate", gotEncoding)
+	}
+	if result.ResponseBody != `{"ok":true}` {
+		t.Errorf("ResponseBody = %q, want decoded JSON", result.ResponseBody)
+	}
+}
+
+func TestDecodeBody_Deflate(t *testing.T) {
+	payload := "hello deflate"
+
+	var zlibBuf bytes.Buffer
+	zw := zlib.NewWriter(&zlibBuf)
+	_, _ = zw.Write([]byte(payload))
+	_ = zw.Close()
+
+	var rawBuf bytes.Buffer
+	fw, _ := flate.NewWriter(&rawBuf, flate.DefaultCompression)
+	_, _ = fw.Write([]byte(payload))
+	_ = fw.Close()
+
+	tests := []struct {
+		name string
+		body []byte
+	}{
+		{"zlib wrapped", zlibBuf.Bytes()},
+		{"raw deflate", rawBuf.Bytes()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{
+				Header: http.Header{"Content-Encoding": []string{"deflate"}},
+				Body:   io.NopCloser(bytes.NewReader(tt.body)),
+			}
+			reader, err := decodeBody(resp)
+			if err != nil {
+				t.Fatalf("decodeBody() error = %v", err)
+			}
+			decoded, err := io.ReadAll(reader)
+			if err != nil {
+				t.Fatalf("ReadAll() error = %v", err)
+			}
+			if string(decoded) != payload {
+				t.Errorf("decoded = %q, want %q", decoded, payload)
+			}
+		})
+	}
+}
+
+func TestExecutor_Execute_CurlLocationOverridesTest(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+
+	redirect := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL, http.StatusFound)
+	}))
+	defer redirect.Close()
+
+	executor := NewExecutor(5 * time.Second)
+	noRedirects := 0
+	result, err := executor.Execute(context.Background(), models.Test{
+		Name:         "location",
+		Curl:         "curl -L " + redirect.URL,
+		MaxRedirects: &noRedirects,
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.StatusCode != http.StatusOK {
+		t.Errorf("StatusCode = %d, want 200 after following the redirect", result.StatusCode)
+	}
+}
+
+func TestExecutor_Execute_CurlMaxTime(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(2 * time.Second):
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+
+	executor := NewExecutor(10 * time.Second)
+	result, err := executor.Execute(context.Background(), models.Test{
+		Name:    "max-time",
+		Curl:    "curl -m 0.1 " + server.URL,
+		Timeout: 5 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if !result.IsTimeout() {
+		t.Fatalf("Error = %v, want timeout", result.Error)
+	}
+	if timeoutErr := result.Error.(*models.TimeoutError); timeoutErr.Timeout != 100*time.Millisecond {
+		t.Errorf("Timeout = %v, want 100ms from -m", timeoutErr.Timeout)
+	}
+}
@@ -0,0 +1,54 @@
+package formdata
+
+import (
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"path/filepath"
+	"strings"
+
+	"curlex/internal/models"
+)
+
+// WriteFile adds a file part with the given content, read by the caller from
+// Path, to a multipart/form-data body
+// The filename defaults to the base name of the path and the content type to
+// one guessed from its extension
+func WriteFile(writer *multipart.Writer, file models.MultipartFile, content []byte) error {
+	filename := file.Filename
+	if filename == "" {
+		filename = filepath.Base(file.Path)
+	}
+
+	contentType := file.ContentType
+	if contentType == "" {
+		contentType = mime.TypeByExtension(filepath.Ext(file.Path))
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+		escapeQuotes(file.Name), escapeQuotes(filename)))
+	header.Set("Content-Type", contentType)
+
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		return fmt.Errorf("failed to encode multipart file %s: %w", file.Name, err)
+	}
+	if _, err := part.Write(content); err != nil {
+		return fmt.Errorf("failed to encode multipart file %s: %w", file.Name, err)
+	}
+
+	return nil
+}
+
+// quoteEscaper escapes quoted-string values in MIME headers
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// escapeQuotes escapes a value for use in a quoted MIME header parameter
+func escapeQuotes(s string) string {
+	return quoteEscaper.Replace(s)
+}
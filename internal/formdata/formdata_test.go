@@ -0,0 +1,74 @@
+package formdata
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"testing"
+
+	"curlex/internal/models"
+)
+
+func TestWriteFile(t *testing.T) {
+	tests := []struct {
+		name            string
+		file            models.MultipartFile
+		wantFilename    string
+		wantContentType string
+	}{
+		{
+			name:            "defaults from the path",
+			file:            models.MultipartFile{Name: "avatar", Path: "images/avatar.png"},
+			wantFilename:    "avatar.png",
+			wantContentType: "image/png",
+		},
+		{
+			name:            "explicit filename and type",
+			file:            models.MultipartFile{Name: "doc", Path: "a.bin", Filename: "report.csv", ContentType: "text/csv"},
+			wantFilename:    "report.csv",
+			wantContentType: "text/csv",
+		},
+		{
+			name:            "unknown extension",
+			file:            models.MultipartFile{Name: "blob", Path: "data.unknownext"},
+			wantFilename:    "data.unknownext",
+			wantContentType: "application/octet-stream",
+		},
+		{
+			name:            "quotes in names",
+			file:            models.MultipartFile{Name: `field"1`, Path: "x.txt", Filename: `my "file".txt`, ContentType: "text/plain"},
+			wantFilename:    `my "file".txt`,
+			wantContentType: "text/plain",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			writer := multipart.NewWriter(&buf)
+			if err := WriteFile(writer, tt.file, []byte("content")); err != nil {
+				t.Fatalf("WriteFile() error = %v", err)
+			}
+			if err := writer.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			part, err := multipart.NewReader(&buf, writer.Boundary()).NextPart()
+			if err != nil {
+				t.Fatalf("NextPart() error = %v", err)
+			}
+			if part.FormName() != tt.file.Name {
+				t.Errorf("FormName() = %q, want %q", part.FormName(), tt.file.Name)
+			}
+			if part.FileName() != tt.wantFilename {
+				t.Errorf("FileName() = %q, want %q", part.FileName(), tt.wantFilename)
+			}
+			if got := part.Header.Get("Content-Type"); got != tt.wantContentType {
+				t.Errorf("Content-Type = %q, want %q", got, tt.wantContentType)
+			}
+			if content, _ := io.ReadAll(part); string(content) != "content" {
+				t.Errorf("content = %q, want %q", content, "content")
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -11,12 +12,26 @@ import (
 type AssertionType string
 
 const (
-	AssertionStatus       AssertionType = "status"
-	AssertionBody         AssertionType = "body"
-	AssertionBodyContains AssertionType = "body_contains"
-	AssertionJSONPath     AssertionType = "json_path"
-	AssertionHeader       AssertionType = "header"
-	AssertionResponseTime AssertionType = "response_time"
+	AssertionStatus          AssertionType = "status"
+	AssertionBody            AssertionType = "body"
+	AssertionBodyContains    AssertionType = "body_contains"
+	AssertionBodyNotContains AssertionType = "body_not_contains"
+	AssertionBodyMatches     AssertionType = "body_matches"
+	AssertionJSONPath        AssertionType = "json_path"
+	AssertionJSONSchema      AssertionType = "json_schema"
+	AssertionXPath           AssertionType = "xpath"
+	AssertionHeader          AssertionType = "header"
+	AssertionResponseTime    AssertionType = "response_time"
+	AssertionTLS             AssertionType = "tls"
+	AssertionCookie          AssertionType = "cookie"
+	AssertionExpr            AssertionType = "expr"
+
+	// Timing phase assertions compare a single phase of the request timing breakdown
+	AssertionDNSLookup       AssertionType = "dns_lookup"
+	AssertionTCPConnect      AssertionType = "tcp_connect"
+	AssertionTLSHandshake    AssertionType = "tls_handshake"
+	AssertionTTFB            AssertionType = "ttfb"
+	AssertionContentTransfer AssertionType = "content_transfer"
 )
 
 // Assertion represents a single test assertion
@@ -31,11 +46,20 @@ type Assertion struct {
 // - json_path: ".data.id == 1"
 func (a *Assertion) UnmarshalYAML(value *yaml.Node) error {
 	// Parse as map to get the assertion type and value
-	var assertionMap map[string]string
-	if err := value.Decode(&assertionMap); err != nil {
+	var nodes map[string]yaml.Node
+	if err := value.Decode(&nodes); err != nil {
 		return fmt.Errorf("failed to decode assertion: %w", err)
 	}
 
+	assertionMap := make(map[string]string, len(nodes))
+	for key, node := range nodes {
+		val, err := assertionValue(strings.TrimSpace(key), &node)
+		if err != nil {
+			return err
+		}
+		assertionMap[key] = val
+	}
+
 	// Should have exactly one key
 	if len(assertionMap) != 1 {
 		return fmt.Errorf("assertion must have exactly one key-value pair, got %d", len(assertionMap))
@@ -53,12 +77,40 @@ func (a *Assertion) UnmarshalYAML(value *yaml.Node) error {
 			a.Type = AssertionBody
 		case "body_contains":
 			a.Type = AssertionBodyContains
+		case "body_not_contains":
+			a.Type = AssertionBodyNotContains
+		case "body_matches":
+			a.Type = AssertionBodyMatches
 		case "json_path":
 			a.Type = AssertionJSONPath
+		case "json_schema":
+			a.Type = AssertionJSONSchema
+		case "json_body":
+			a.Type = AssertionJSONBody
+		case "xpath":
+			a.Type = AssertionXPath
+		case "snapshot":
+			a.Type = AssertionSnapshot
 		case "header":
 			a.Type = AssertionHeader
 		case "response_time":
 			a.Type = AssertionResponseTime
+		case "tls":
+			a.Type = AssertionTLS
+		case "cookie":
+			a.Type = AssertionCookie
+		case "expr":
+			a.Type = AssertionExpr
+		case "dns_lookup":
+			a.Type = AssertionDNSLookup
+		case "tcp_connect":
+			a.Type = AssertionTCPConnect
+		case "tls_handshake":
+			a.Type = AssertionTLSHandshake
+		case "ttfb":
+			a.Type = AssertionTTFB
+		case "content_transfer":
+			a.Type = AssertionContentTransfer
 		default:
 			return fmt.Errorf("unknown assertion type: %s", assertionType)
 		}
@@ -69,6 +121,47 @@ func (a *Assertion) UnmarshalYAML(value *yaml.Node) error {
 	return nil
 }
 
+// structuredAssertions lists the assertion types whose value may be written
+// as a YAML mapping or list, which is stored as JSON
+var structuredAssertions = map[string]bool{
+	"json_schema": true,
+	"json_body":   true,
+	"snapshot":    true,
+}
+
+// assertionValue decodes an assertion's value as a string
+func assertionValue(assertionType string, node *yaml.Node) (string, error) {
+	if node.Kind != yaml.MappingNode && node.Kind != yaml.SequenceNode {
+		var val string
+		if err := node.Decode(&val); err != nil {
+			return "", fmt.Errorf("failed to decode assertion: %w", err)
+		}
+		return val, nil
+	}
+
+	if !structuredAssertions[assertionType] {
+		return "", fmt.Errorf("failed to decode assertion: %s expects a single value", assertionType)
+	}
+
+	var doc any
+	if err := node.Decode(&doc); err != nil {
+		return "", fmt.Errorf("failed to decode %s: %w", assertionType, err)
+	}
+
+	// A json_body mapping holds options; inline documents go under expected
+	if options, ok := doc.(map[string]any); ok && assertionType == "json_body" {
+		if err := checkJSONBodyOptions(options); err != nil {
+			return "", err
+		}
+	}
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode %s: %w", assertionType, err)
+	}
+	return string(data), nil
+}
+
 // String returns a human-readable representation of the assertion
 func (a Assertion) String() string {
 	return fmt.Sprintf("%s: %s", a.Type, a.Value)
@@ -43,6 +43,12 @@ func TestAssertion_UnmarshalYAML(t *testing.T) {
 			expectedValue: ".data.id == 123",
 			shouldError:   false,
 		},
+		{
+			name:          "xpath assertion",
+			yaml:          "xpath: 'count(//item) >= 3'",
+			expectedType:  AssertionXPath,
+			expectedValue: "count(//item) >= 3",
+		},
 		{
 			name:          "header assertion",
 			yaml:          "header: 'Content-Type contains json'",
@@ -50,6 +56,41 @@ func TestAssertion_UnmarshalYAML(t *testing.T) {
 			expectedValue: "Content-Type contains json",
 			shouldError:   false,
 		},
+		{
+			name:          "tls assertion",
+			yaml:          "tls: 'expires_in > 14d'",
+			expectedType:  AssertionTLS,
+			expectedValue: "expires_in > 14d",
+			shouldError:   false,
+		},
+		{
+			name:          "body_matches assertion",
+			yaml:          "body_matches: '(?i)<title>.*welcome'",
+			expectedType:  AssertionBodyMatches,
+			expectedValue: "(?i)<title>.*welcome",
+			shouldError:   false,
+		},
+		{
+			name:          "body_not_contains assertion",
+			yaml:          "body_not_contains: 'Traceback'",
+			expectedType:  AssertionBodyNotContains,
+			expectedValue: "Traceback",
+			shouldError:   false,
+		},
+		{
+			name:          "cookie assertion",
+			yaml:          "cookie: 'session HttpOnly && Secure'",
+			expectedType:  AssertionCookie,
+			expectedValue: "session HttpOnly && Secure",
+			shouldError:   false,
+		},
+		{
+			name:          "expr assertion",
+			yaml:          "expr: 'json.total == len(json.items) && status == 200'",
+			expectedType:  AssertionExpr,
+			expectedValue: "json.total == len(json.items) && status == 200",
+			shouldError:   false,
+		},
 		{
 			name:          "response_time assertion",
 			yaml:          "response_time: '< 500ms'",
@@ -57,6 +98,65 @@ func TestAssertion_UnmarshalYAML(t *testing.T) {
 			expectedValue: "< 500ms",
 			shouldError:   false,
 		},
+		{
+			name:          "ttfb assertion",
+			yaml:          "ttfb: '< 200ms'",
+			expectedType:  AssertionTTFB,
+			expectedValue: "< 200ms",
+			shouldError:   false,
+		},
+		{
+			name:          "tls_handshake assertion",
+			yaml:          "tls_handshake: '< 100ms'",
+			expectedType:  AssertionTLSHandshake,
+			expectedValue: "< 100ms",
+			shouldError:   false,
+		},
+		{
+			name:          "json_schema file",
+			yaml:          "json_schema: schemas/user.json",
+			expectedType:  AssertionJSONSchema,
+			expectedValue: "schemas/user.json",
+			shouldError:   false,
+		},
+		{
+			name:          "json_schema inline mapping",
+			yaml:          "json_schema:\n  type: object\n  required: [id]",
+			expectedType:  AssertionJSONSchema,
+			expectedValue: `{"required":["id"],"type":"object"}`,
+			shouldError:   false,
+		},
+		{
+			name:          "json_body options mapping",
+			yaml:          "json_body:\n  expected: {id: 1}\n  ignore: [.created_at]",
+			expectedType:  AssertionJSONBody,
+			expectedValue: `{"expected":{"id":1},"ignore":[".created_at"]}`,
+			shouldError:   false,
+		},
+		{
+			name:        "json_body mapping without document",
+			yaml:        "json_body:\n  id: 1",
+			shouldError: true,
+		},
+		{
+			name:          "snapshot",
+			yaml:          "snapshot: true",
+			expectedType:  AssertionSnapshot,
+			expectedValue: "true",
+			shouldError:   false,
+		},
+		{
+			name:          "snapshot options mapping",
+			yaml:          "snapshot:\n  mask: [.id]",
+			expectedType:  AssertionSnapshot,
+			expectedValue: `{"mask":[".id"]}`,
+			shouldError:   false,
+		},
+		{
+			name:        "mapping value for scalar assertion",
+			yaml:        "status:\n  code: 200",
+			shouldError: true,
+		},
 		{
 			name:        "unknown assertion type",
 			yaml:        "unknown_type: value",
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CaptureSource represents where a captured value is read from
+type CaptureSource string
+
+const (
+	CaptureJSONPath CaptureSource = "json_path"
+	CaptureXPath    CaptureSource = "xpath"
+	CaptureHeader   CaptureSource = "header"
+	CaptureRegex    CaptureSource = "regex"
+	CaptureStatus   CaptureSource = "status"
+	CaptureCookie   CaptureSource = "cookie"
+)
+
+// AssertionCapture is the failure type reported when a capture cannot be extracted
+const AssertionCapture AssertionType = "capture"
+
+// Capture extracts a value from a response and stores it as a suite variable
+type Capture struct {
+	Source     CaptureSource
+	Expression string
+}
+
+// UnmarshalYAML implements custom YAML unmarshaling for capture definitions
+// Supports the same single-key syntax as assertions:
+// - json_path: ".data.token"
+// - xpath: "//session/@id"
+// - header: "X-Request-Id"
+// - regex: "order-(\\d+)"
+// - cookie: "session_id"
+// - status: true
+func (c *Capture) UnmarshalYAML(value *yaml.Node) error {
+	var captureMap map[string]string
+	if err := value.Decode(&captureMap); err != nil {
+		return fmt.Errorf("failed to decode capture: %w", err)
+	}
+
+	// Should have exactly one key
+	if len(captureMap) != 1 {
+		return fmt.Errorf("capture must have exactly one key-value pair, got %d", len(captureMap))
+	}
+
+	for key, val := range captureMap {
+		switch source := CaptureSource(strings.TrimSpace(key)); source {
+		case CaptureJSONPath, CaptureXPath, CaptureHeader, CaptureRegex, CaptureCookie:
+			if strings.TrimSpace(val) == "" {
+				return fmt.Errorf("capture %s requires a value", source)
+			}
+			c.Source = source
+		case CaptureStatus:
+			c.Source = source
+		default:
+			return fmt.Errorf("unknown capture source: %s", key)
+		}
+
+		c.Expression = val
+	}
+
+	return nil
+}
+
+// String returns a human-readable representation of the capture
+func (c Capture) String() string {
+	if c.Source == CaptureStatus {
+		return string(c.Source)
+	}
+	return fmt.Sprintf("%s: %s", c.Source, c.Expression)
+}
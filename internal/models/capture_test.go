@@ -0,0 +1,93 @@
+package models
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestCapture_UnmarshalYAML(t *testing.T) {
+	tests := []struct {
+		name               string
+		yaml               string
+		expectedSource     CaptureSource
+		expectedExpression string
+		shouldError        bool
+	}{
+		{
+			name:               "json_path capture",
+			yaml:               "json_path: '.data.token'",
+			expectedSource:     CaptureJSONPath,
+			expectedExpression: ".data.token",
+		},
+		{
+			name:               "xpath capture",
+			yaml:               "xpath: '//session/@id'",
+			expectedSource:     CaptureXPath,
+			expectedExpression: "//session/@id",
+		},
+		{
+			name:               "header capture",
+			yaml:               "header: X-Request-Id",
+			expectedSource:     CaptureHeader,
+			expectedExpression: "X-Request-Id",
+		},
+		{
+			name:               "regex capture",
+			yaml:               `regex: 'order-(\d+)'`,
+			expectedSource:     CaptureRegex,
+			expectedExpression: `order-(\d+)`,
+		},
+		{
+			name:               "cookie capture",
+			yaml:               "cookie: session_id",
+			expectedSource:     CaptureCookie,
+			expectedExpression: "session_id",
+		},
+		{
+			name:               "status capture",
+			yaml:               "status: true",
+			expectedSource:     CaptureStatus,
+			expectedExpression: "true",
+		},
+		{
+			name:        "unknown source",
+			yaml:        "css: .token",
+			shouldError: true,
+		},
+		{
+			name:        "missing value",
+			yaml:        "json_path: ''",
+			shouldError: true,
+		},
+		{
+			name:        "multiple keys",
+			yaml:        "header: a\ncookie: b",
+			shouldError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var c Capture
+			err := yaml.Unmarshal([]byte(tt.yaml), &c)
+
+			if tt.shouldError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if c.Source != tt.expectedSource {
+				t.Errorf("Source = %s, want %s", c.Source, tt.expectedSource)
+			}
+			if c.Expression != tt.expectedExpression {
+				t.Errorf("Expression = %s, want %s", c.Expression, tt.expectedExpression)
+			}
+		})
+	}
+}
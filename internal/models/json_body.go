@@ -0,0 +1,81 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// AssertionJSONBody compares the whole response body against an expected JSON document
+const AssertionJSONBody AssertionType = "json_body"
+
+// JSONBody is the expected document of a json_body assertion and the
+// options for comparing it
+type JSONBody struct {
+	Expected         json.RawMessage `json:"expected,omitempty"` // Inline expected document
+	File             string          `json:"file,omitempty"`     // Expected document file, relative to the test file
+	Ignore           []string        `json:"ignore,omitempty"`   // Paths left out of the comparison, e.g. ".id"
+	AllowExtraFields bool            `json:"allow_extra_fields,omitempty"`
+	UnorderedArrays  bool            `json:"unordered_arrays,omitempty"`
+}
+
+// jsonBodyOptions lists the keys of the json_body mapping form
+var jsonBodyOptions = map[string]bool{
+	"expected":           true,
+	"file":               true,
+	"ignore":             true,
+	"allow_extra_fields": true,
+	"unordered_arrays":   true,
+}
+
+// ParseJSONBody decodes the value of a json_body assertion
+// Supports a file path, an inline JSON document, or an object of options
+// with the document under "expected" or "file":
+// - json_body: expected/user.json
+// - json_body: '{"name": "Alice"}'
+// - json_body: '{"expected": {"name": "Alice"}, "ignore": [".id"]}'
+func ParseJSONBody(value string) (JSONBody, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return JSONBody{}, fmt.Errorf("json_body requires a value")
+	}
+	if !strings.HasPrefix(value, "{") && !strings.HasPrefix(value, "[") {
+		return JSONBody{File: value}, nil
+	}
+	if !json.Valid([]byte(value)) {
+		return JSONBody{}, fmt.Errorf("expected body is not valid JSON")
+	}
+
+	// An object is only read as options when it names the document
+	var keys map[string]any
+	if err := json.Unmarshal([]byte(value), &keys); err != nil || checkJSONBodyOptions(keys) != nil {
+		return JSONBody{Expected: json.RawMessage(value)}, nil
+	}
+
+	var body JSONBody
+	decoder := json.NewDecoder(bytes.NewReader([]byte(value)))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&body); err != nil {
+		return JSONBody{}, fmt.Errorf("invalid json_body options: %w", err)
+	}
+	return body, nil
+}
+
+// checkJSONBodyOptions checks the keys of the json_body mapping form
+func checkJSONBodyOptions(keys map[string]any) error {
+	for key := range keys {
+		if !jsonBodyOptions[key] {
+			return fmt.Errorf("json_body: unknown option %q", key)
+		}
+	}
+	_, hasExpected := keys["expected"]
+	_, hasFile := keys["file"]
+	switch {
+	case hasExpected && hasFile:
+		return fmt.Errorf("json_body: expected and file cannot both be set")
+	case !hasExpected && !hasFile:
+		return fmt.Errorf("json_body: expected or file is required")
+	}
+	return nil
+}
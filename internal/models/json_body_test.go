@@ -0,0 +1,86 @@
+package models
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestParseJSONBody(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       string
+		expected    JSONBody
+		shouldError bool
+	}{
+		{
+			name:     "file path",
+			value:    "expected/user.json",
+			expected: JSONBody{File: "expected/user.json"},
+		},
+		{
+			name:     "inline object",
+			value:    `{"id": 1, "name": "Alice"}`,
+			expected: JSONBody{Expected: json.RawMessage(`{"id": 1, "name": "Alice"}`)},
+		},
+		{
+			name:     "inline array",
+			value:    `[1, 2]`,
+			expected: JSONBody{Expected: json.RawMessage(`[1, 2]`)},
+		},
+		{
+			name:  "options",
+			value: `{"expected": {"id": 1}, "ignore": [".id"], "allow_extra_fields": true, "unordered_arrays": true}`,
+			expected: JSONBody{
+				Expected:         json.RawMessage(`{"id": 1}`),
+				Ignore:           []string{".id"},
+				AllowExtraFields: true,
+				UnorderedArrays:  true,
+			},
+		},
+		{
+			name:     "options with file",
+			value:    `{"file": "expected/user.json", "ignore": [".id"]}`,
+			expected: JSONBody{File: "expected/user.json", Ignore: []string{".id"}},
+		},
+		{
+			name:     "document with an expected field",
+			value:    `{"expected": 1, "actual": 2}`,
+			expected: JSONBody{Expected: json.RawMessage(`{"expected": 1, "actual": 2}`)},
+		},
+		{
+			name:        "invalid json",
+			value:       `{"id": `,
+			shouldError: true,
+		},
+		{
+			name:        "invalid option value",
+			value:       `{"expected": {}, "ignore": ".id"}`,
+			shouldError: true,
+		},
+		{
+			name:        "empty",
+			value:       " ",
+			shouldError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, err := ParseJSONBody(tt.value)
+
+			if tt.shouldError {
+				if err == nil {
+					t.Errorf("Expected error, got %+v", body)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(body, tt.expected) {
+				t.Errorf("ParseJSONBody() = %+v, want %+v", body, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package models
+
+// AssertionOpenAPI is the failure type reported when a request or response
+// does not match the suite's OpenAPI document
+const AssertionOpenAPI AssertionType = "openapi"
+
+// OpenAPICoverage reports which documented operations a suite exercised
+type OpenAPICoverage struct {
+	Spec       string              // Path of the OpenAPI document
+	Operations []OperationCoverage // In document order: by path, then method
+}
+
+// OperationCoverage counts the requests matched to one documented operation
+type OperationCoverage struct {
+	Method      string // Upper-case HTTP method
+	Path        string // Path template, e.g. "/users/{id}"
+	OperationID string
+	Requests    int
+}
+
+// Covered returns the number of operations exercised at least once
+func (c OpenAPICoverage) Covered() int {
+	covered := 0
+	for _, op := range c.Operations {
+		if op.Requests > 0 {
+			covered++
+		}
+	}
+	return covered
+}
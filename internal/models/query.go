@@ -0,0 +1,43 @@
+package models
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// QueryParams maps query parameter names to their values
+// Each parameter may be given a single value or a list for repeated keys:
+//
+//	query:
+//	  q: "search term"
+//	  tag: [a, b]
+type QueryParams map[string][]string
+
+// UnmarshalYAML implements custom YAML unmarshaling for query parameters
+func (q *QueryParams) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind != yaml.MappingNode {
+		return fmt.Errorf("query must be a map of parameter names to values")
+	}
+
+	params := make(QueryParams, len(value.Content)/2)
+	for i := 0; i+1 < len(value.Content); i += 2 {
+		keyNode, valueNode := value.Content[i], value.Content[i+1]
+
+		switch valueNode.Kind {
+		case yaml.ScalarNode:
+			params[keyNode.Value] = []string{valueNode.Value}
+		case yaml.SequenceNode:
+			var values []string
+			if err := valueNode.Decode(&values); err != nil {
+				return fmt.Errorf("query parameter %s: values must be scalars", keyNode.Value)
+			}
+			params[keyNode.Value] = values
+		default:
+			return fmt.Errorf("query parameter %s: value must be a scalar or a list", keyNode.Value)
+		}
+	}
+
+	*q = params
+	return nil
+}
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestQueryParams_UnmarshalYAML(t *testing.T) {
+	tests := []struct {
+		name        string
+		yaml        string
+		expected    QueryParams
+		shouldError bool
+	}{
+		{
+			name:     "scalar values",
+			yaml:     "q: search term\npage: 2",
+			expected: QueryParams{"q": {"search term"}, "page": {"2"}},
+		},
+		{
+			name:     "repeated keys as a list",
+			yaml:     "tag: [a, b]\nsort: name",
+			expected: QueryParams{"tag": {"a", "b"}, "sort": {"name"}},
+		},
+		{
+			name:     "empty value",
+			yaml:     "flag: ''",
+			expected: QueryParams{"flag": {""}},
+		},
+		{
+			name:        "nested map value",
+			yaml:        "filter:\n  a: b",
+			shouldError: true,
+		},
+		{
+			name:        "not a map",
+			yaml:        "- a\n- b",
+			shouldError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var params QueryParams
+			err := yaml.Unmarshal([]byte(tt.yaml), &params)
+
+			if tt.shouldError {
+				if err == nil {
+					t.Errorf("Expected error, got %v", params)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(params, tt.expected) {
+				t.Errorf("QueryParams = %v, want %v", params, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// ParseResolve parses a --resolve entry of the form host:port:addr
+// Only the first address of a comma-separated list is used
+func ParseResolve(value string) (string, string, error) {
+	parts := strings.SplitN(value, ":", 3)
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return "", "", fmt.Errorf("invalid --resolve value %q: expected host:port:addr", value)
+	}
+
+	addr, _, _ := strings.Cut(parts[2], ",")
+	addr = strings.TrimSuffix(strings.TrimPrefix(addr, "["), "]")
+
+	return net.JoinHostPort(parts[0], parts[1]), addr, nil
+}
@@ -0,0 +1,37 @@
+package models
+
+import "testing"
+
+func TestParseResolve(t *testing.T) {
+	tests := []struct {
+		name         string
+		value        string
+		wantHostPort string
+		wantAddr     string
+		shouldError  bool
+	}{
+		{name: "IPv4", value: "api.example.com:443:10.0.0.1", wantHostPort: "api.example.com:443", wantAddr: "10.0.0.1"},
+		{name: "bracketed IPv6", value: "api.example.com:443:[::1]", wantHostPort: "api.example.com:443", wantAddr: "::1"},
+		{name: "first of several addresses", value: "api.example.com:80:10.0.0.1,10.0.0.2", wantHostPort: "api.example.com:80", wantAddr: "10.0.0.1"},
+		{name: "missing address", value: "api.example.com:443", shouldError: true},
+		{name: "empty port", value: "api.example.com::10.0.0.1", shouldError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hostPort, addr, err := ParseResolve(tt.value)
+			if tt.shouldError {
+				if err == nil {
+					t.Errorf("ParseResolve(%q) expected error", tt.value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseResolve(%q) error = %v", tt.value, err)
+			}
+			if hostPort != tt.wantHostPort || addr != tt.wantAddr {
+				t.Errorf("ParseResolve(%q) = %q, %q, want %q, %q", tt.value, hostPort, addr, tt.wantHostPort, tt.wantAddr)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
@@ -11,12 +12,67 @@ type TestResult struct {
 	Test            Test
 	Success         bool
 	StatusCode      int
-	ResponseTime    time.Duration
+	ResponseTime    time.Duration // Total time including reading the response body
+	Timing          *Timing       // Per-phase breakdown, nil if the request never completed
+	TLS             *TLSInfo      // Negotiated connection and peer certificate, nil for plain HTTP
 	ResponseBody    string
 	Headers         http.Header
 	Failures        []AssertionFailure
 	Error           error
-	PreparedRequest *PreparedRequest // Request details for logging
+	PreparedRequest *PreparedRequest  // Request details for logging
+	Captured        map[string]string // Variables captured from the response
+	Variables       map[string]string // Variables in scope when expr assertions ran
+	Skipped         bool              // Test was not run because a dependency failed
+	SkipReason      string            // Why the test was skipped
+}
+
+// Timing holds a per-phase breakdown of a request's duration
+// Connection phases are zero when a pooled connection was reused
+type Timing struct {
+	DNSLookup       time.Duration
+	TCPConnect      time.Duration
+	TLSHandshake    time.Duration
+	TTFB            time.Duration // From request start until the first response byte
+	ContentTransfer time.Duration // From the first response byte until the body was read
+	Total           time.Duration
+}
+
+// TLSInfo summarizes a response's TLS connection and the server's leaf certificate
+type TLSInfo struct {
+	Version     string    // Negotiated protocol version, e.g. "1.3"
+	CipherSuite string    // Negotiated cipher suite name
+	Subject     string    // Common name of the certificate subject
+	Issuer      string    // Common name of the certificate issuer
+	DNSNames    []string  // Subject alternative names
+	NotBefore   time.Time // Start of the certificate validity period
+	NotAfter    time.Time // Certificate expiry
+}
+
+// ExpiresIn returns how long the certificate remains valid after now
+func (t *TLSInfo) ExpiresIn(now time.Time) time.Duration {
+	return t.NotAfter.Sub(now)
+}
+
+// TimeoutError reports a request that did not complete within its timeout
+type TimeoutError struct {
+	Timeout time.Duration
+	Err     error
+}
+
+// Error returns a human-readable description of the timeout
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("request timed out after %s", e.Timeout)
+}
+
+// Unwrap returns the underlying transport error
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// IsTimeout returns true if the test failed because its request timed out
+func (r TestResult) IsTimeout() bool {
+	var timeoutErr *TimeoutError
+	return errors.As(r.Error, &timeoutErr)
 }
 
 // AssertionFailure represents a failed assertion with details
@@ -37,16 +93,30 @@ func (f AssertionFailure) String() string {
 
 // SuiteResult represents the overall test suite execution results
 type SuiteResult struct {
-	Results     []TestResult
-	TotalTests  int
-	PassedTests int
-	FailedTests int
-	TotalTime   time.Duration
-	StartTime   time.Time
-	EndTime     time.Time
+	Results         []TestResult
+	SetupResults    []TestResult     // Results of suite setup tests
+	TeardownResults []TestResult     // Results of suite teardown tests
+	Error           error            // Suite-level error, e.g. a failed setup
+	OpenAPICoverage *OpenAPICoverage // Set when the suite validates against an OpenAPI document
+	Snapshots       *SnapshotReport  // Set when snapshot files were written or are obsolete
+	TotalTests      int
+	PassedTests     int
+	FailedTests     int
+	SkippedTests    int
+	TotalTime       time.Duration
+	StartTime       time.Time
+	EndTime         time.Time
 }
 
-// HasFailures returns true if any test failed
+// HasFailures returns true if any test failed, setup errored or teardown failed
 func (sr SuiteResult) HasFailures() bool {
-	return sr.FailedTests > 0
+	if sr.FailedTests > 0 || sr.Error != nil {
+		return true
+	}
+	for _, result := range sr.TeardownResults {
+		if !result.Success {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// AssertionSnapshot compares the response against a snapshot recorded by an earlier run
+const AssertionSnapshot AssertionType = "snapshot"
+
+// Snapshot holds the options of a snapshot assertion
+type Snapshot struct {
+	Name      string   `json:"name,omitempty"`       // Snapshot file name, defaults to the test name
+	Headers   []string `json:"headers,omitempty"`    // Response headers to record, defaults to Content-Type
+	Mask      []string `json:"mask,omitempty"`       // JSON paths whose values are masked, e.g. ".data.id"
+	MaskRegex []string `json:"mask_regex,omitempty"` // Patterns masked in the body and recorded headers
+}
+
+// DefaultSnapshotHeaders are recorded when a snapshot does not list headers
+var DefaultSnapshotHeaders = []string{"Content-Type"}
+
+// ParseSnapshot decodes the value of a snapshot assertion
+// Supports true, a snapshot name, or an object of options:
+// - snapshot: true
+// - snapshot: user-profile
+// - snapshot: '{"mask": [".id"], "headers": ["Content-Type", "Cache-Control"]}'
+func ParseSnapshot(value string) (Snapshot, error) {
+	value = strings.TrimSpace(value)
+	switch {
+	case value == "" || value == "true":
+		return Snapshot{}, nil
+	case value == "false":
+		return Snapshot{}, fmt.Errorf("snapshot cannot be false; remove the assertion instead")
+	case !strings.HasPrefix(value, "{"):
+		return Snapshot{Name: value}, nil
+	}
+
+	var snapshot Snapshot
+	decoder := json.NewDecoder(bytes.NewReader([]byte(value)))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&snapshot); err != nil {
+		return Snapshot{}, fmt.Errorf("invalid snapshot options: %w", err)
+	}
+	return snapshot, nil
+}
+
+// SnapshotName returns the file name, without extension, of a test's snapshot:
+// the snapshot's name or the test name, lower-cased with runs of other
+// characters replaced by "-"
+func SnapshotName(test Test, snapshot Snapshot) string {
+	name := snapshot.Name
+	if name == "" {
+		name = test.Name
+	}
+
+	var sb strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_' {
+			if dash && sb.Len() > 0 {
+				sb.WriteByte('-')
+			}
+			sb.WriteRune(r)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+	if sb.Len() == 0 {
+		return "snapshot"
+	}
+	return sb.String()
+}
+
+// SnapshotReport lists the snapshot files a run wrote and those no test uses
+type SnapshotReport struct {
+	Written  []string // Recorded for the first time or rewritten by --update-snapshots
+	Obsolete []string // Not used by any test in the suite
+	Removed  bool     // Obsolete snapshots were deleted by --update-snapshots
+}
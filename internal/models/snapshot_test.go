@@ -0,0 +1,64 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSnapshot(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       string
+		expected    Snapshot
+		shouldError bool
+	}{
+		{name: "true", value: "true", expected: Snapshot{}},
+		{name: "name", value: "user profile", expected: Snapshot{Name: "user profile"}},
+		{
+			name:     "options",
+			value:    `{"name": "profile", "headers": ["Cache-Control"], "mask": [".id"], "mask_regex": ["\\d+"]}`,
+			expected: Snapshot{Name: "profile", Headers: []string{"Cache-Control"}, Mask: []string{".id"}, MaskRegex: []string{`\d+`}},
+		},
+		{name: "false", value: "false", shouldError: true},
+		{name: "unknown option", value: `{"masks": [".id"]}`, shouldError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			snapshot, err := ParseSnapshot(tt.value)
+
+			if tt.shouldError {
+				if err == nil {
+					t.Errorf("Expected error, got %+v", snapshot)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(snapshot, tt.expected) {
+				t.Errorf("ParseSnapshot() = %+v, want %+v", snapshot, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSnapshotName(t *testing.T) {
+	tests := []struct {
+		testName string
+		snapshot Snapshot
+		want     string
+	}{
+		{"Get user", Snapshot{}, "get-user"},
+		{"GET /users/{id} -> 200", Snapshot{}, "get-users-id-200"},
+		{"list_users", Snapshot{}, "list_users"},
+		{"Get user", Snapshot{Name: "User Profile"}, "user-profile"},
+		{"???", Snapshot{}, "snapshot"},
+	}
+
+	for _, tt := range tests {
+		if got := SnapshotName(Test{Name: tt.testName}, tt.snapshot); got != tt.want {
+			t.Errorf("SnapshotName(%q, %q) = %q, want %q", tt.testName, tt.snapshot.Name, got, tt.want)
+		}
+	}
+}
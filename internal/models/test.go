@@ -1,15 +1,29 @@
 package models
 
-import "time"
+import (
+	"crypto/tls"
+	"fmt"
+	"time"
+)
 
 // TestSuite represents a collection of tests defined in a YAML file
 type TestSuite struct {
-	Version   string            `yaml:"version"`
-	Variables map[string]string `yaml:"variables"`
-	Defaults  DefaultConfig     `yaml:"defaults"`
-	Tests     []Test            `yaml:"tests"`
+	Version     string            `yaml:"version"`
+	Variables   map[string]string `yaml:"variables"`
+	Defaults    DefaultConfig     `yaml:"defaults"`
+	MaxDuration time.Duration     `yaml:"max_duration,omitempty"` // Abort remaining tests once the suite runs this long
+	Cookies     string            `yaml:"cookies,omitempty"`      // "jar" keeps response cookies for later requests
+	OpenAPI     string            `yaml:"openapi,omitempty"`      // OpenAPI document every exchange is validated against
+	Namespaces  map[string]string `yaml:"namespaces,omitempty"`   // XML namespace prefixes for xpath, e.g. soap -> URI
+	Setup       []Test            `yaml:"setup,omitempty"`        // Run in order before tests, regardless of filters
+	Tests       []Test            `yaml:"tests"`
+	Teardown    []Test            `yaml:"teardown,omitempty"` // Always run after tests, even when cancelled
+	Path        string            `yaml:"-"`                  // Test file the suite was loaded from
 }
 
+// CookiesJar is the TestSuite.Cookies mode that shares a cookie jar across tests
+const CookiesJar = "jar"
+
 // DefaultConfig holds default configuration for all tests
 type DefaultConfig struct {
 	Timeout       time.Duration     `yaml:"timeout"`
@@ -18,6 +32,11 @@ type DefaultConfig struct {
 	RetryBackoff  string            `yaml:"retry_backoff,omitempty"`   // "exponential" or "linear"
 	RetryOnStatus []int             `yaml:"retry_on_status,omitempty"` // Status codes to retry on
 	Headers       map[string]string `yaml:"headers"`
+	Query         QueryParams       `yaml:"query,omitempty"`         // Added to structured requests that don't set the parameter
+	TLS           *TLSConfig        `yaml:"tls,omitempty"`           // TLS client settings; tests override individual fields
+	Proxy         *ProxyConfig      `yaml:"proxy,omitempty"`         // Proxy for tests that don't set their own
+	Resolve       map[string]string `yaml:"resolve,omitempty"`       // "host:port" or "host" -> address to connect to
+	UnixSocket    string            `yaml:"unix_socket,omitempty"`   // Connect through a Unix domain socket instead of TCP
 	MaxRedirects  *int              `yaml:"max_redirects,omitempty"` // nil = default (10), 0 = no redirects, -1 = unlimited
 }
 
@@ -27,21 +46,49 @@ type Test struct {
 	Curl          string             `yaml:"curl,omitempty"`
 	Request       *StructuredRequest `yaml:"request,omitempty"`
 	Assertions    []Assertion        `yaml:"assertions"`
+	Capture       map[string]Capture `yaml:"capture,omitempty"`    // Values extracted into variables for later tests
+	DependsOn     []string           `yaml:"depends_on,omitempty"` // Names of tests that must pass before this one runs
 	Timeout       time.Duration      `yaml:"timeout,omitempty"`
 	Retries       int                `yaml:"retries,omitempty"`
 	RetryDelay    time.Duration      `yaml:"retry_delay,omitempty"`     // Delay between retries
 	RetryBackoff  string             `yaml:"retry_backoff,omitempty"`   // "exponential" or "linear"
 	RetryOnStatus []int              `yaml:"retry_on_status,omitempty"` // Status codes to retry on
 	MaxRedirects  *int               `yaml:"max_redirects,omitempty"`   // nil = default (10), 0 = no redirects, -1 = unlimited
+	TLS           *TLSConfig         `yaml:"tls,omitempty"`             // TLS client settings, merged with defaults.tls
+	Proxy         *ProxyConfig       `yaml:"proxy,omitempty"`           // Proxy settings, overriding defaults.proxy and --proxy
+	Resolve       map[string]string  `yaml:"resolve,omitempty"`         // Host resolution overrides, merged with defaults.resolve
+	UnixSocket    string             `yaml:"unix_socket,omitempty"`     // Unix domain socket path, relative to the test file
+	CookieJar     *bool              `yaml:"cookie_jar,omitempty"`      // false keeps this test out of the suite's cookie jar
+	OpenAPI       *bool              `yaml:"openapi,omitempty"`         // false skips the suite's OpenAPI validation for this test
 	Debug         bool               `yaml:"debug,omitempty"`           // Print response headers and body for debugging
+	BaseDir       string             `yaml:"-"`                         // Directory of the test file; relative file paths resolve against it
+	Namespaces    map[string]string  `yaml:"-"`                         // The suite's XML namespace prefixes
 }
 
 // StructuredRequest represents an HTTP request in structured format
 type StructuredRequest struct {
-	Method  string            `yaml:"method"`
-	URL     string            `yaml:"url"`
-	Headers map[string]string `yaml:"headers,omitempty"`
-	Body    string            `yaml:"body,omitempty"`
+	Method    string            `yaml:"method"`
+	URL       string            `yaml:"url"`
+	Headers   map[string]string `yaml:"headers,omitempty"`
+	Body      string            `yaml:"body,omitempty"`
+	BodyFile  string            `yaml:"body_file,omitempty"` // File sent as the body, relative to the test file
+	Query     QueryParams       `yaml:"query,omitempty"`     // Encoded into the URL's query string
+	Form      map[string]string `yaml:"form,omitempty"`      // Sent as application/x-www-form-urlencoded
+	Multipart *MultipartBody    `yaml:"multipart,omitempty"` // Sent as multipart/form-data
+}
+
+// MultipartBody describes a multipart/form-data request body
+type MultipartBody struct {
+	Fields map[string]string `yaml:"fields,omitempty"`
+	Files  []MultipartFile   `yaml:"files,omitempty"`
+}
+
+// MultipartFile is a file part of a multipart/form-data body
+type MultipartFile struct {
+	Name        string `yaml:"name"`                   // Form field name
+	Path        string `yaml:"path"`                   // File to upload, relative to the test file
+	Filename    string `yaml:"filename,omitempty"`     // Defaults to the base name of Path
+	ContentType string `yaml:"content_type,omitempty"` // Defaults to a type guessed from the extension
 }
 
 // PreparedRequest is the internal representation after parsing curl or structured request
@@ -49,5 +96,95 @@ type PreparedRequest struct {
 	Method  string
 	URL     string
 	Headers map[string]string
-	Body    string
+	Body    []byte
+
+	// Structured form bodies, encoded into Body when the HTTP request is built
+	Form      map[string]string
+	Multipart *MultipartBody
+
+	// Client settings taken from curl flags; zero values defer to the test
+	MaxRedirects *int              // -L/--max-redirs
+	Timeout      time.Duration     // -m/--max-time
+	Compressed   bool              // --compressed: request gzip/deflate and decode the response
+	TLS          *TLSConfig        // -k, --cacert, --cert, --key
+	Proxy        string            // -x/--proxy
+	NoProxy      []string          // --noproxy: hosts that bypass the proxy
+	Resolve      map[string]string // --resolve: "host:port" -> address to connect to
+	UnixSocket   string            // --unix-socket: connect through this socket instead of TCP
+}
+
+// TLSConfig holds TLS client settings for a request
+// File paths are relative to the test file
+type TLSConfig struct {
+	CAFile             string `yaml:"ca_file,omitempty"`              // PEM bundle used instead of the system roots
+	CertFile           string `yaml:"cert_file,omitempty"`            // Client certificate for mutual TLS
+	KeyFile            string `yaml:"key_file,omitempty"`             // Private key for CertFile, if not in the same file
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify,omitempty"` // Skip server certificate verification
+	ServerName         string `yaml:"server_name,omitempty"`          // SNI and verification name, defaults to the URL host
+	MinVersion         string `yaml:"min_version,omitempty"`          // "1.0", "1.1", "1.2" or "1.3"
+}
+
+// tlsVersions maps min_version values to crypto/tls versions
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// TLSVersionName returns the min_version style name of a crypto/tls version,
+// e.g. "1.3", or the hex value for versions curlex doesn't know
+func TLSVersionName(version uint16) string {
+	for name, v := range tlsVersions {
+		if v == version {
+			return name
+		}
+	}
+	return fmt.Sprintf("0x%04x", version)
+}
+
+// MinTLSVersion returns the crypto/tls constant for MinVersion, or 0 if unset
+func (c *TLSConfig) MinTLSVersion() (uint16, error) {
+	if c.MinVersion == "" {
+		return 0, nil
+	}
+	version, ok := tlsVersions[c.MinVersion]
+	if !ok {
+		return 0, fmt.Errorf("invalid TLS min_version %q: expected 1.0, 1.1, 1.2 or 1.3", c.MinVersion)
+	}
+	return version, nil
+}
+
+// Merge returns c with empty fields filled in from fallback
+// Either may be nil; InsecureSkipVerify is enabled if either enables it
+func (c *TLSConfig) Merge(fallback *TLSConfig) *TLSConfig {
+	if c == nil && fallback == nil {
+		return nil
+	}
+
+	var merged TLSConfig
+	if c != nil {
+		merged = *c
+	}
+	if fallback == nil {
+		return &merged
+	}
+
+	if merged.CAFile == "" {
+		merged.CAFile = fallback.CAFile
+	}
+	if merged.CertFile == "" && merged.KeyFile == "" {
+		// The certificate and key are only taken as a pair
+		merged.CertFile = fallback.CertFile
+		merged.KeyFile = fallback.KeyFile
+	}
+	if merged.ServerName == "" {
+		merged.ServerName = fallback.ServerName
+	}
+	if merged.MinVersion == "" {
+		merged.MinVersion = fallback.MinVersion
+	}
+	merged.InsecureSkipVerify = merged.InsecureSkipVerify || fallback.InsecureSkipVerify
+
+	return &merged
 }
@@ -0,0 +1,420 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"curlex/internal/models"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"gopkg.in/yaml.v3"
+)
+
+// methods lists the operations of a path item in the order they are reported
+var methods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// Spec is a loaded OpenAPI 3.x document that exchanges are validated against
+// It is safe for concurrent use by parallel tests
+type Spec struct {
+	path       string
+	location   string // Absolute path used as the base of schema references
+	doc        map[string]any
+	basePaths  []string // Server URL paths, longest first
+	operations []*operation
+
+	mu       sync.Mutex
+	compiler *jsonschema.Compiler
+	schemas  map[string]*jsonschema.Schema // Keyed by JSON pointer into doc
+}
+
+// operation is a documented method on a path template
+type operation struct {
+	method  string // Upper-case
+	path    string // Path template, e.g. "/users/{id}"
+	id      string // operationId
+	pointer string // JSON pointer of the operation object
+
+	pattern  *regexp.Regexp
+	params   int // Templated segments; fewer wins when several paths match
+	literal  int // Literal characters; more wins on a tie
+	requests int
+}
+
+// templateParam matches a parameter in a path template
+var templateParam = regexp.MustCompile(`\{[^}/]+\}`)
+
+// Load reads an OpenAPI 3.0 or 3.1 document in YAML or JSON
+func Load(path string) (*Spec, error) {
+	location, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := readDocument(location)
+	if err != nil {
+		return nil, err
+	}
+	doc, ok := parsed.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%s: expected an OpenAPI document object", path)
+	}
+
+	version, _ := doc["openapi"].(string)
+	if !strings.HasPrefix(version, "3.") {
+		return nil, fmt.Errorf("%s: unsupported OpenAPI version %q: expected 3.0 or 3.1", path, version)
+	}
+
+	s := &Spec{
+		path:     path,
+		location: location,
+		doc:      doc,
+		compiler: jsonschema.NewCompiler(),
+		schemas:  make(map[string]*jsonschema.Schema),
+	}
+
+	// 3.0 schemas are a Draft 4 dialect with nullable; 3.1 schemas are Draft 2020-12
+	draft := jsonschema.Draft2020
+	legacy := strings.HasPrefix(version, "3.0")
+	if legacy {
+		draft = jsonschema.Draft4
+		s.doc = convertNullable(doc).(map[string]any)
+	}
+	s.compiler.DefaultDraft(draft)
+	s.compiler.UseLoader(documentLoader{legacy: legacy})
+	if err := s.compiler.AddResource(location, s.doc); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	s.basePaths = serverBasePaths(s.doc)
+	if err := s.loadOperations(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return s, nil
+}
+
+// loadOperations indexes every operation under paths
+func (s *Spec) loadOperations() error {
+	paths, _ := s.doc["paths"].(map[string]any)
+
+	templates := make([]string, 0, len(paths))
+	for template := range paths {
+		templates = append(templates, template)
+	}
+	sort.Strings(templates)
+
+	for _, template := range templates {
+		itemPointer := "/paths/" + escapePointer(template)
+		item, _, err := s.resolve(itemPointer)
+		if err != nil {
+			return err
+		}
+		itemMap, _ := item.(map[string]any)
+
+		pattern, params, literal := compileTemplate(template)
+		for _, method := range methods {
+			op, ok := itemMap[method].(map[string]any)
+			if !ok {
+				continue
+			}
+			id, _ := op["operationId"].(string)
+			s.operations = append(s.operations, &operation{
+				method:  strings.ToUpper(method),
+				path:    template,
+				id:      id,
+				pointer: itemPointer + "/" + method,
+				pattern: pattern,
+				params:  params,
+				literal: literal,
+			})
+		}
+	}
+	return nil
+}
+
+// compileTemplate turns a path template into a regular expression where each
+// parameter matches one path segment
+func compileTemplate(template string) (*regexp.Regexp, int, int) {
+	var pattern strings.Builder
+	pattern.WriteString("^")
+
+	params, literal, last := 0, 0, 0
+	for _, loc := range templateParam.FindAllStringIndex(template, -1) {
+		pattern.WriteString(regexp.QuoteMeta(template[last:loc[0]]))
+		pattern.WriteString(`[^/]+`)
+		literal += loc[0] - last
+		params++
+		last = loc[1]
+	}
+	pattern.WriteString(regexp.QuoteMeta(template[last:]))
+	pattern.WriteString("$")
+	literal += len(template) - last
+
+	return regexp.MustCompile(pattern.String()), params, literal
+}
+
+// serverBasePaths returns the path prefixes of the document's server URLs,
+// with server variables replaced by their defaults
+func serverBasePaths(doc map[string]any) []string {
+	servers, _ := doc["servers"].([]any)
+
+	var basePaths []string
+	for _, server := range servers {
+		serverMap, _ := server.(map[string]any)
+		raw, _ := serverMap["url"].(string)
+		variables, _ := serverMap["variables"].(map[string]any)
+		raw = templateParam.ReplaceAllStringFunc(raw, func(param string) string {
+			variable, _ := variables[param[1:len(param)-1]].(map[string]any)
+			value, _ := variable["default"].(string)
+			return value
+		})
+
+		u, err := url.Parse(raw)
+		if err != nil {
+			continue
+		}
+		if base := strings.TrimSuffix(u.Path, "/"); base != "" {
+			basePaths = append(basePaths, base)
+		}
+	}
+
+	sort.Slice(basePaths, func(i, j int) bool { return len(basePaths[i]) > len(basePaths[j]) })
+	return basePaths
+}
+
+// match finds the operation for a request
+// Server base paths are stripped first; the path as requested is tried last
+func (s *Spec) match(method, rawURL string) (*operation, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request URL: %w", err)
+	}
+	requestPath := u.Path
+	if requestPath == "" {
+		requestPath = "/"
+	}
+
+	var candidates []string
+	for _, base := range s.basePaths {
+		if rest, ok := strings.CutPrefix(requestPath, base); ok && (rest == "" || rest[0] == '/') {
+			if rest == "" {
+				rest = "/"
+			}
+			candidates = append(candidates, rest)
+		}
+	}
+	candidates = append(candidates, requestPath)
+
+	for _, candidate := range candidates {
+		var best, bestPath *operation
+		for _, op := range s.operations {
+			if !op.pattern.MatchString(candidate) {
+				continue
+			}
+			if bestPath == nil || moreSpecific(op, bestPath) {
+				bestPath = op
+			}
+			if op.method == method && (best == nil || moreSpecific(op, best)) {
+				best = op
+			}
+		}
+		if best != nil {
+			return best, nil
+		}
+		if bestPath != nil {
+			return nil, fmt.Errorf("%s is not documented for %s", method, bestPath.path)
+		}
+	}
+
+	return nil, fmt.Errorf("no operation matches %s %s", method, requestPath)
+}
+
+// moreSpecific reports whether a's path template is a better match than b's
+func moreSpecific(a, b *operation) bool {
+	if a.params != b.params {
+		return a.params < b.params
+	}
+	return a.literal > b.literal
+}
+
+// resolve returns the node at pointer, following local $refs
+func (s *Spec) resolve(pointer string) (any, string, error) {
+	node, err := lookupPointer(s.doc, pointer)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Guard against reference cycles
+	for range 32 {
+		nodeMap, ok := node.(map[string]any)
+		if !ok {
+			return node, pointer, nil
+		}
+		ref, ok := nodeMap["$ref"].(string)
+		if !ok {
+			return node, pointer, nil
+		}
+		if !strings.HasPrefix(ref, "#") {
+			return nil, "", fmt.Errorf("external $ref %q is only supported in schemas", ref)
+		}
+		pointer = ref[1:]
+		if node, err = lookupPointer(s.doc, pointer); err != nil {
+			return nil, "", err
+		}
+	}
+	return nil, "", fmt.Errorf("too many $ref hops at %s", pointer)
+}
+
+// schema returns the compiled schema at pointer, compiling it on first use
+func (s *Spec) schema(pointer string) (*jsonschema.Schema, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if schema, ok := s.schemas[pointer]; ok {
+		return schema, nil
+	}
+	schema, err := s.compiler.Compile(s.location + "#" + pointer)
+	if err != nil {
+		return nil, err
+	}
+	s.schemas[pointer] = schema
+	return schema, nil
+}
+
+// record counts a request matched to op
+func (s *Spec) record(op *operation) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	op.requests++
+}
+
+// Coverage reports how often each documented operation was exercised
+func (s *Spec) Coverage() *models.OpenAPICoverage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	coverage := &models.OpenAPICoverage{Spec: s.path}
+	for _, op := range s.operations {
+		coverage.Operations = append(coverage.Operations, models.OperationCoverage{
+			Method:      op.method,
+			Path:        op.path,
+			OperationID: op.id,
+			Requests:    op.requests,
+		})
+	}
+	return coverage
+}
+
+// lookupPointer returns the node at an RFC 6901 JSON pointer
+func lookupPointer(doc any, pointer string) (any, error) {
+	if pointer == "" {
+		return doc, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q", pointer)
+	}
+
+	node := doc
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		switch n := node.(type) {
+		case map[string]any:
+			child, ok := n[token]
+			if !ok {
+				return nil, fmt.Errorf("%s not found in document", pointer)
+			}
+			node = child
+		case []any:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(n) {
+				return nil, fmt.Errorf("%s not found in document", pointer)
+			}
+			node = n[i]
+		default:
+			return nil, fmt.Errorf("%s not found in document", pointer)
+		}
+	}
+	return node, nil
+}
+
+// escapePointer escapes a JSON pointer token
+func escapePointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+// readDocument reads a YAML or JSON file as a JSON value with json.Number numbers
+func readDocument(path string) (any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read OpenAPI document: %w", err)
+	}
+
+	// YAML is a superset of JSON, so both go through the YAML decoder
+	var raw any
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	encoded, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return jsonschema.UnmarshalJSON(bytes.NewReader(encoded))
+}
+
+// convertNullable rewrites OpenAPI 3.0 "nullable: true" as JSON Schema null types
+func convertNullable(node any) any {
+	switch n := node.(type) {
+	case map[string]any:
+		for key, child := range n {
+			n[key] = convertNullable(child)
+		}
+		if nullable, _ := n["nullable"].(bool); !nullable {
+			return n
+		}
+		delete(n, "nullable")
+
+		if enum, ok := n["enum"].([]any); ok {
+			n["enum"] = append(enum, nil)
+		}
+		if typ, ok := n["type"].(string); ok {
+			n["type"] = []any{typ, "null"}
+			return n
+		}
+		// $ref and composition keywords are wrapped so null is allowed too
+		return map[string]any{"anyOf": []any{n, map[string]any{"type": "null"}}}
+	case []any:
+		for i, child := range n {
+			n[i] = convertNullable(child)
+		}
+	}
+	return node
+}
+
+// documentLoader loads schema files referenced from the document, which may
+// be YAML as well as JSON
+type documentLoader struct {
+	legacy bool // Apply OpenAPI 3.0 conversions
+}
+
+// Load reads the document at a file URL
+func (l documentLoader) Load(rawURL string) (any, error) {
+	path, err := jsonschema.FileLoader{}.ToFile(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("only local $refs are supported: %w", err)
+	}
+	doc, err := readDocument(path)
+	if err != nil {
+		return nil, err
+	}
+	if l.legacy {
+		doc = convertNullable(doc)
+	}
+	return doc, nil
+}
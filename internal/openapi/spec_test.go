@@ -0,0 +1,304 @@
+package openapi
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"curlex/internal/models"
+)
+
+const petstore = `openapi: 3.0.3
+info:
+  title: Pets
+  version: "1"
+servers:
+  - url: https://api.example.com/v1
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      responses:
+        "200":
+          description: ok
+          headers:
+            X-Total:
+              required: true
+              schema:
+                type: integer
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: "#/components/schemas/Pet"
+    post:
+      operationId: createPet
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: "#/components/schemas/Pet"
+      responses:
+        "201":
+          description: created
+        4XX:
+          description: client error
+  /pets/{id}:
+    get:
+      operationId: getPet
+      responses:
+        "200":
+          $ref: "#/components/responses/Pet"
+        default:
+          description: error
+  /pets/mine:
+    get:
+      operationId: myPets
+      responses:
+        "200":
+          description: ok
+components:
+  responses:
+    Pet:
+      description: a pet
+      content:
+        application/json:
+          schema:
+            $ref: "#/components/schemas/Pet"
+  schemas:
+    Pet:
+      type: object
+      required: [id, name]
+      properties:
+        id:
+          type: integer
+        name:
+          type: string
+        tag:
+          type: string
+          nullable: true
+`
+
+func loadSpec(t *testing.T, content string) *Spec {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "openapi.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	spec, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	return spec
+}
+
+func exchange(method, url string, reqBody string, status int, headers http.Header, body string) *models.TestResult {
+	if headers == nil {
+		headers = http.Header{}
+	}
+	return &models.TestResult{
+		PreparedRequest: &models.PreparedRequest{Method: method, URL: url, Body: []byte(reqBody)},
+		StatusCode:      status,
+		Headers:         headers,
+		ResponseBody:    body,
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	dir := t.TempDir()
+	swagger := filepath.Join(dir, "swagger.yaml")
+	if err := os.WriteFile(swagger, []byte("swagger: \"2.0\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Load(filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Error("Expected error for missing file")
+	}
+	if _, err := Load(swagger); err == nil || !strings.Contains(err.Error(), "unsupported OpenAPI version") {
+		t.Errorf("Expected unsupported version error, got %v", err)
+	}
+}
+
+func TestSpec_Match(t *testing.T) {
+	spec := loadSpec(t, petstore)
+
+	tests := []struct {
+		method  string
+		url     string
+		want    string
+		wantErr string
+	}{
+		{"GET", "https://api.example.com/v1/pets", "/pets", ""},
+		{"GET", "https://api.example.com/v1/pets?limit=2", "/pets", ""},
+		{"GET", "https://api.example.com/v1/pets/42", "/pets/{id}", ""},
+		{"GET", "https://api.example.com/v1/pets/mine", "/pets/mine", ""},
+		{"GET", "http://localhost:8080/pets/7", "/pets/{id}", ""}, // Server base path not used
+		{"DELETE", "https://api.example.com/v1/pets/42", "", "DELETE is not documented for /pets/{id}"},
+		{"GET", "https://api.example.com/v1/owners", "", "no operation matches GET /v1/owners"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.url, func(t *testing.T) {
+			op, err := spec.match(tt.method, tt.url)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("match() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("match() error = %v", err)
+			}
+			if op.path != tt.want {
+				t.Errorf("match() = %s, want %s", op.path, tt.want)
+			}
+		})
+	}
+}
+
+func TestSpec_Validate(t *testing.T) {
+	spec := loadSpec(t, petstore)
+	jsonHeaders := func(extra ...string) http.Header {
+		h := http.Header{"Content-Type": {"application/json; charset=utf-8"}}
+		for i := 0; i < len(extra); i += 2 {
+			h.Set(extra[i], extra[i+1])
+		}
+		return h
+	}
+
+	tests := []struct {
+		name   string
+		result *models.TestResult
+		want   []string // Failure messages, in order
+	}{
+		{
+			name:   "valid list",
+			result: exchange("GET", "https://api.example.com/v1/pets", "", 200, jsonHeaders("X-Total", "1"), `[{"id": 1, "name": "Rex", "tag": null}]`),
+		},
+		{
+			name:   "invalid item and missing header",
+			result: exchange("GET", "https://api.example.com/v1/pets", "", 200, jsonHeaders(), `[{"id": "1"}]`),
+			want: []string{
+				"GET /pets: response header X-Total is required",
+				"GET /pets: response body /0: missing property 'name'",
+				"GET /pets: response body /0/id: got string, want integer",
+			},
+		},
+		{
+			name:   "header of the wrong type",
+			result: exchange("GET", "https://api.example.com/v1/pets", "", 200, jsonHeaders("X-Total", "many"), `[]`),
+			want:   []string{"GET /pets: response header X-Total: got string, want integer"},
+		},
+		{
+			name:   "undocumented status",
+			result: exchange("GET", "https://api.example.com/v1/pets", "", 500, nil, ""),
+			want:   []string{"GET /pets: status 500 is not documented"},
+		},
+		{
+			name:   "status range and missing request body",
+			result: exchange("POST", "https://api.example.com/v1/pets", "", 400, nil, ""),
+			want:   []string{"POST /pets: request body is required"},
+		},
+		{
+			name:   "invalid request body",
+			result: exchange("POST", "https://api.example.com/v1/pets", `{"name": 5}`, 201, nil, ""),
+			want: []string{
+				"POST /pets: request body: missing property 'id'",
+				"POST /pets: request body /name: got number, want string",
+			},
+		},
+		{
+			name:   "default response without content",
+			result: exchange("GET", "https://api.example.com/v1/pets/1", "", 404, jsonHeaders(), `{"error": "gone"}`),
+			want:   []string{"GET /pets/{id}: response body is not documented"},
+		},
+		{
+			name:   "response ref",
+			result: exchange("GET", "https://api.example.com/v1/pets/1", "", 200, jsonHeaders(), `{"id": 1, "name": "Rex", "tag": 3}`),
+			want:   []string{"GET /pets/{id}: response body /tag: got number, want null or string"},
+		},
+		{
+			name:   "undocumented content type",
+			result: exchange("GET", "https://api.example.com/v1/pets/1", "", 200, http.Header{"Content-Type": {"text/plain"}}, "Rex"),
+			want:   []string{`GET /pets/{id}: response body content type "text/plain" is not documented`},
+		},
+		{
+			name:   "unmatched request",
+			result: exchange("PUT", "https://api.example.com/v1/pets", "", 200, nil, ""),
+			want:   []string{"PUT is not documented for /pets"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failures := spec.Validate(tt.result)
+			if len(failures) != len(tt.want) {
+				t.Fatalf("Validate() = %v, want %d failures", failures, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if failures[i].Type != models.AssertionOpenAPI {
+					t.Errorf("failure %d type = %s", i, failures[i].Type)
+				}
+				if failures[i].Message != want {
+					t.Errorf("failure %d = %q, want %q", i, failures[i].Message, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSpec_Coverage(t *testing.T) {
+	spec := loadSpec(t, petstore)
+	spec.Validate(exchange("GET", "https://api.example.com/v1/pets/1", "", 200, nil, ""))
+	spec.Validate(exchange("GET", "https://api.example.com/v1/pets/2", "", 200, nil, ""))
+	spec.Validate(exchange("POST", "https://api.example.com/v1/pets", `{"id": 1, "name": "Rex"}`, 201, nil, ""))
+
+	coverage := spec.Coverage()
+	got := map[string]int{}
+	for _, op := range coverage.Operations {
+		got[op.Method+" "+op.Path] = op.Requests
+	}
+	want := map[string]int{"GET /pets": 0, "POST /pets": 1, "GET /pets/mine": 0, "GET /pets/{id}": 2}
+	if len(got) != len(want) {
+		t.Fatalf("Coverage() = %v, want %v", got, want)
+	}
+	for key, n := range want {
+		if got[key] != n {
+			t.Errorf("%s requests = %d, want %d", key, got[key], n)
+		}
+	}
+	if coverage.Covered() != 2 {
+		t.Errorf("Covered() = %d, want 2", coverage.Covered())
+	}
+}
+
+func TestSpec_OpenAPI31(t *testing.T) {
+	spec := loadSpec(t, `openapi: 3.1.0
+info: {title: t, version: "1"}
+paths:
+  /items:
+    get:
+      responses:
+        "200":
+          description: ok
+          content:
+            application/problem+json:
+              schema:
+                type: [object, "null"]
+                properties:
+                  count: {type: integer, minimum: 0}
+`)
+
+	ok := exchange("GET", "http://localhost/items", "", 200, http.Header{"Content-Type": {"application/problem+json"}}, `null`)
+	if failures := spec.Validate(ok); len(failures) != 0 {
+		t.Errorf("Expected null to be valid, got %v", failures)
+	}
+	bad := exchange("GET", "http://localhost/items", "", 200, http.Header{"Content-Type": {"application/problem+json"}}, `{"count": -1}`)
+	if failures := spec.Validate(bad); len(failures) != 1 || !strings.Contains(failures[0].Message, "/count") {
+		t.Errorf("Expected a /count failure, got %v", failures)
+	}
+}
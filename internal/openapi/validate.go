@@ -0,0 +1,312 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"curlex/internal/assertion"
+	"curlex/internal/models"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// Validate matches the test's request to a documented operation and checks
+// the request body and the response status, headers and body against it
+// Every mismatch is returned as an openapi assertion failure
+func (s *Spec) Validate(result *models.TestResult) []models.AssertionFailure {
+	req := result.PreparedRequest
+	if req == nil {
+		return nil
+	}
+
+	method := strings.ToUpper(req.Method)
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	op, err := s.match(method, req.URL)
+	if err != nil {
+		return []models.AssertionFailure{{
+			Type:     models.AssertionOpenAPI,
+			Expected: "request to match an operation in " + s.path,
+			Actual:   fmt.Sprintf("%s %s", method, req.URL),
+			Message:  err.Error(),
+		}}
+	}
+	s.record(op)
+
+	v := &exchangeValidator{spec: s, label: fmt.Sprintf("%s %s", op.method, op.path)}
+	v.validateRequest(op, req)
+	v.validateResponse(op, result)
+	return v.failures
+}
+
+// exchangeValidator collects the failures for a single request and response
+type exchangeValidator struct {
+	spec     *Spec
+	label    string // Operation reported in messages, e.g. "GET /users/{id}"
+	failures []models.AssertionFailure
+}
+
+// fail records a mismatch for the operation
+func (v *exchangeValidator) fail(expected, actual, format string, args ...any) {
+	v.failures = append(v.failures, models.AssertionFailure{
+		Type:     models.AssertionOpenAPI,
+		Expected: expected,
+		Actual:   actual,
+		Message:  v.label + ": " + fmt.Sprintf(format, args...),
+	})
+}
+
+// validateRequest checks the request body against the operation's requestBody
+func (v *exchangeValidator) validateRequest(op *operation, req *models.PreparedRequest) {
+	if _, err := lookupPointer(v.spec.doc, op.pointer+"/requestBody"); err != nil {
+		return
+	}
+	requestBody, pointer, err := v.spec.resolve(op.pointer + "/requestBody")
+	if err != nil {
+		v.fail("valid requestBody", err.Error(), "invalid OpenAPI document: %v", err)
+		return
+	}
+	requestMap, _ := requestBody.(map[string]any)
+
+	// Form and multipart bodies are encoded later and are not checked
+	if len(req.Body) == 0 {
+		if required, _ := requestMap["required"].(bool); required && req.Form == nil && req.Multipart == nil {
+			v.fail("request body", "no body", "request body is required")
+		}
+		return
+	}
+
+	contentType := ""
+	for name, value := range req.Headers {
+		if strings.EqualFold(name, "Content-Type") {
+			contentType = value
+		}
+	}
+	if contentType == "" && json.Valid(req.Body) {
+		contentType = "application/json"
+	}
+
+	v.validateContent("request body", requestMap, pointer, contentType, req.Body)
+}
+
+// validateResponse checks the response status, headers and body
+func (v *exchangeValidator) validateResponse(op *operation, result *models.TestResult) {
+	responses, _, err := v.spec.resolve(op.pointer + "/responses")
+	if err != nil {
+		v.fail("documented responses", err.Error(), "invalid OpenAPI document: %v", err)
+		return
+	}
+	responsesMap, _ := responses.(map[string]any)
+
+	key, ok := responseKey(responsesMap, result.StatusCode)
+	if !ok {
+		v.fail(strings.Join(sortedKeys(responsesMap), ", "), strconv.Itoa(result.StatusCode),
+			"status %d is not documented", result.StatusCode)
+		return
+	}
+
+	response, pointer, err := v.spec.resolve(op.pointer + "/responses/" + escapePointer(key))
+	if err != nil {
+		v.fail("valid response "+key, err.Error(), "invalid OpenAPI document: %v", err)
+		return
+	}
+	responseMap, _ := response.(map[string]any)
+
+	v.validateHeaders(responseMap, pointer, result.Headers)
+	v.validateContent("response body", responseMap, pointer, result.Headers.Get("Content-Type"), []byte(result.ResponseBody))
+}
+
+// validateHeaders checks required response headers and their schemas
+func (v *exchangeValidator) validateHeaders(response map[string]any, pointer string, headers http.Header) {
+	documented, _ := response["headers"].(map[string]any)
+
+	for _, name := range sortedKeys(documented) {
+		// Content-Type is described by content, not headers
+		if strings.EqualFold(name, "Content-Type") {
+			continue
+		}
+
+		header, headerPointer, err := v.spec.resolve(pointer + "/headers/" + escapePointer(name))
+		if err != nil {
+			v.fail("valid header "+name, err.Error(), "invalid OpenAPI document: %v", err)
+			continue
+		}
+		headerMap, _ := header.(map[string]any)
+
+		values := headers.Values(name)
+		if len(values) == 0 {
+			if required, _ := headerMap["required"].(bool); required {
+				v.fail(fmt.Sprintf("header %q to exist", name), "header not found", "response header %s is required", name)
+			}
+			continue
+		}
+		if _, ok := headerMap["schema"]; !ok {
+			continue
+		}
+
+		schemaPointer := headerPointer + "/schema"
+		value := v.headerValue(schemaPointer, strings.Join(values, ", "))
+		v.validateSchema("response header "+name, schemaPointer, value)
+	}
+}
+
+// headerValue converts a header string to the JSON type its schema expects
+func (v *exchangeValidator) headerValue(schemaPointer, raw string) any {
+	schema, _, err := v.spec.resolve(schemaPointer)
+	if err != nil {
+		return raw
+	}
+	schemaMap, _ := schema.(map[string]any)
+
+	switch schemaType(schemaMap) {
+	case "integer", "number":
+		if _, err := strconv.ParseFloat(raw, 64); err == nil {
+			return json.Number(raw)
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(raw); err == nil {
+			return b
+		}
+	case "array":
+		var items []any
+		for _, item := range strings.Split(raw, ",") {
+			items = append(items, strings.TrimSpace(item))
+		}
+		return items
+	}
+	return raw
+}
+
+// validateContent checks a body's media type and, for JSON, its schema
+func (v *exchangeValidator) validateContent(what string, owner map[string]any, pointer, contentType string, body []byte) {
+	content, _ := owner["content"].(map[string]any)
+	if len(content) == 0 {
+		if len(bytes.TrimSpace(body)) > 0 {
+			v.fail("no "+what, truncate(string(body)), "%s is not documented", what)
+		}
+		return
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = strings.ToLower(strings.TrimSpace(contentType))
+	}
+	key, ok := mediaTypeKey(content, mediaType)
+	if !ok {
+		v.fail(strings.Join(sortedKeys(content), ", "), contentType, "%s content type %q is not documented", what, contentType)
+		return
+	}
+
+	media, _ := content[key].(map[string]any)
+	if _, ok := media["schema"]; !ok || !isJSON(mediaType) {
+		return
+	}
+
+	value, err := jsonschema.UnmarshalJSON(bytes.NewReader(body))
+	if err != nil {
+		v.fail("JSON "+what, truncate(string(body)), "%s is not valid JSON: %v", what, err)
+		return
+	}
+	v.validateSchema(what, pointer+"/content/"+escapePointer(key)+"/schema", value)
+}
+
+// validateSchema validates value against the schema at pointer
+func (v *exchangeValidator) validateSchema(what, pointer string, value any) {
+	schema, err := v.spec.schema(pointer)
+	if err != nil {
+		v.fail("valid schema at #"+pointer, err.Error(), "invalid schema in OpenAPI document: %v", err)
+		return
+	}
+	if err := schema.Validate(value); err != nil {
+		for _, violation := range assertion.SchemaViolations(err) {
+			location := what
+			if violation.Pointer != "/" {
+				location += " " + violation.Pointer
+			}
+			v.fail(fmt.Sprintf("%s to satisfy %s", location, violation.Keyword), violation.Reason,
+				"%s: %s", location, violation.Reason)
+		}
+	}
+}
+
+// responseKey picks the documented response for a status code: the exact
+// code, then its range such as "2XX", then "default"
+func responseKey(responses map[string]any, status int) (string, bool) {
+	code := strconv.Itoa(status)
+	if _, ok := responses[code]; ok {
+		return code, true
+	}
+	for key := range responses {
+		if len(key) == 3 && key[0] == code[0] && strings.EqualFold(key[1:], "XX") {
+			return key, true
+		}
+	}
+	if _, ok := responses["default"]; ok {
+		return "default", true
+	}
+	return "", false
+}
+
+// mediaTypeKey picks the documented media type for a content type: the exact
+// type, then a wildcard such as "application/*", then "*/*"
+func mediaTypeKey(content map[string]any, mediaType string) (string, bool) {
+	for key := range content {
+		if strings.EqualFold(key, mediaType) {
+			return key, true
+		}
+	}
+	if major, _, ok := strings.Cut(mediaType, "/"); ok {
+		if _, ok := content[major+"/*"]; ok {
+			return major + "/*", true
+		}
+	}
+	if _, ok := content["*/*"]; ok {
+		return "*/*", true
+	}
+	return "", false
+}
+
+// isJSON reports whether a media type carries JSON
+func isJSON(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// schemaType returns a schema's type, ignoring "null" in 3.1 type lists
+func schemaType(schema map[string]any) string {
+	switch typ := schema["type"].(type) {
+	case string:
+		return typ
+	case []any:
+		for _, t := range typ {
+			if s, ok := t.(string); ok && s != "null" {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+// sortedKeys returns a map's keys in order
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// truncate limits a body's length for display
+func truncate(s string) string {
+	if len(s) <= 100 {
+		return s
+	}
+	return s[:100] + "..."
+}
@@ -0,0 +1,80 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+
+	"curlex/internal/models"
+)
+
+// CoverageFormatter formats the OpenAPI coverage report
+type CoverageFormatter struct {
+	NoColor bool
+}
+
+// NewCoverageFormatter creates a new coverage report formatter
+func NewCoverageFormatter(noColor bool) *CoverageFormatter {
+	return &CoverageFormatter{
+		NoColor: noColor,
+	}
+}
+
+// Format lists every documented operation with its request count, followed
+// by the share of operations the suite exercised
+func (f *CoverageFormatter) Format(coverage *models.OpenAPICoverage) string {
+	var sb strings.Builder
+
+	sb.WriteString("\n")
+	sb.WriteString(f.colorize(ColorBold, "OpenAPI coverage: "+coverage.Spec))
+	sb.WriteString("\n")
+
+	// Align operations in a column
+	width := 0
+	for _, op := range coverage.Operations {
+		width = max(width, len(op.Method)+1+len(op.Path))
+	}
+
+	for _, op := range coverage.Operations {
+		label := fmt.Sprintf("%-*s", width, op.Method+" "+op.Path)
+		icon, count := f.colorize(ColorGreen, "✓"), fmt.Sprintf("%-13s", pluralize(op.Requests, "request"))
+		if op.Requests == 0 {
+			icon, count = f.colorize(ColorRed, "✗"), f.colorize(ColorRed, "not exercised")
+		}
+		line := fmt.Sprintf("  %s %s  %s", icon, label, count)
+		if op.OperationID != "" {
+			line += "  " + f.colorize(ColorGray, op.OperationID)
+		}
+		sb.WriteString(strings.TrimRight(line, " ") + "\n")
+	}
+
+	total := len(coverage.Operations)
+	covered := coverage.Covered()
+	percent := 100.0
+	if total > 0 {
+		percent = float64(covered) * 100 / float64(total)
+	}
+	summaryColor := ColorGreen
+	if covered < total {
+		summaryColor = ColorYellow
+	}
+	sb.WriteString(f.colorize(summaryColor, fmt.Sprintf("%d/%d operations covered (%.0f%%)", covered, total, percent)))
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
+// pluralize formats a count with a noun, e.g. "1 request" or "3 requests"
+func pluralize(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// colorize applies color codes if colors are enabled
+func (f *CoverageFormatter) colorize(color, text string) string {
+	if f.NoColor {
+		return text
+	}
+	return color + text + ColorReset
+}
@@ -0,0 +1,33 @@
+package output
+
+import (
+	"strings"
+	"testing"
+
+	"curlex/internal/models"
+)
+
+func TestCoverageFormatter_Format(t *testing.T) {
+	coverage := &models.OpenAPICoverage{
+		Spec: "api/openapi.yaml",
+		Operations: []models.OperationCoverage{
+			{Method: "GET", Path: "/users", OperationID: "listUsers", Requests: 3},
+			{Method: "POST", Path: "/users", Requests: 1},
+			{Method: "DELETE", Path: "/users/{id}", OperationID: "deleteUser"},
+		},
+	}
+
+	output := NewCoverageFormatter(true).Format(coverage)
+
+	for _, want := range []string{
+		"OpenAPI coverage: api/openapi.yaml",
+		"✓ GET /users          3 requests     listUsers\n",
+		"✓ POST /users         1 request\n",
+		"✗ DELETE /users/{id}  not exercised  deleteUser\n",
+		"2/3 operations covered (67%)",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output, got:\n%s", want, output)
+		}
+	}
+}
@@ -37,15 +37,25 @@ func (f *HumanFormatter) FormatResult(result models.TestResult) string {
 	var sb strings.Builder
 
 	// Test name with status icon
-	if result.Success {
+	switch {
+	case result.Success:
 		sb.WriteString(f.colorize(ColorGreen, "✓"))
-	} else {
+	case result.Skipped:
+		sb.WriteString(f.colorize(ColorYellow, "○"))
+	default:
 		sb.WriteString(f.colorize(ColorRed, "✗"))
 	}
 	sb.WriteString(" ")
 	sb.WriteString(f.colorize(ColorBold, result.Test.Name))
 	sb.WriteString("\n")
 
+	// Show skip reason if the test never ran
+	if result.Skipped {
+		sb.WriteString(f.indent(f.colorize(ColorYellow, "Skipped: "+result.SkipReason), 2))
+		sb.WriteString("\n")
+		return sb.String()
+	}
+
 	// Show error if present
 	if result.Error != nil {
 		sb.WriteString(f.indent(f.colorize(ColorRed, "Error: "+result.Error.Error()), 2))
@@ -113,16 +123,25 @@ func (f *HumanFormatter) FormatResult(result models.TestResult) string {
 	return sb.String()
 }
 
+// FormatPhaseHeader outputs a heading for setup or teardown results
+func (f *HumanFormatter) FormatPhaseHeader(phase string) string {
+	return f.colorize(ColorBlue+ColorBold, phase+":") + "\n"
+}
+
 // FormatSummary outputs the final summary
 func (f *HumanFormatter) FormatSummary(results []models.TestResult, duration time.Duration) string {
 	var sb strings.Builder
 
 	passed := 0
 	failed := 0
+	skipped := 0
 	for _, result := range results {
-		if result.Success {
+		switch {
+		case result.Success:
 			passed++
-		} else {
+		case result.Skipped:
+			skipped++
+		default:
 			failed++
 		}
 	}
@@ -134,8 +153,10 @@ func (f *HumanFormatter) FormatSummary(results []models.TestResult, duration tim
 	sb.WriteString("\n")
 
 	// Summary line
-	if failed == 0 {
+	if failed == 0 && skipped == 0 {
 		sb.WriteString(f.colorize(ColorGreen+ColorBold, fmt.Sprintf("✓ All %d tests passed", total)))
+	} else if failed == 0 {
+		sb.WriteString(f.colorize(ColorYellow+ColorBold, fmt.Sprintf("○ %d of %d tests skipped", skipped, total)))
 	} else {
 		sb.WriteString(f.colorize(ColorRed+ColorBold, fmt.Sprintf("✗ %d of %d tests failed", failed, total)))
 	}
@@ -148,6 +169,9 @@ func (f *HumanFormatter) FormatSummary(results []models.TestResult, duration tim
 	} else {
 		sb.WriteString(fmt.Sprintf("%sFailed:%s %d  ", f.colorize(ColorGray, ""), ColorReset, failed))
 	}
+	if skipped > 0 {
+		sb.WriteString(fmt.Sprintf("%sSkipped:%s %s%d%s  ", f.colorize(ColorGray, ""), ColorReset, ColorYellow, skipped, ColorReset))
+	}
 	sb.WriteString(fmt.Sprintf("%sTotal:%s %d  ", f.colorize(ColorGray, ""), ColorReset, total))
 	sb.WriteString(fmt.Sprintf("%sTime:%s %dms\n", f.colorize(ColorGray, ""), ColorReset, duration.Milliseconds()))
 
@@ -17,26 +17,64 @@ func NewJSONFormatter() *JSONFormatter {
 
 // JSONOutput represents the JSON output structure
 type JSONOutput struct {
-	Version     string           `json:"version"`
-	TotalTests  int              `json:"total_tests"`
-	PassedTests int              `json:"passed_tests"`
-	FailedTests int              `json:"failed_tests"`
-	TotalTime   string           `json:"total_time"`
-	StartTime   string           `json:"start_time"`
-	EndTime     string           `json:"end_time"`
-	Tests       []JSONTestResult `json:"tests"`
+	Version      string           `json:"version"`
+	TotalTests   int              `json:"total_tests"`
+	PassedTests  int              `json:"passed_tests"`
+	FailedTests  int              `json:"failed_tests"`
+	SkippedTests int              `json:"skipped_tests,omitempty"`
+	TotalTime    string           `json:"total_time"`
+	StartTime    string           `json:"start_time"`
+	EndTime      string           `json:"end_time"`
+	Error        string           `json:"error,omitempty"`
+	Setup        []JSONTestResult `json:"setup,omitempty"`
+	Tests        []JSONTestResult `json:"tests"`
+	Teardown     []JSONTestResult `json:"teardown,omitempty"`
+	OpenAPI      *JSONCoverage    `json:"openapi_coverage,omitempty"`
 }
 
 // JSONTestResult represents a single test result in JSON format
 type JSONTestResult struct {
-	Name         string        `json:"name"`
-	Success      bool          `json:"success"`
-	StatusCode   int           `json:"status_code,omitempty"`
-	ResponseTime string        `json:"response_time,omitempty"`
-	Error        string        `json:"error,omitempty"`
-	Failures     []JSONFailure `json:"failures,omitempty"`
-	Request      *JSONRequest  `json:"request,omitempty"`
-	Response     *JSONResponse `json:"response,omitempty"`
+	Name         string            `json:"name"`
+	Success      bool              `json:"success"`
+	Skipped      bool              `json:"skipped,omitempty"`
+	SkipReason   string            `json:"skip_reason,omitempty"`
+	StatusCode   int               `json:"status_code,omitempty"`
+	ResponseTime string            `json:"response_time,omitempty"`
+	Timing       *JSONTiming       `json:"timing,omitempty"`
+	TLS          *JSONTLS          `json:"tls,omitempty"`
+	Error        string            `json:"error,omitempty"`
+	ErrorType    string            `json:"error_type,omitempty"`
+	Failures     []JSONFailure     `json:"failures,omitempty"`
+	Captured     map[string]string `json:"captured,omitempty"`
+	Request      *JSONRequest      `json:"request,omitempty"`
+	Response     *JSONResponse     `json:"response,omitempty"`
+}
+
+// JSONTiming represents a request's timing breakdown in JSON format
+type JSONTiming struct {
+	DNSLookup       string `json:"dns_lookup"`
+	TCPConnect      string `json:"tcp_connect"`
+	TLSHandshake    string `json:"tls_handshake"`
+	TTFB            string `json:"ttfb"`
+	ContentTransfer string `json:"content_transfer"`
+	Total           string `json:"total"`
+}
+
+// JSONTLS represents a response's TLS connection and peer certificate in JSON format
+type JSONTLS struct {
+	Version       string   `json:"version"`
+	CipherSuite   string   `json:"cipher_suite"`
+	Subject       string   `json:"subject,omitempty"`
+	Issuer        string   `json:"issuer,omitempty"`
+	DNSNames      []string `json:"san,omitempty"`
+	NotBefore     string   `json:"not_before,omitempty"`
+	NotAfter      string   `json:"not_after,omitempty"`
+	ExpiresInDays int      `json:"expires_in_days,omitempty"`
+}
+
+// expiresInDays returns the whole days until a certificate expires
+func expiresInDays(info *models.TLSInfo) int {
+	return int(info.ExpiresIn(time.Now()).Hours() / 24)
 }
 
 // JSONRequest represents request details in JSON format
@@ -54,6 +92,22 @@ type JSONResponse struct {
 	Body       string              `json:"body,omitempty"`
 }
 
+// JSONCoverage represents the OpenAPI coverage report in JSON format
+type JSONCoverage struct {
+	Spec       string          `json:"spec"`
+	Covered    int             `json:"covered"`
+	Total      int             `json:"total"`
+	Operations []JSONOperation `json:"operations"`
+}
+
+// JSONOperation represents a documented operation's request count in JSON format
+type JSONOperation struct {
+	Method      string `json:"method"`
+	Path        string `json:"path"`
+	OperationID string `json:"operation_id,omitempty"`
+	Requests    int    `json:"requests"`
+}
+
 // JSONFailure represents an assertion failure in JSON format
 type JSONFailure struct {
 	Type     string `json:"type"`
@@ -65,70 +119,131 @@ type JSONFailure struct {
 // Format converts suite results to JSON
 func (f *JSONFormatter) Format(suiteResult *models.SuiteResult) string {
 	output := JSONOutput{
-		Version:     "1.0.0",
-		TotalTests:  suiteResult.TotalTests,
-		PassedTests: suiteResult.PassedTests,
-		FailedTests: suiteResult.FailedTests,
-		TotalTime:   formatDuration(suiteResult.TotalTime),
-		StartTime:   suiteResult.StartTime.Format(time.RFC3339),
-		EndTime:     suiteResult.EndTime.Format(time.RFC3339),
-		Tests:       make([]JSONTestResult, 0, len(suiteResult.Results)),
+		Version:      "1.0.0",
+		TotalTests:   suiteResult.TotalTests,
+		PassedTests:  suiteResult.PassedTests,
+		FailedTests:  suiteResult.FailedTests,
+		SkippedTests: suiteResult.SkippedTests,
+		TotalTime:    formatDuration(suiteResult.TotalTime),
+		StartTime:    suiteResult.StartTime.Format(time.RFC3339),
+		EndTime:      suiteResult.EndTime.Format(time.RFC3339),
+		Tests:        make([]JSONTestResult, 0, len(suiteResult.Results)),
 	}
 
+	if suiteResult.Error != nil {
+		output.Error = suiteResult.Error.Error()
+	}
+
+	for _, result := range suiteResult.SetupResults {
+		output.Setup = append(output.Setup, f.formatTestResult(result))
+	}
 	for _, result := range suiteResult.Results {
-		testResult := JSONTestResult{
-			Name:         result.Test.Name,
-			Success:      result.Success,
-			StatusCode:   result.StatusCode,
-			ResponseTime: formatDuration(result.ResponseTime),
+		output.Tests = append(output.Tests, f.formatTestResult(result))
+	}
+	for _, result := range suiteResult.TeardownResults {
+		output.Teardown = append(output.Teardown, f.formatTestResult(result))
+	}
+
+	if coverage := suiteResult.OpenAPICoverage; coverage != nil {
+		output.OpenAPI = &JSONCoverage{
+			Spec:       coverage.Spec,
+			Covered:    coverage.Covered(),
+			Total:      len(coverage.Operations),
+			Operations: make([]JSONOperation, 0, len(coverage.Operations)),
 		}
+		for _, op := range coverage.Operations {
+			output.OpenAPI.Operations = append(output.OpenAPI.Operations, JSONOperation(op))
+		}
+	}
 
-		if result.Error != nil {
-			testResult.Error = result.Error.Error()
+	// Marshal to JSON with indentation
+	data, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return `{"error": "failed to marshal JSON"}`
+	}
+
+	return string(data) + "\n"
+}
+
+// formatTestResult converts a single test result to its JSON representation
+func (f *JSONFormatter) formatTestResult(result models.TestResult) JSONTestResult {
+	testResult := JSONTestResult{
+		Name:         result.Test.Name,
+		Success:      result.Success,
+		Skipped:      result.Skipped,
+		SkipReason:   result.SkipReason,
+		StatusCode:   result.StatusCode,
+		ResponseTime: formatDuration(result.ResponseTime),
+		Captured:     result.Captured,
+	}
+
+	if result.Timing != nil {
+		testResult.Timing = &JSONTiming{
+			DNSLookup:       formatDuration(result.Timing.DNSLookup),
+			TCPConnect:      formatDuration(result.Timing.TCPConnect),
+			TLSHandshake:    formatDuration(result.Timing.TLSHandshake),
+			TTFB:            formatDuration(result.Timing.TTFB),
+			ContentTransfer: formatDuration(result.Timing.ContentTransfer),
+			Total:           formatDuration(result.Timing.Total),
 		}
+	}
 
-		// Add failures
-		if len(result.Failures) > 0 {
-			testResult.Failures = make([]JSONFailure, 0, len(result.Failures))
-			for _, failure := range result.Failures {
-				testResult.Failures = append(testResult.Failures, JSONFailure{
-					Type:     string(failure.Type),
-					Expected: failure.Expected,
-					Actual:   failure.Actual,
-					Message:  failure.Message,
-				})
-			}
+	if result.TLS != nil {
+		testResult.TLS = &JSONTLS{
+			Version:     result.TLS.Version,
+			CipherSuite: result.TLS.CipherSuite,
+			Subject:     result.TLS.Subject,
+			Issuer:      result.TLS.Issuer,
+			DNSNames:    result.TLS.DNSNames,
+		}
+		if !result.TLS.NotAfter.IsZero() {
+			testResult.TLS.NotBefore = result.TLS.NotBefore.UTC().Format(time.RFC3339)
+			testResult.TLS.NotAfter = result.TLS.NotAfter.UTC().Format(time.RFC3339)
+			testResult.TLS.ExpiresInDays = expiresInDays(result.TLS)
 		}
+	}
 
-		// Add request details
-		if result.PreparedRequest != nil {
-			testResult.Request = &JSONRequest{
-				Method:  result.PreparedRequest.Method,
-				URL:     result.PreparedRequest.URL,
-				Headers: result.PreparedRequest.Headers,
-				Body:    result.PreparedRequest.Body,
-			}
+	if result.Error != nil {
+		testResult.Error = result.Error.Error()
+		testResult.ErrorType = "execution"
+		if result.IsTimeout() {
+			testResult.ErrorType = "timeout"
 		}
+	}
 
-		// Add response details
-		if result.StatusCode > 0 {
-			testResult.Response = &JSONResponse{
-				StatusCode: result.StatusCode,
-				Headers:    result.Headers,
-				Body:       result.ResponseBody,
-			}
+	// Add failures
+	if len(result.Failures) > 0 {
+		testResult.Failures = make([]JSONFailure, 0, len(result.Failures))
+		for _, failure := range result.Failures {
+			testResult.Failures = append(testResult.Failures, JSONFailure{
+				Type:     string(failure.Type),
+				Expected: failure.Expected,
+				Actual:   failure.Actual,
+				Message:  failure.Message,
+			})
 		}
+	}
 
-		output.Tests = append(output.Tests, testResult)
+	// Add request details
+	if result.PreparedRequest != nil {
+		testResult.Request = &JSONRequest{
+			Method:  result.PreparedRequest.Method,
+			URL:     result.PreparedRequest.URL,
+			Headers: result.PreparedRequest.Headers,
+			Body:    bodyText(result.PreparedRequest.Body),
+		}
 	}
 
-	// Marshal to JSON with indentation
-	data, err := json.MarshalIndent(output, "", "  ")
-	if err != nil {
-		return `{"error": "failed to marshal JSON"}`
+	// Add response details
+	if result.StatusCode > 0 {
+		testResult.Response = &JSONResponse{
+			StatusCode: result.StatusCode,
+			Headers:    result.Headers,
+			Body:       result.ResponseBody,
+		}
 	}
 
-	return string(data) + "\n"
+	return testResult
 }
 
 // formatDuration converts a duration to a human-readable string
@@ -2,6 +2,7 @@ package output
 
 import (
 	"encoding/json"
+	"errors"
 	"testing"
 	"time"
 
@@ -118,3 +119,149 @@ func TestJSONFormatter_ValidJSON(t *testing.T) {
 		t.Fatalf("Output is not valid JSON: %v", err)
 	}
 }
+
+func TestJSONFormatter_SetupTeardown(t *testing.T) {
+	formatter := NewJSONFormatter()
+
+	suiteResult := &models.SuiteResult{
+		TotalTests:   1,
+		SkippedTests: 1,
+		Error:        errors.New(`setup failed: test "Create fixture" failed`),
+		SetupResults: []models.TestResult{
+			{Test: models.Test{Name: "Create fixture"}, StatusCode: 500},
+		},
+		Results: []models.TestResult{
+			{Test: models.Test{Name: "Main test"}, Skipped: true, SkipReason: "suite setup failed"},
+		},
+		TeardownResults: []models.TestResult{
+			{Test: models.Test{Name: "Delete fixture"}, Success: true, StatusCode: 200},
+		},
+	}
+
+	var output JSONOutput
+	if err := json.Unmarshal([]byte(formatter.Format(suiteResult)), &output); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+
+	if output.Error == "" {
+		t.Error("Expected suite error in output")
+	}
+	if len(output.Setup) != 1 || output.Setup[0].Name != "Create fixture" {
+		t.Errorf("Setup = %+v", output.Setup)
+	}
+	if len(output.Teardown) != 1 || output.Teardown[0].Name != "Delete fixture" {
+		t.Errorf("Teardown = %+v", output.Teardown)
+	}
+	if len(output.Tests) != 1 || !output.Tests[0].Skipped || output.SkippedTests != 1 {
+		t.Errorf("Main test should be reported as skipped, got %+v", output.Tests)
+	}
+}
+
+func TestJSONFormatter_Timing(t *testing.T) {
+	formatter := NewJSONFormatter()
+
+	suiteResult := &models.SuiteResult{
+		TotalTests:  2,
+		PassedTests: 2,
+		Results: []models.TestResult{
+			{
+				Test:         models.Test{Name: "Timed"},
+				Success:      true,
+				StatusCode:   200,
+				ResponseTime: 180 * time.Millisecond,
+				Timing: &models.Timing{
+					DNSLookup:       2 * time.Millisecond,
+					TCPConnect:      8 * time.Millisecond,
+					TLSHandshake:    30 * time.Millisecond,
+					TTFB:            120 * time.Millisecond,
+					ContentTransfer: 60 * time.Millisecond,
+					Total:           180 * time.Millisecond,
+				},
+			},
+			{Test: models.Test{Name: "Untimed"}, Success: true},
+		},
+	}
+
+	var output JSONOutput
+	if err := json.Unmarshal([]byte(formatter.Format(suiteResult)), &output); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+
+	timing := output.Tests[0].Timing
+	if timing == nil {
+		t.Fatal("Expected timing in output")
+	}
+	if timing.TTFB != "120ms" || timing.TLSHandshake != "30ms" || timing.Total != "180ms" {
+		t.Errorf("Timing = %+v", timing)
+	}
+	if output.Tests[1].Timing != nil {
+		t.Errorf("Expected no timing for untimed result, got %+v", output.Tests[1].Timing)
+	}
+}
+
+func TestJSONFormatter_TLS(t *testing.T) {
+	formatter := NewJSONFormatter()
+
+	notAfter := time.Now().Add(20*24*time.Hour + time.Hour)
+	suiteResult := &models.SuiteResult{
+		TotalTests:  1,
+		PassedTests: 1,
+		Results: []models.TestResult{
+			{
+				Test:       models.Test{Name: "Secure"},
+				Success:    true,
+				StatusCode: 200,
+				TLS: &models.TLSInfo{
+					Version:     "1.3",
+					CipherSuite: "TLS_AES_128_GCM_SHA256",
+					Subject:     "api.example.com",
+					Issuer:      "Example CA",
+					DNSNames:    []string{"api.example.com"},
+					NotBefore:   notAfter.Add(-90 * 24 * time.Hour),
+					NotAfter:    notAfter,
+				},
+			},
+		},
+	}
+
+	var output JSONOutput
+	if err := json.Unmarshal([]byte(formatter.Format(suiteResult)), &output); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+
+	tls := output.Tests[0].TLS
+	if tls == nil {
+		t.Fatal("Expected TLS details in output")
+	}
+	if tls.Version != "1.3" || tls.Issuer != "Example CA" || tls.ExpiresInDays != 20 {
+		t.Errorf("TLS = %+v", tls)
+	}
+	if tls.NotAfter != notAfter.UTC().Format(time.RFC3339) {
+		t.Errorf("NotAfter = %q", tls.NotAfter)
+	}
+}
+
+func TestJSONFormatter_OpenAPICoverage(t *testing.T) {
+	suiteResult := &models.SuiteResult{
+		OpenAPICoverage: &models.OpenAPICoverage{
+			Spec: "openapi.yaml",
+			Operations: []models.OperationCoverage{
+				{Method: "GET", Path: "/users", OperationID: "listUsers", Requests: 2},
+				{Method: "DELETE", Path: "/users/{id}"},
+			},
+		},
+	}
+
+	var output JSONOutput
+	if err := json.Unmarshal([]byte(NewJSONFormatter().Format(suiteResult)), &output); err != nil {
+		t.Fatalf("Failed to parse JSON output: %v", err)
+	}
+
+	coverage := output.OpenAPI
+	if coverage == nil || coverage.Covered != 1 || coverage.Total != 2 || len(coverage.Operations) != 2 {
+		t.Fatalf("openapi_coverage = %+v", coverage)
+	}
+	if op := coverage.Operations[0]; op.OperationID != "listUsers" || op.Requests != 2 {
+		t.Errorf("operation = %+v", op)
+	}
+}
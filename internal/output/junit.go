@@ -28,6 +28,7 @@ type JUnitTestSuite struct {
 	Tests    int             `xml:"tests,attr"`
 	Failures int             `xml:"failures,attr"`
 	Errors   int             `xml:"errors,attr"`
+	Skipped  int             `xml:"skipped,attr"`
 	Time     float64         `xml:"time,attr"`
 	Cases    []JUnitTestCase `xml:"testcase"`
 }
@@ -39,6 +40,7 @@ type JUnitTestCase struct {
 	Time      float64       `xml:"time,attr"`
 	Failure   *JUnitFailure `xml:"failure,omitempty"`
 	Error     *JUnitError   `xml:"error,omitempty"`
+	Skipped   *JUnitSkipped `xml:"skipped,omitempty"`
 	SystemOut string        `xml:"system-out,omitempty"`
 }
 
@@ -49,6 +51,11 @@ type JUnitFailure struct {
 	Content string `xml:",chardata"`
 }
 
+// JUnitSkipped represents a test that was not run
+type JUnitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
 // JUnitError represents a test error
 type JUnitError struct {
 	Message string `xml:"message,attr"`
@@ -63,59 +70,31 @@ func (f *JUnitFormatter) Format(suiteResult *models.SuiteResult) string {
 		Tests:    suiteResult.TotalTests,
 		Failures: suiteResult.FailedTests,
 		Errors:   0,
+		Skipped:  suiteResult.SkippedTests,
 		Time:     suiteResult.TotalTime.Seconds(),
 		Cases:    make([]JUnitTestCase, 0, len(suiteResult.Results)),
 	}
 
 	for _, result := range suiteResult.Results {
-		testCase := JUnitTestCase{
-			Name:      result.Test.Name,
-			Classname: "curlex.tests",
-			Time:      result.ResponseTime.Seconds(),
-		}
-
-		// Add system output (request/response details)
-		var sysOut strings.Builder
-		if result.PreparedRequest != nil {
-			sysOut.WriteString(fmt.Sprintf("Request: %s %s\n", result.PreparedRequest.Method, result.PreparedRequest.URL))
-		}
-		sysOut.WriteString(fmt.Sprintf("Status: %d\n", result.StatusCode))
-		sysOut.WriteString(fmt.Sprintf("Response Time: %dms\n", result.ResponseTime.Milliseconds()))
-		testCase.SystemOut = sysOut.String()
-
-		// Add failure if test failed
-		if !result.Success {
-			if result.Error != nil {
-				// Error during execution
-				testCase.Error = &JUnitError{
-					Message: "Test execution error",
-					Type:    "ExecutionError",
-					Content: result.Error.Error(),
-				}
-				suite.Errors++
-			} else if len(result.Failures) > 0 {
-				// Assertion failures
-				var failureMsg strings.Builder
-				for i, failure := range result.Failures {
-					if i > 0 {
-						failureMsg.WriteString("\n")
-					}
-					failureMsg.WriteString(failure.String())
-				}
-
-				testCase.Failure = &JUnitFailure{
-					Message: fmt.Sprintf("%d assertion(s) failed", len(result.Failures)),
-					Type:    "AssertionFailure",
-					Content: failureMsg.String(),
-				}
-			}
+		testCase := f.formatTestCase(result, "curlex.tests")
+		if testCase.Error != nil {
+			suite.Errors++
 		}
-
 		suite.Cases = append(suite.Cases, testCase)
 	}
 
+	// Setup and teardown are reported as their own suites around the main one
+	var suites []JUnitTestSuite
+	if len(suiteResult.SetupResults) > 0 {
+		suites = append(suites, f.formatPhase("curlex.setup", suiteResult.SetupResults))
+	}
+	suites = append(suites, suite)
+	if len(suiteResult.TeardownResults) > 0 {
+		suites = append(suites, f.formatPhase("curlex.teardown", suiteResult.TeardownResults))
+	}
+
 	testSuites := JUnitTestSuites{
-		Suites: []JUnitTestSuite{suite},
+		Suites: suites,
 	}
 
 	// Marshal to XML
@@ -126,3 +105,89 @@ func (f *JUnitFormatter) Format(suiteResult *models.SuiteResult) string {
 
 	return xml.Header + string(output) + "\n"
 }
+
+// formatPhase builds a test suite element for setup or teardown results
+func (f *JUnitFormatter) formatPhase(name string, results []models.TestResult) JUnitTestSuite {
+	suite := JUnitTestSuite{
+		Name:  name,
+		Tests: len(results),
+		Cases: make([]JUnitTestCase, 0, len(results)),
+	}
+
+	for _, result := range results {
+		testCase := f.formatTestCase(result, name)
+		switch {
+		case result.Skipped:
+			suite.Skipped++
+		case testCase.Error != nil:
+			suite.Errors++
+			suite.Failures++
+		case !result.Success:
+			suite.Failures++
+		}
+		suite.Time += result.ResponseTime.Seconds()
+		suite.Cases = append(suite.Cases, testCase)
+	}
+
+	return suite
+}
+
+// formatTestCase converts a single test result to a JUnit test case
+func (f *JUnitFormatter) formatTestCase(result models.TestResult, classname string) JUnitTestCase {
+	testCase := JUnitTestCase{
+		Name:      result.Test.Name,
+		Classname: classname,
+		Time:      result.ResponseTime.Seconds(),
+	}
+
+	// Skipped tests have no request/response details
+	if result.Skipped {
+		testCase.Skipped = &JUnitSkipped{Message: result.SkipReason}
+		return testCase
+	}
+
+	// Add system output (request/response details)
+	var sysOut strings.Builder
+	if result.PreparedRequest != nil {
+		sysOut.WriteString(fmt.Sprintf("Request: %s %s\n", result.PreparedRequest.Method, result.PreparedRequest.URL))
+	}
+	sysOut.WriteString(fmt.Sprintf("Status: %d\n", result.StatusCode))
+	sysOut.WriteString(fmt.Sprintf("Response Time: %dms\n", result.ResponseTime.Milliseconds()))
+	testCase.SystemOut = sysOut.String()
+
+	// Add failure if test failed
+	if !result.Success {
+		if result.IsTimeout() {
+			// Request exceeded its timeout
+			testCase.Error = &JUnitError{
+				Message: "Request timed out",
+				Type:    "TimeoutError",
+				Content: result.Error.Error(),
+			}
+		} else if result.Error != nil {
+			// Error during execution
+			testCase.Error = &JUnitError{
+				Message: "Test execution error",
+				Type:    "ExecutionError",
+				Content: result.Error.Error(),
+			}
+		} else if len(result.Failures) > 0 {
+			// Assertion failures
+			var failureMsg strings.Builder
+			for i, failure := range result.Failures {
+				if i > 0 {
+					failureMsg.WriteString("\n")
+				}
+				failureMsg.WriteString(failure.String())
+			}
+
+			testCase.Failure = &JUnitFailure{
+				Message: fmt.Sprintf("%d assertion(s) failed", len(result.Failures)),
+				Type:    "AssertionFailure",
+				Content: failureMsg.String(),
+			}
+		}
+	}
+
+	return testCase
+}
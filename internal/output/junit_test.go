@@ -117,3 +117,92 @@ func TestJUnitFormatter_ValidXML(t *testing.T) {
 		t.Fatalf("Output is not valid XML: %v", err)
 	}
 }
+
+func TestJUnitFormatter_Skipped(t *testing.T) {
+	formatter := NewJUnitFormatter()
+
+	suiteResult := &models.SuiteResult{
+		TotalTests:   1,
+		SkippedTests: 1,
+		Results: []models.TestResult{
+			{
+				Test:       models.Test{Name: "Dependent test"},
+				Skipped:    true,
+				SkipReason: `dependency "Login" failed`,
+			},
+		},
+	}
+
+	output := formatter.Format(suiteResult)
+
+	if !strings.Contains(output, `skipped="1"`) {
+		t.Error("Output should contain skipped count")
+	}
+	if !strings.Contains(output, `<skipped message="dependency &#34;Login&#34; failed">`) {
+		t.Errorf("Output should contain skipped element, got: %s", output)
+	}
+	if strings.Contains(output, "<error") || strings.Contains(output, "<failure") {
+		t.Error("Skipped test should not be reported as error or failure")
+	}
+}
+
+func TestJUnitFormatter_SetupTeardownSuites(t *testing.T) {
+	formatter := NewJUnitFormatter()
+
+	suiteResult := &models.SuiteResult{
+		TotalTests:   1,
+		SkippedTests: 1,
+		SetupResults: []models.TestResult{
+			{Test: models.Test{Name: "Create fixture"}, Success: false, StatusCode: 500},
+		},
+		Results: []models.TestResult{
+			{Test: models.Test{Name: "Main test"}, Skipped: true, SkipReason: "suite setup failed"},
+		},
+		TeardownResults: []models.TestResult{
+			{Test: models.Test{Name: "Delete fixture"}, Success: true, StatusCode: 200},
+		},
+	}
+
+	var parsed JUnitTestSuites
+	if err := xml.Unmarshal([]byte(formatter.Format(suiteResult)), &parsed); err != nil {
+		t.Fatalf("Invalid XML: %v", err)
+	}
+
+	if len(parsed.Suites) != 3 {
+		t.Fatalf("Expected 3 test suites, got %d", len(parsed.Suites))
+	}
+	names := []string{parsed.Suites[0].Name, parsed.Suites[1].Name, parsed.Suites[2].Name}
+	if names[0] != "curlex.setup" || names[1] != "curlex" || names[2] != "curlex.teardown" {
+		t.Errorf("Suite names = %v", names)
+	}
+	if parsed.Suites[0].Failures != 1 {
+		t.Errorf("Setup suite failures = %d, want 1", parsed.Suites[0].Failures)
+	}
+	if parsed.Suites[0].Cases[0].Classname != "curlex.setup" {
+		t.Errorf("Setup classname = %s", parsed.Suites[0].Cases[0].Classname)
+	}
+}
+
+func TestJUnitFormatter_Timeout(t *testing.T) {
+	formatter := NewJUnitFormatter()
+
+	suiteResult := &models.SuiteResult{
+		TotalTests:  1,
+		FailedTests: 1,
+		Results: []models.TestResult{
+			{
+				Test:  models.Test{Name: "Slow test"},
+				Error: &models.TimeoutError{Timeout: 5 * time.Second},
+			},
+		},
+	}
+
+	output := formatter.Format(suiteResult)
+
+	if !strings.Contains(output, `type="TimeoutError"`) {
+		t.Errorf("Timeout should be reported as TimeoutError, got: %s", output)
+	}
+	if !strings.Contains(output, "request timed out after 5s") {
+		t.Errorf("Output should contain timeout message, got: %s", output)
+	}
+}
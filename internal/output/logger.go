@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"curlex/internal/models"
 )
@@ -57,9 +58,9 @@ func (l *RequestLogger) LogTest(result models.TestResult, preparedReq *models.Pr
 				content.WriteString(fmt.Sprintf("  %s: %s\n", key, displayValue))
 			}
 		}
-		if preparedReq.Body != "" {
+		if len(preparedReq.Body) > 0 {
 			content.WriteString("\nBody:\n")
-			content.WriteString(formatBody(preparedReq.Body))
+			content.WriteString(formatBody(bodyText(preparedReq.Body)))
 			content.WriteString("\n")
 		}
 	}
@@ -68,6 +69,13 @@ func (l *RequestLogger) LogTest(result models.TestResult, preparedReq *models.Pr
 	content.WriteString("\n=== RESPONSE ===\n")
 	content.WriteString(fmt.Sprintf("Status: %d (%dms)\n", result.StatusCode, result.ResponseTime.Milliseconds()))
 
+	if result.Timing != nil {
+		content.WriteString("\nTiming:\n")
+		for _, phase := range timingPhases(result.Timing) {
+			content.WriteString(fmt.Sprintf("  %-17s %s\n", phase.Label+":", formatDuration(phase.Duration)))
+		}
+	}
+
 	if len(result.Headers) > 0 {
 		content.WriteString("\nHeaders:\n")
 		for key, values := range result.Headers {
@@ -136,6 +144,14 @@ func isSensitiveHeader(key string) bool {
 	return false
 }
 
+// bodyText returns a request body for display, summarizing binary data
+func bodyText(body []byte) string {
+	if !utf8.Valid(body) {
+		return fmt.Sprintf("[binary data, %d bytes]", len(body))
+	}
+	return string(body)
+}
+
 // formatBody attempts to format JSON bodies with indentation
 func formatBody(body string) string {
 	// For now, just return the body as-is
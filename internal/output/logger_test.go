@@ -30,7 +30,7 @@ func TestRequestLogger_LogTest(t *testing.T) {
 		Method:  "GET",
 		URL:     "https://api.example.com/users",
 		Headers: map[string]string{"Accept": "application/json"},
-		Body:    "",
+		Body:    nil,
 	}
 
 	err := logger.LogTest(result, preparedReq)
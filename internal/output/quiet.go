@@ -23,10 +23,14 @@ func NewQuietFormatter(noColor bool) *QuietFormatter {
 func (f *QuietFormatter) FormatSummary(results []models.TestResult, duration time.Duration) string {
 	passed := 0
 	failed := 0
+	skipped := 0
 	for _, result := range results {
-		if result.Success {
+		switch {
+		case result.Success:
 			passed++
-		} else {
+		case result.Skipped:
+			skipped++
+		default:
 			failed++
 		}
 	}
@@ -34,9 +38,15 @@ func (f *QuietFormatter) FormatSummary(results []models.TestResult, duration tim
 	total := len(results)
 
 	// Simple one-line output
+	if failed == 0 && skipped > 0 {
+		return f.colorize(ColorYellow, fmt.Sprintf("○ %d/%d passed, %d skipped (%dms)\n", passed, total, skipped, duration.Milliseconds()))
+	}
 	if failed == 0 {
 		return f.colorize(ColorGreen, fmt.Sprintf("✓ %d/%d passed (%dms)\n", passed, total, duration.Milliseconds()))
 	}
+	if skipped > 0 {
+		return f.colorize(ColorRed, fmt.Sprintf("✗ %d/%d failed, %d passed, %d skipped (%dms)\n", failed, total, passed, skipped, duration.Milliseconds()))
+	}
 	return f.colorize(ColorRed, fmt.Sprintf("✗ %d/%d failed, %d passed (%dms)\n", failed, total, passed, duration.Milliseconds()))
 }
 
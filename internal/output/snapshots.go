@@ -0,0 +1,56 @@
+package output
+
+import (
+	"strings"
+
+	"curlex/internal/models"
+)
+
+// SnapshotFormatter formats the list of written and obsolete snapshots
+type SnapshotFormatter struct {
+	NoColor bool
+}
+
+// NewSnapshotFormatter creates a new snapshot report formatter
+func NewSnapshotFormatter(noColor bool) *SnapshotFormatter {
+	return &SnapshotFormatter{
+		NoColor: noColor,
+	}
+}
+
+// Format lists the snapshot files the run wrote, then those no test uses
+func (f *SnapshotFormatter) Format(report *models.SnapshotReport) string {
+	var sb strings.Builder
+
+	if len(report.Written) > 0 {
+		sb.WriteString("\n")
+		sb.WriteString(f.colorize(ColorBold, pluralize(len(report.Written), "snapshot")+" written"))
+		sb.WriteString("\n")
+		for _, path := range report.Written {
+			sb.WriteString("  " + f.colorize(ColorGreen, "+") + " " + path + "\n")
+		}
+	}
+
+	if len(report.Obsolete) > 0 {
+		heading, color := pluralize(len(report.Obsolete), "obsolete snapshot")+" (run with --update-snapshots to remove)", ColorYellow
+		if report.Removed {
+			heading, color = pluralize(len(report.Obsolete), "obsolete snapshot")+" removed", ColorGray
+		}
+		sb.WriteString("\n")
+		sb.WriteString(f.colorize(ColorBold, heading))
+		sb.WriteString("\n")
+		for _, path := range report.Obsolete {
+			sb.WriteString("  " + f.colorize(color, "-") + " " + path + "\n")
+		}
+	}
+
+	return sb.String()
+}
+
+// colorize applies color codes if colors are enabled
+func (f *SnapshotFormatter) colorize(color, text string) string {
+	if f.NoColor {
+		return text
+	}
+	return color + text + ColorReset
+}
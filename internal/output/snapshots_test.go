@@ -0,0 +1,43 @@
+package output
+
+import (
+	"strings"
+	"testing"
+
+	"curlex/internal/models"
+)
+
+func TestSnapshotFormatter_Format(t *testing.T) {
+	tests := []struct {
+		name   string
+		report *models.SnapshotReport
+		want   []string
+	}{
+		{
+			name:   "written",
+			report: &models.SnapshotReport{Written: []string{"__snapshots__/users/get-user.snap"}},
+			want:   []string{"1 snapshot written\n", "  + __snapshots__/users/get-user.snap\n"},
+		},
+		{
+			name:   "obsolete",
+			report: &models.SnapshotReport{Obsolete: []string{"__snapshots__/users/a.snap", "__snapshots__/users/b.snap"}},
+			want:   []string{"2 obsolete snapshots (run with --update-snapshots to remove)\n", "  - __snapshots__/users/b.snap\n"},
+		},
+		{
+			name:   "removed",
+			report: &models.SnapshotReport{Obsolete: []string{"__snapshots__/users/a.snap"}, Removed: true},
+			want:   []string{"1 obsolete snapshot removed\n"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := NewSnapshotFormatter(true).Format(tt.report)
+			for _, want := range tt.want {
+				if !strings.Contains(output, want) {
+					t.Errorf("Expected %q in output, got:\n%s", want, output)
+				}
+			}
+		})
+	}
+}
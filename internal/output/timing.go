@@ -0,0 +1,25 @@
+package output
+
+import (
+	"time"
+
+	"curlex/internal/models"
+)
+
+// timingPhase is a labelled entry of a request's timing breakdown
+type timingPhase struct {
+	Label    string
+	Duration time.Duration
+}
+
+// timingPhases lists the timing breakdown in the order the phases occur
+func timingPhases(timing *models.Timing) []timingPhase {
+	return []timingPhase{
+		{"DNS Lookup", timing.DNSLookup},
+		{"TCP Connect", timing.TCPConnect},
+		{"TLS Handshake", timing.TLSHandshake},
+		{"TTFB", timing.TTFB},
+		{"Content Transfer", timing.ContentTransfer},
+		{"Total", timing.Total},
+	}
+}
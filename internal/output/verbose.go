@@ -2,8 +2,11 @@ package output
 
 import (
 	"fmt"
+	"maps"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"curlex/internal/models"
 )
@@ -27,15 +30,25 @@ func (f *VerboseFormatter) FormatResult(result models.TestResult) string {
 	// Test name with separator
 	sb.WriteString(strings.Repeat("=", 60))
 	sb.WriteString("\n")
-	if result.Success {
+	switch {
+	case result.Success:
 		sb.WriteString(f.colorize(ColorGreen+ColorBold, "✓ "+result.Test.Name))
-	} else {
+	case result.Skipped:
+		sb.WriteString(f.colorize(ColorYellow+ColorBold, "○ "+result.Test.Name))
+	default:
 		sb.WriteString(f.colorize(ColorRed+ColorBold, "✗ "+result.Test.Name))
 	}
 	sb.WriteString("\n")
 	sb.WriteString(strings.Repeat("=", 60))
 	sb.WriteString("\n\n")
 
+	// Skipped tests never ran, so there is no request or response to show
+	if result.Skipped {
+		sb.WriteString(f.colorize(ColorYellow, "  Skipped: "+result.SkipReason))
+		sb.WriteString("\n\n")
+		return sb.String()
+	}
+
 	// Request details
 	if result.PreparedRequest != nil {
 		sb.WriteString(f.colorize(ColorBlue+ColorBold, "REQUEST:"))
@@ -55,11 +68,11 @@ func (f *VerboseFormatter) FormatResult(result models.TestResult) string {
 			}
 		}
 
-		if result.PreparedRequest.Body != "" {
+		if len(result.PreparedRequest.Body) > 0 {
 			sb.WriteString(f.colorize(ColorBlue, "  Body:"))
 			sb.WriteString("\n")
 			// Show first 200 characters
-			body := result.PreparedRequest.Body
+			body := bodyText(result.PreparedRequest.Body)
 			if len(body) > 200 {
 				body = body[:200] + "..."
 			}
@@ -83,6 +96,31 @@ func (f *VerboseFormatter) FormatResult(result models.TestResult) string {
 		f.colorize(statusColor, strconv.Itoa(result.StatusCode)),
 		result.ResponseTime.Milliseconds()))
 
+	// Timing breakdown
+	if result.Timing != nil {
+		sb.WriteString(f.colorize(ColorBlue, "  Timing:"))
+		sb.WriteString("\n")
+		for _, phase := range timingPhases(result.Timing) {
+			sb.WriteString(fmt.Sprintf("    %-17s %s\n", phase.Label+":", formatDuration(phase.Duration)))
+		}
+	}
+
+	// TLS connection and peer certificate
+	if result.TLS != nil {
+		sb.WriteString(f.colorize(ColorBlue, "  TLS:"))
+		sb.WriteString("\n")
+		sb.WriteString(fmt.Sprintf("    %-17s %s (%s)\n", "Version:", result.TLS.Version, result.TLS.CipherSuite))
+		if !result.TLS.NotAfter.IsZero() {
+			sb.WriteString(fmt.Sprintf("    %-17s %s\n", "Subject:", result.TLS.Subject))
+			sb.WriteString(fmt.Sprintf("    %-17s %s\n", "Issuer:", result.TLS.Issuer))
+			if len(result.TLS.DNSNames) > 0 {
+				sb.WriteString(fmt.Sprintf("    %-17s %s\n", "SAN:", strings.Join(result.TLS.DNSNames, ", ")))
+			}
+			sb.WriteString(fmt.Sprintf("    %-17s %s (%d days)\n", "Expires:",
+				result.TLS.NotAfter.UTC().Format(time.RFC3339), expiresInDays(result.TLS)))
+		}
+	}
+
 	// Headers
 	if len(result.Headers) > 0 {
 		sb.WriteString(f.colorize(ColorBlue, "  Headers:"))
@@ -121,6 +159,16 @@ func (f *VerboseFormatter) FormatResult(result models.TestResult) string {
 		}
 	}
 
+	// Captured variables
+	if len(result.Captured) > 0 {
+		sb.WriteString("\n")
+		sb.WriteString(f.colorize(ColorBlue+ColorBold, "CAPTURED:"))
+		sb.WriteString("\n")
+		for _, name := range slices.Sorted(maps.Keys(result.Captured)) {
+			sb.WriteString(fmt.Sprintf("    %s = %s\n", name, result.Captured[name]))
+		}
+	}
+
 	// Error if present
 	if result.Error != nil {
 		sb.WriteString("\n")
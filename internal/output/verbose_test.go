@@ -37,7 +37,7 @@ func TestVerboseFormatter_FormatResult_Success(t *testing.T) {
 			Headers: map[string]string{
 				"Accept": "application/json",
 			},
-			Body: "",
+			Body: nil,
 		},
 		StatusCode:   200,
 		ResponseTime: 150 * time.Millisecond,
@@ -184,7 +184,7 @@ func TestVerboseFormatter_FormatResult_LongBody(t *testing.T) {
 		PreparedRequest: &models.PreparedRequest{
 			Method: "POST",
 			URL:    "https://api.example.com/data",
-			Body:   longRequestBody,
+			Body:   []byte(longRequestBody),
 		},
 		StatusCode:   200,
 		ResponseTime: 100 * time.Millisecond,
@@ -264,3 +264,53 @@ func TestVerboseFormatter_FormatResult_NoRequestDetails(t *testing.T) {
 		t.Error("Output should contain status code")
 	}
 }
+
+func TestVerboseFormatter_FormatResult_TLS(t *testing.T) {
+	formatter := NewVerboseFormatter(true)
+
+	result := models.TestResult{
+		Test:       models.Test{Name: "Secure"},
+		StatusCode: 200,
+		TLS: &models.TLSInfo{
+			Version:     "1.2",
+			CipherSuite: "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256",
+			Subject:     "api.example.com",
+			Issuer:      "Example CA",
+			DNSNames:    []string{"api.example.com", "www.example.com"},
+			NotAfter:    time.Now().Add(10*24*time.Hour + time.Hour),
+		},
+		Success: true,
+	}
+
+	output := formatter.FormatResult(result)
+
+	for _, want := range []string{
+		"TLS:",
+		"1.2 (TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256)",
+		"Issuer:           Example CA",
+		"SAN:              api.example.com, www.example.com",
+		"(10 days)",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Output should contain %q, got:\n%s", want, output)
+		}
+	}
+}
+
+func TestVerboseFormatter_FormatResult_CapturedSorted(t *testing.T) {
+	formatter := NewVerboseFormatter(true)
+
+	result := models.TestResult{
+		Test:       models.Test{Name: "Login"},
+		StatusCode: 200,
+		Captured:   map[string]string{"token": "abc", "order": "42", "session": "s1", "id": "7"},
+		Success:    true,
+	}
+
+	want := "    id = 7\n    order = 42\n    session = s1\n    token = abc\n"
+	for range 5 {
+		if output := formatter.FormatResult(result); !strings.Contains(output, want) {
+			t.Fatalf("Captured variables should be sorted by name, got:\n%s", output)
+		}
+	}
+}
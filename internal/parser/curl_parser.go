@@ -1,38 +1,165 @@
 package parser
 
 import (
+	"bytes"
 	"encoding/base64"
 	"fmt"
-	"regexp"
+	"mime/multipart"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
+	"curlex/internal/formdata"
 	"curlex/internal/models"
 )
 
-// Pre-compiled regex patterns for performance
-var (
-	// URL extraction patterns
-	urlPatternDoubleQuote = regexp.MustCompile(`"([^"]+)"`)
-	urlPatternSingleQuote = regexp.MustCompile(`'([^']+)'`)
-	urlPatternHTTP        = regexp.MustCompile(`(https?://[^\s]+)`)
-	urlPatternGeneric     = regexp.MustCompile(`([a-zA-Z0-9\.\-_:/@]+[^\s]*)`)
-
-	// Flag parsing patterns
-	flagMethodShort     = regexp.MustCompile(`-X\s+(\w+)`)
-	flagMethodLong      = regexp.MustCompile(`--request\s+(\w+)`)
-	flagHeaderShort     = regexp.MustCompile(`-H\s+["']([^"']+)["']`)
-	flagHeaderLong      = regexp.MustCompile(`--header\s+["']([^"']+)["']`)
-	flagDataShortDouble = regexp.MustCompile(`-d\s+"([^"]+)"`)
-	flagDataShortSingle = regexp.MustCompile(`-d\s+'([^']+)'`)
-	flagDataLongDouble  = regexp.MustCompile(`--data\s+"([^"]+)"`)
-	flagDataLongSingle  = regexp.MustCompile(`--data\s+'([^']+)'`)
-	flagUserShort       = regexp.MustCompile(`-u\s+["']([^"']+)["']`)
-	flagUserLong        = regexp.MustCompile(`--user\s+["']([^"']+)["']`)
-	flagUserAgentShort  = regexp.MustCompile(`-A\s+["']([^"']+)["']`)
-	flagUserAgentLong   = regexp.MustCompile(`--user-agent\s+["']([^"']+)["']`)
-	flagCookieShort     = regexp.MustCompile(`-b\s+["']([^"']+)["']`)
-	flagCookieLong      = regexp.MustCompile(`--cookie\s+["']([^"']+)["']`)
-)
+// curlFlagSpec describes how a curl flag is parsed and handled
+type curlFlagSpec struct {
+	takesValue  bool // flag consumes the following argument
+	ignored     bool // accepted for compatibility, has no effect on the request
+	unsupported bool // known to curl but not implemented by curlex
+}
+
+// curlFlags lists the curl flags curlex recognizes, keyed by long form
+// Unsupported flags are listed so their values are never mistaken for the URL;
+// flags missing from the list are assumed to take no value
+var curlFlags = map[string]curlFlagSpec{
+	// Request
+	"--request":     {takesValue: true},
+	"--header":      {takesValue: true},
+	"--url":         {takesValue: true},
+	"--get":         {},
+	"--head":        {},
+	"--user":        {takesValue: true},
+	"--user-agent":  {takesValue: true},
+	"--referer":     {takesValue: true},
+	"--cookie":      {takesValue: true},
+	"--compressed":  {},
+	"--max-time":    {takesValue: true},
+	"--location":    {},
+	"--max-redirs":  {takesValue: true},
+	"--insecure":    {},
+	"--cacert":      {takesValue: true},
+	"--cert":        {takesValue: true},
+	"--key":         {takesValue: true},
+	"--proxy":       {takesValue: true},
+	"--noproxy":     {takesValue: true},
+	"--resolve":     {takesValue: true},
+	"--unix-socket": {takesValue: true},
+	"--form":        {takesValue: true},
+	"--form-string": {takesValue: true},
+
+	// Body
+	"--data":           {takesValue: true},
+	"--data-ascii":     {takesValue: true},
+	"--data-raw":       {takesValue: true},
+	"--data-binary":    {takesValue: true},
+	"--data-urlencode": {takesValue: true},
+	"--json":           {takesValue: true},
+
+	// Output and progress options that don't change the request
+	"--silent":            {ignored: true},
+	"--show-error":        {ignored: true},
+	"--verbose":           {ignored: true},
+	"--include":           {ignored: true},
+	"--fail":              {ignored: true},
+	"--no-progress-meter": {ignored: true},
+	"--progress-bar":      {ignored: true},
+	"--output":            {takesValue: true, ignored: true},
+	"--write-out":         {takesValue: true, ignored: true},
+
+	// Known flags curlex does not implement
+	"--connect-timeout": {takesValue: true, unsupported: true},
+	"--retry":           {takesValue: true, unsupported: true},
+	"--cookie-jar":      {takesValue: true, unsupported: true},
+	"--upload-file":     {takesValue: true, unsupported: true},
+	"--range":           {takesValue: true, unsupported: true},
+	"--oauth2-bearer":   {takesValue: true, unsupported: true},
+	"--proxy-user":      {takesValue: true, unsupported: true},
+	"--interface":       {takesValue: true, unsupported: true},
+	"--retry-delay":     {takesValue: true, unsupported: true},
+	"--retry-max-time":  {takesValue: true, unsupported: true},
+	"--continue-at":     {takesValue: true, unsupported: true},
+	"--limit-rate":      {takesValue: true, unsupported: true},
+	"--max-filesize":    {takesValue: true, unsupported: true},
+	"--speed-limit":     {takesValue: true, unsupported: true},
+	"--speed-time":      {takesValue: true, unsupported: true},
+	"--keepalive-time":  {takesValue: true, unsupported: true},
+	"--dns-servers":     {takesValue: true, unsupported: true},
+	"--local-port":      {takesValue: true, unsupported: true},
+	"--connect-to":      {takesValue: true, unsupported: true},
+	"--ciphers":         {takesValue: true, unsupported: true},
+	"--tls-max":         {takesValue: true, unsupported: true},
+	"--capath":          {takesValue: true, unsupported: true},
+	"--crlfile":         {takesValue: true, unsupported: true},
+	"--pinnedpubkey":    {takesValue: true, unsupported: true},
+	"--cert-type":       {takesValue: true, unsupported: true},
+	"--key-type":        {takesValue: true, unsupported: true},
+	"--pass":            {takesValue: true, unsupported: true},
+	"--proxy-header":    {takesValue: true, unsupported: true},
+	"--proxy-cacert":    {takesValue: true, unsupported: true},
+	"--preproxy":        {takesValue: true, unsupported: true},
+	"--socks5":          {takesValue: true, unsupported: true},
+	"--socks5-hostname": {takesValue: true, unsupported: true},
+	"--netrc-file":      {takesValue: true, unsupported: true},
+	"--aws-sigv4":       {takesValue: true, unsupported: true},
+	"--dump-header":     {takesValue: true, unsupported: true},
+	"--trace":           {takesValue: true, unsupported: true},
+	"--trace-ascii":     {takesValue: true, unsupported: true},
+	"--stderr":          {takesValue: true, unsupported: true},
+	"--config":          {takesValue: true, unsupported: true},
+	"--time-cond":       {takesValue: true, unsupported: true},
+	"--url-query":       {takesValue: true, unsupported: true},
+	"--variable":        {takesValue: true, unsupported: true},
+	"--request-target":  {takesValue: true, unsupported: true},
+	"--http1.1":         {unsupported: true},
+	"--http2":           {unsupported: true},
+	"--digest":          {unsupported: true},
+	"--ntlm":            {unsupported: true},
+}
+
+// curlFlagAliases maps short curl flags to their long form
+var curlFlagAliases = map[string]string{
+	"-X": "--request",
+	"-H": "--header",
+	"-G": "--get",
+	"-I": "--head",
+	"-u": "--user",
+	"-A": "--user-agent",
+	"-e": "--referer",
+	"-b": "--cookie",
+	"-m": "--max-time",
+	"-L": "--location",
+	"-k": "--insecure",
+	"-E": "--cert",
+	"-x": "--proxy",
+	"-F": "--form",
+	"-d": "--data",
+	"-s": "--silent",
+	"-S": "--show-error",
+	"-v": "--verbose",
+	"-i": "--include",
+	"-f": "--fail",
+	"-#": "--progress-bar",
+	"-o": "--output",
+	"-w": "--write-out",
+	"-c": "--cookie-jar",
+	"-T": "--upload-file",
+	"-r": "--range",
+	"-C": "--continue-at",
+	"-Y": "--speed-limit",
+	"-y": "--speed-time",
+	"-U": "--proxy-user",
+	"-D": "--dump-header",
+	"-K": "--config",
+	"-z": "--time-cond",
+}
+
+// curlDefaultMaxRedirs is curl's redirect limit for -L without --max-redirs
+const curlDefaultMaxRedirs = 50
 
 // CurlParser parses curl command strings
 type CurlParser struct{}
@@ -42,169 +169,479 @@ func NewCurlParser() *CurlParser {
 	return &CurlParser{}
 }
 
+// curlFlag is a single flag occurrence with its value, if it takes one
+type curlFlag struct {
+	name  string // long form, e.g. --header
+	arg   string // flag as written, e.g. -H
+	value string
+}
+
 // ParseCurl converts a curl command string to a PreparedRequest
-// Supports common flags: -X, -H, -d, -u, -A, --json
+// Unsupported flags are ignored; use UnsupportedFlags to report them
+// Files referenced with @file are resolved against the working directory
 func (p *CurlParser) ParseCurl(curlCmd string) (*models.PreparedRequest, error) {
+	return p.ParseCurlRelativeTo(curlCmd, "")
+}
+
+// ParseCurlRelativeTo is like ParseCurl but resolves relative @file paths
+// against baseDir, typically the directory of the test file
+func (p *CurlParser) ParseCurlRelativeTo(curlCmd, baseDir string) (*models.PreparedRequest, error) {
+	flags, urls, err := p.tokenize(curlCmd)
+	if err != nil {
+		return nil, err
+	}
+
 	req := &models.PreparedRequest{
 		Method:  "GET", // default
 		Headers: make(map[string]string),
 	}
 
-	// Clean up the command
-	curlCmd = strings.TrimSpace(curlCmd)
-
-	// Remove 'curl' prefix if present
-	curlCmd = strings.TrimPrefix(curlCmd, "curl ")
-	curlCmd = strings.TrimPrefix(curlCmd, "curl")
-	curlCmd = strings.TrimSpace(curlCmd)
+	if err := p.applyFlags(flags, &urls, baseDir, req); err != nil {
+		return nil, err
+	}
 
-	// Extract URL (first argument that doesn't start with -)
-	url, remaining := p.extractURL(curlCmd)
-	if url == "" {
+	if len(urls) == 0 {
 		return nil, fmt.Errorf("no URL found in curl command")
 	}
-	req.URL = url
-
-	// Parse flags
-	if err := p.parseFlags(remaining, req); err != nil {
-		return nil, err
+	if len(urls) > 1 {
+		return nil, fmt.Errorf("multiple URLs in curl command: %s", strings.Join(urls, ", "))
 	}
+	req.URL = urls[0]
 
 	return req, nil
 }
 
-// extractURL extracts the URL from the curl command
-func (p *CurlParser) extractURL(cmd string) (string, string) {
-	// Use pre-compiled regex patterns for performance
-	patterns := []*regexp.Regexp{
-		urlPatternDoubleQuote,
-		urlPatternSingleQuote,
-		urlPatternHTTP,
-		urlPatternGeneric,
-	}
-
-	for _, re := range patterns {
-		matches := re.FindStringSubmatch(cmd)
-		if len(matches) > 1 {
-			url := matches[1]
-			// Make sure it looks like a URL
-			if strings.Contains(url, "://") || strings.HasPrefix(url, "http") {
-				remaining := re.ReplaceAllString(cmd, "")
-				return url, remaining
-			}
-		}
+// UnsupportedFlags returns the flags in a curl command that curlex does not
+// implement, in command order; they are ignored by ParseCurl
+func (p *CurlParser) UnsupportedFlags(curlCmd string) ([]string, error) {
+	flags, _, err := p.tokenize(curlCmd)
+	if err != nil {
+		return nil, err
 	}
 
-	// Fallback: extract first non-flag argument
-	parts := strings.Fields(cmd)
-	for i, part := range parts {
-		if !strings.HasPrefix(part, "-") {
-			remaining := strings.Join(append(parts[:i], parts[i+1:]...), " ")
-			return part, remaining
+	var unsupported []string
+	for _, flag := range flags {
+		spec, known := curlFlags[flag.name]
+		if !known || spec.unsupported {
+			unsupported = append(unsupported, flag.arg)
 		}
 	}
+	return unsupported, nil
+}
+
+// tokenize splits a curl command into flags and positional URL arguments
+func (p *CurlParser) tokenize(curlCmd string) ([]curlFlag, []string, error) {
+	args, err := splitShellWords(curlCmd)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid curl command: %w", err)
+	}
 
-	return "", cmd
+	// Remove 'curl' prefix if present
+	if len(args) > 0 && args[0] == "curl" {
+		args = args[1:]
+	}
+
+	return p.splitArgs(args)
 }
 
-// parseFlags parses curl flags from the command
-func (p *CurlParser) parseFlags(cmd string, req *models.PreparedRequest) error {
-	// Parse -X/--request METHOD
-	if method := p.extractFlagRe(cmd, flagMethodShort); method != "" {
-		req.Method = strings.ToUpper(method)
-	} else if method := p.extractFlagRe(cmd, flagMethodLong); method != "" {
-		req.Method = strings.ToUpper(method)
+// splitArgs separates flags, with their values, from positional URL arguments
+// Short flags may be combined (-sS) and take their value attached (-XPOST)
+// or as the next argument; "--" ends flag parsing
+func (p *CurlParser) splitArgs(args []string) ([]curlFlag, []string, error) {
+	var flags []curlFlag
+	var urls []string
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		switch {
+		case arg == "--":
+			urls = append(urls, args[i+1:]...)
+			return flags, urls, nil
+
+		case strings.HasPrefix(arg, "--"):
+			flag := curlFlag{name: arg, arg: arg}
+			if curlFlags[arg].takesValue {
+				if i+1 >= len(args) {
+					return nil, nil, fmt.Errorf("flag %s requires a value", arg)
+				}
+				i++
+				flag.value = args[i]
+			}
+			flags = append(flags, flag)
+
+		case strings.HasPrefix(arg, "-") && len(arg) > 1:
+			// Walk combined short flags until one takes a value
+			for j := 1; j < len(arg); j++ {
+				short := "-" + string(arg[j])
+				flag := curlFlag{name: short, arg: short}
+				if long, ok := curlFlagAliases[short]; ok {
+					flag.name = long
+				}
+
+				if curlFlags[flag.name].takesValue {
+					if rest := arg[j+1:]; rest != "" {
+						flag.value = rest
+					} else if i+1 < len(args) {
+						i++
+						flag.value = args[i]
+					} else {
+						return nil, nil, fmt.Errorf("flag %s requires a value", short)
+					}
+					flags = append(flags, flag)
+					break
+				}
+				flags = append(flags, flag)
+			}
+
+		default:
+			urls = append(urls, arg)
+		}
 	}
 
-	// Parse -H/--header "Header: Value"
-	headers := p.extractMultipleFlagsRe(cmd, flagHeaderShort)
-	headers = append(headers, p.extractMultipleFlagsRe(cmd, flagHeaderLong)...)
-	for _, header := range headers {
-		parts := strings.SplitN(header, ":", 2)
-		if len(parts) == 2 {
-			key := strings.TrimSpace(parts[0])
-			value := strings.TrimSpace(parts[1])
-			req.Headers[key] = value
+	return flags, urls, nil
+}
+
+// applyFlags maps parsed flags onto the request in command order
+// --url adds to urls like a positional argument
+func (p *CurlParser) applyFlags(flags []curlFlag, urls *[]string, baseDir string, req *models.PreparedRequest) error {
+	var (
+		method       string
+		data         []string
+		form         []curlFlag
+		cookies      []string
+		useGet       bool
+		useHead      bool
+		follow       bool
+		maxRedirects *int
+	)
+
+	for _, flag := range flags {
+		switch flag.name {
+		case "--request":
+			method = strings.ToUpper(flag.value)
+
+		case "--url":
+			*urls = append(*urls, flag.value)
+
+		case "--header":
+			parts := strings.SplitN(flag.value, ":", 2)
+			if len(parts) == 2 {
+				key := strings.TrimSpace(parts[0])
+				value := strings.TrimSpace(parts[1])
+				req.Headers[key] = value
+			}
+
+		case "--data", "--data-ascii":
+			// Multiple data values are joined with & as curl does
+			// Newlines are stripped from @file contents, as curl does for -d
+			value, err := readDataArg(flag, baseDir)
+			if err != nil {
+				return err
+			}
+			if strings.HasPrefix(flag.value, "@") {
+				value = strings.NewReplacer("\r", "", "\n", "").Replace(value)
+			}
+			data = append(data, value)
+
+		case "--data-binary":
+			value, err := readDataArg(flag, baseDir)
+			if err != nil {
+				return err
+			}
+			data = append(data, value)
+
+		case "--data-raw":
+			// --data-raw never treats @ specially
+			data = append(data, flag.value)
+
+		case "--data-urlencode":
+			value, err := urlencodeData(flag, baseDir)
+			if err != nil {
+				return err
+			}
+			data = append(data, value)
+
+		case "--json":
+			// --json implies JSON content type and accept headers
+			value, err := readDataArg(flag, baseDir)
+			if err != nil {
+				return err
+			}
+			data = append(data, value)
+			req.Headers["Content-Type"] = "application/json"
+			req.Headers["Accept"] = "application/json"
+
+		case "--form", "--form-string":
+			form = append(form, flag)
+
+		case "--get":
+			useGet = true
+
+		case "--head":
+			useHead = true
+
+		case "--user":
+			// -u/--user "username:password" for Basic Auth
+			encoded := base64.StdEncoding.EncodeToString([]byte(flag.value))
+			req.Headers["Authorization"] = "Basic " + encoded
+
+		case "--user-agent":
+			req.Headers["User-Agent"] = flag.value
+
+		case "--referer":
+			// curl's ";auto" suffix only affects redirects
+			req.Headers["Referer"] = strings.TrimSuffix(flag.value, ";auto")
+
+		case "--cookie":
+			// Multiple cookies are combined with semicolons
+			cookies = append(cookies, flag.value)
+
+		case "--compressed":
+			req.Compressed = true
+
+		case "--max-time":
+			seconds, err := strconv.ParseFloat(flag.value, 64)
+			if err != nil || seconds < 0 {
+				return fmt.Errorf("invalid %s value %q: expected seconds", flag.arg, flag.value)
+			}
+			req.Timeout = time.Duration(seconds * float64(time.Second))
+
+		case "--location":
+			follow = true
+
+		case "--max-redirs":
+			n, err := strconv.Atoi(flag.value)
+			if err != nil || n < -1 {
+				return fmt.Errorf("invalid %s value %q", flag.arg, flag.value)
+			}
+			maxRedirects = &n
+
+		case "--insecure":
+			tlsConfig(req).InsecureSkipVerify = true
+
+		case "--cacert":
+			tlsConfig(req).CAFile = ResolvePath(baseDir, flag.value)
+
+		case "--cert":
+			tlsConfig(req).CertFile = ResolvePath(baseDir, flag.value)
+
+		case "--key":
+			tlsConfig(req).KeyFile = ResolvePath(baseDir, flag.value)
+
+		case "--proxy":
+			req.Proxy = flag.value
+
+		case "--noproxy":
+			req.NoProxy = strings.Split(flag.value, ",")
+
+		case "--resolve":
+			hostPort, addr, err := models.ParseResolve(flag.value)
+			if err != nil {
+				return err
+			}
+			if req.Resolve == nil {
+				req.Resolve = make(map[string]string)
+			}
+			req.Resolve[hostPort] = addr
+
+		case "--unix-socket":
+			req.UnixSocket = ResolvePath(baseDir, flag.value)
 		}
 	}
 
-	// Parse -d/--data "body"
-	// Try double quotes first
-	if body := p.extractFlagRe(cmd, flagDataShortDouble); body != "" {
-		req.Body = body
-		// -d implies POST if method not specified
-		if req.Method == "GET" {
-			req.Method = "POST"
+	// curl only follows redirects with -L; without it the test's own
+	// redirect settings apply
+	if follow {
+		if maxRedirects == nil {
+			n := curlDefaultMaxRedirs
+			maxRedirects = &n
 		}
-	} else if body := p.extractFlagRe(cmd, flagDataShortSingle); body != "" {
-		// Try single quotes
-		req.Body = body
-		if req.Method == "GET" {
-			req.Method = "POST"
+		req.MaxRedirects = maxRedirects
+	}
+
+	if len(form) > 0 {
+		if len(data) > 0 {
+			return fmt.Errorf("cannot combine -F/--form with -d/--data")
 		}
-	} else if body := p.extractFlagRe(cmd, flagDataLongDouble); body != "" {
-		req.Body = body
-		if req.Method == "GET" {
-			req.Method = "POST"
+		if err := buildMultipart(form, baseDir, req); err != nil {
+			return err
 		}
-	} else if body := p.extractFlagRe(cmd, flagDataLongSingle); body != "" {
-		req.Body = body
-		if req.Method == "GET" {
+	}
+
+	if len(data) > 0 {
+		body := strings.Join(data, "&")
+		if useGet {
+			// -G sends the data as the URL query string instead
+			for i, u := range *urls {
+				(*urls)[i] = appendQuery(u, body)
+			}
+		} else {
+			req.Body = []byte(body)
+			// -d implies POST if method not specified
 			req.Method = "POST"
 		}
 	}
 
-	// Parse -u/--user "username:password" for Basic Auth
-	if auth := p.extractFlagRe(cmd, flagUserShort); auth != "" {
-		encoded := base64.StdEncoding.EncodeToString([]byte(auth))
-		req.Headers["Authorization"] = "Basic " + encoded
-	} else if auth := p.extractFlagRe(cmd, flagUserLong); auth != "" {
-		encoded := base64.StdEncoding.EncodeToString([]byte(auth))
-		req.Headers["Authorization"] = "Basic " + encoded
+	if useHead {
+		req.Method = "HEAD"
 	}
 
-	// Parse -A/--user-agent "agent"
-	if agent := p.extractFlagRe(cmd, flagUserAgentShort); agent != "" {
-		req.Headers["User-Agent"] = agent
-	} else if agent := p.extractFlagRe(cmd, flagUserAgentLong); agent != "" {
-		req.Headers["User-Agent"] = agent
+	if method != "" {
+		req.Method = method
 	}
 
-	// Parse -b/--cookie "name=value"
-	// Multiple cookies are combined with semicolons
-	cookies := p.extractMultipleFlagsRe(cmd, flagCookieShort)
-	cookies = append(cookies, p.extractMultipleFlagsRe(cmd, flagCookieLong)...)
 	if len(cookies) > 0 {
-		// Combine multiple cookies with semicolons
 		req.Headers["Cookie"] = strings.Join(cookies, "; ")
 	}
 
-	// Parse --json (implies -H "Content-Type: application/json")
-	if strings.Contains(cmd, "--json") {
-		req.Headers["Content-Type"] = "application/json"
-		req.Headers["Accept"] = "application/json"
+	return nil
+}
+
+// tlsConfig returns the request's TLS settings, creating them if needed
+func tlsConfig(req *models.PreparedRequest) *models.TLSConfig {
+	if req.TLS == nil {
+		req.TLS = &models.TLSConfig{}
 	}
+	return req.TLS
+}
 
-	return nil
+// readDataArg returns a data flag's value, reading the file for @file values
+func readDataArg(flag curlFlag, baseDir string) (string, error) {
+	path, isFile := strings.CutPrefix(flag.value, "@")
+	if !isFile {
+		return flag.value, nil
+	}
+	content, err := readCurlFile(flag.arg, path, baseDir)
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
 }
 
-// extractFlagRe extracts a single flag value using pre-compiled regex
-func (p *CurlParser) extractFlagRe(cmd string, re *regexp.Regexp) string {
-	matches := re.FindStringSubmatch(cmd)
-	if len(matches) > 1 {
-		return matches[1]
+// readCurlFile reads a file referenced by a curl flag
+func readCurlFile(arg, path, baseDir string) ([]byte, error) {
+	if path == "-" {
+		return nil, fmt.Errorf("%s: reading from stdin is not supported", arg)
+	}
+	content, err := os.ReadFile(ResolvePath(baseDir, path))
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to read file: %w", arg, err)
 	}
-	return ""
+	return content, nil
 }
 
-// extractMultipleFlagsRe extracts all occurrences of a flag using pre-compiled regex
-func (p *CurlParser) extractMultipleFlagsRe(cmd string, re *regexp.Regexp) []string {
-	matches := re.FindAllStringSubmatch(cmd, -1)
-	var results []string
-	for _, match := range matches {
-		if len(match) > 1 {
-			results = append(results, match[1])
+// urlencodeData encodes a --data-urlencode value
+// Forms: "content", "=content", "name=content", "@file" and "name@file";
+// only the content is encoded. The first = or @ decides the form, as in curl
+func urlencodeData(flag curlFlag, baseDir string) (string, error) {
+	value := flag.value
+	sep := strings.IndexAny(value, "=@")
+	if sep < 0 {
+		return escapeQueryComponent(value), nil
+	}
+
+	name, content := value[:sep], value[sep+1:]
+	if value[sep] == '@' {
+		fileContent, err := readCurlFile(flag.arg, content, baseDir)
+		if err != nil {
+			return "", err
 		}
+		content = string(fileContent)
+	}
+
+	if name == "" {
+		return escapeQueryComponent(content), nil
+	}
+	return name + "=" + escapeQueryComponent(content), nil
+}
+
+// escapeQueryComponent percent-encodes s the way curl does, with %20 for spaces
+func escapeQueryComponent(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+// appendQuery appends an encoded query string to a URL
+func appendQuery(rawURL, query string) string {
+	if strings.Contains(rawURL, "?") {
+		return rawURL + "&" + query
+	}
+	return rawURL + "?" + query
+}
+
+// buildMultipart encodes -F/--form fields as a multipart/form-data body
+// -F supports name=value, name=@file for file uploads and name=<file for a
+// text field read from a file; ;type= and ;filename= adjust file parts
+// --form-string values are always sent literally
+func buildMultipart(fields []curlFlag, baseDir string, req *models.PreparedRequest) error {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	for _, field := range fields {
+		name, value, found := strings.Cut(field.value, "=")
+		if !found || name == "" {
+			return fmt.Errorf("invalid %s value %q: expected name=value", field.arg, field.value)
+		}
+
+		if field.name == "--form" {
+			switch {
+			case strings.HasPrefix(value, "@"):
+				if err := writeFilePart(writer, field.arg, name, value[1:], baseDir); err != nil {
+					return err
+				}
+				continue
+			case strings.HasPrefix(value, "<"):
+				content, err := readCurlFile(field.arg, value[1:], baseDir)
+				if err != nil {
+					return err
+				}
+				value = string(content)
+			}
+		}
+
+		if err := writer.WriteField(name, value); err != nil {
+			return fmt.Errorf("failed to encode form field %s: %w", name, err)
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to encode form: %w", err)
+	}
+
+	req.Body = body.Bytes()
+	req.Headers["Content-Type"] = writer.FormDataContentType()
+	req.Method = "POST"
+
+	return nil
+}
+
+// writeFilePart adds a file upload part from a -F name=@file[;type=...][;filename=...] value
+func writeFilePart(writer *multipart.Writer, arg, name, spec, baseDir string) error {
+	parts := strings.Split(spec, ";")
+	file := models.MultipartFile{Name: name, Path: parts[0]}
+
+	for _, option := range parts[1:] {
+		key, value, _ := strings.Cut(strings.TrimSpace(option), "=")
+		switch key {
+		case "type":
+			file.ContentType = value
+		case "filename":
+			file.Filename = strings.Trim(value, `"`)
+		}
+	}
+
+	content, err := readCurlFile(arg, file.Path, baseDir)
+	if err != nil {
+		return err
+	}
+	return formdata.WriteFile(writer, file, content)
+}
+
+// ResolvePath resolves a file path from a test file against baseDir
+// Absolute paths and an empty baseDir leave the path unchanged
+func ResolvePath(baseDir, path string) string {
+	if baseDir == "" || filepath.IsAbs(path) {
+		return path
 	}
-	return results
+	return filepath.Join(baseDir, path)
 }
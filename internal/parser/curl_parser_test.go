@@ -1,7 +1,18 @@
 package parser
 
 import (
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
+
+	"curlex/internal/models"
 )
 
 func TestCurlParser_ParseCurl(t *testing.T) {
@@ -82,7 +93,7 @@ func TestCurlParser_ParseCurl(t *testing.T) {
 			expectedMethod: "GET",
 			expectedURL:    "https://example.com",
 			headerKey:      "Cookie",
-			headerValue:    "session=abc123; theme=dark; user_id=42",
+			headerValue:    "session=abc123; user_id=42; theme=dark",
 		},
 	}
 
@@ -101,7 +112,7 @@ func TestCurlParser_ParseCurl(t *testing.T) {
 				t.Errorf("URL = %q, want %q", req.URL, tt.expectedURL)
 			}
 
-			if tt.expectedBody != "" && req.Body != tt.expectedBody {
+			if tt.expectedBody != "" && string(req.Body) != tt.expectedBody {
 				t.Errorf("Body = %q, want %q", req.Body, tt.expectedBody)
 			}
 
@@ -115,3 +126,537 @@ func TestCurlParser_ParseCurl(t *testing.T) {
 		})
 	}
 }
+
+func TestCurlParser_ParseCurl_ShellQuoting(t *testing.T) {
+	parser := NewCurlParser()
+
+	tests := []struct {
+		name           string
+		curl           string
+		expectedMethod string
+		expectedURL    string
+		expectedBody   string
+		headers        map[string]string
+	}{
+		{
+			name:           "json body with escaped double quotes",
+			curl:           `curl -X POST -d "{\"name\":\"test\"}" https://example.com`,
+			expectedMethod: "POST",
+			expectedURL:    "https://example.com",
+			expectedBody:   `{"name":"test"}`,
+		},
+		{
+			name:           "header value containing a single quote",
+			curl:           `curl -H "X-Name: O'Brien" https://example.com`,
+			expectedMethod: "GET",
+			expectedURL:    "https://example.com",
+			headers:        map[string]string{"X-Name": "O'Brien"},
+		},
+		{
+			name:           "quoted header before the URL is not the URL",
+			curl:           `curl -H 'Referer: https://other.example.com/' 'https://example.com/api'`,
+			expectedMethod: "GET",
+			expectedURL:    "https://example.com/api",
+			headers:        map[string]string{"Referer": "https://other.example.com/"},
+		},
+		{
+			name: "copied from browser devtools",
+			curl: "curl 'https://example.com/api/items?page=2' \\\n" +
+				"  -H 'accept: application/json' \\\n" +
+				"  -H 'content-type: application/json' \\\n" +
+				"  -H $'x-note: it\\'s \\\"quoted\\\"' \\\n" +
+				"  --compressed",
+			expectedMethod: "GET",
+			expectedURL:    "https://example.com/api/items?page=2",
+			headers:        map[string]string{"accept": "application/json", "content-type": "application/json", "x-note": `it's "quoted"`},
+		},
+		{
+			name:           "ansi-c quoted body",
+			curl:           `curl $'https://example.com' -d $'a=1\nb=2'`,
+			expectedMethod: "POST",
+			expectedURL:    "https://example.com",
+			expectedBody:   "a=1\nb=2",
+		},
+		{
+			name:           "flags after the URL",
+			curl:           `curl https://example.com -X PUT -d 'x=1'`,
+			expectedMethod: "PUT",
+			expectedURL:    "https://example.com",
+			expectedBody:   "x=1",
+		},
+		{
+			name:           "attached short flag value",
+			curl:           `curl -XDELETE https://example.com`,
+			expectedMethod: "DELETE",
+			expectedURL:    "https://example.com",
+		},
+		{
+			name:           "combined short flags",
+			curl:           `curl -sSX PATCH https://example.com`,
+			expectedMethod: "PATCH",
+			expectedURL:    "https://example.com",
+		},
+		{
+			name:           "multiple data flags are joined",
+			curl:           `curl -d a=1 -d b=2 https://example.com`,
+			expectedMethod: "POST",
+			expectedURL:    "https://example.com",
+			expectedBody:   "a=1&b=2",
+		},
+		{
+			name:           "json flag sets body and headers",
+			curl:           `curl --json '{"a":1}' https://example.com`,
+			expectedMethod: "POST",
+			expectedURL:    "https://example.com",
+			expectedBody:   `{"a":1}`,
+			headers:        map[string]string{"Content-Type": "application/json", "Accept": "application/json"},
+		},
+		{
+			name:           "variable placeholders survive tokenizing",
+			curl:           `curl -H "Authorization: Bearer ${token}" ${base_url}/users`,
+			expectedMethod: "GET",
+			expectedURL:    "${base_url}/users",
+			headers:        map[string]string{"Authorization": "Bearer ${token}"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := parser.ParseCurl(tt.curl)
+			if err != nil {
+				t.Fatalf("ParseCurl failed: %v", err)
+			}
+
+			if req.Method != tt.expectedMethod {
+				t.Errorf("Method = %q, want %q", req.Method, tt.expectedMethod)
+			}
+			if req.URL != tt.expectedURL {
+				t.Errorf("URL = %q, want %q", req.URL, tt.expectedURL)
+			}
+			if tt.expectedBody != "" && string(req.Body) != tt.expectedBody {
+				t.Errorf("Body = %q, want %q", req.Body, tt.expectedBody)
+			}
+			for key, want := range tt.headers {
+				if got := req.Headers[key]; got != want {
+					t.Errorf("Header %q = %q, want %q", key, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCurlParser_ParseCurl_Errors(t *testing.T) {
+	parser := NewCurlParser()
+
+	tests := []struct {
+		name string
+		curl string
+	}{
+		{"no URL", `curl -X GET`},
+		{"multiple URLs", `curl https://a.example.com https://b.example.com`},
+		{"unterminated quote", `curl -d '{"a":1} https://example.com`},
+		{"missing flag value", `curl https://example.com -H`},
+		{"invalid max-time", `curl -m soon https://example.com`},
+		{"invalid resolve", `curl --resolve example.com:443 https://example.com`},
+		{"missing data file", `curl -d @does-not-exist.json https://example.com`},
+		{"data from stdin", `curl --data-binary @- https://example.com`},
+		{"form combined with data", `curl -F 'a=1' -d 'b=2' https://example.com`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parser.ParseCurl(tt.curl); err == nil {
+				t.Error("Expected error, got none")
+			}
+		})
+	}
+}
+
+func TestCurlParser_ParseCurl_Flags(t *testing.T) {
+	parser := NewCurlParser()
+
+	tests := []struct {
+		name   string
+		curl   string
+		verify func(t *testing.T, req *models.PreparedRequest)
+	}{
+		{
+			name: "data-raw implies POST",
+			curl: `curl 'https://example.com' --data-raw '{"q":"x"}'`,
+			verify: func(t *testing.T, req *models.PreparedRequest) {
+				if req.Method != "POST" || string(req.Body) != `{"q":"x"}` {
+					t.Errorf("Method = %q, Body = %q", req.Method, req.Body)
+				}
+			},
+		},
+		{
+			name: "data-binary keeps body as-is",
+			curl: `curl --data-binary $'line1\nline2' https://example.com`,
+			verify: func(t *testing.T, req *models.PreparedRequest) {
+				if string(req.Body) != "line1\nline2" {
+					t.Errorf("Body = %q", req.Body)
+				}
+			},
+		},
+		{
+			name: "data-urlencode forms",
+			curl: `curl --data-urlencode 'q=a b&c' --data-urlencode '=x/y' --data-urlencode 'plain text' https://example.com`,
+			verify: func(t *testing.T, req *models.PreparedRequest) {
+				if want := "q=a%20b%26c&x%2Fy&plain%20text"; string(req.Body) != want {
+					t.Errorf("Body = %q, want %q", req.Body, want)
+				}
+			},
+		},
+		{
+			name: "get moves data into the query string",
+			curl: `curl -G -d 'a=1' --data-urlencode 'b=x y' 'https://example.com/search?page=1'`,
+			verify: func(t *testing.T, req *models.PreparedRequest) {
+				if req.Method != "GET" || string(req.Body) != "" {
+					t.Errorf("Method = %q, Body = %q", req.Method, req.Body)
+				}
+				if want := "https://example.com/search?page=1&a=1&b=x%20y"; req.URL != want {
+					t.Errorf("URL = %q, want %q", req.URL, want)
+				}
+			},
+		},
+		{
+			name: "head",
+			curl: `curl -I https://example.com`,
+			verify: func(t *testing.T, req *models.PreparedRequest) {
+				if req.Method != "HEAD" {
+					t.Errorf("Method = %q, want HEAD", req.Method)
+				}
+			},
+		},
+		{
+			name: "explicit method wins over data and head",
+			curl: `curl -I -X OPTIONS -d x=1 https://example.com`,
+			verify: func(t *testing.T, req *models.PreparedRequest) {
+				if req.Method != "OPTIONS" {
+					t.Errorf("Method = %q, want OPTIONS", req.Method)
+				}
+			},
+		},
+		{
+			name: "url flag",
+			curl: `curl -X GET --url https://example.com/api`,
+			verify: func(t *testing.T, req *models.PreparedRequest) {
+				if req.URL != "https://example.com/api" {
+					t.Errorf("URL = %q", req.URL)
+				}
+			},
+		},
+		{
+			name: "location uses curl's default limit",
+			curl: `curl -L https://example.com`,
+			verify: func(t *testing.T, req *models.PreparedRequest) {
+				if req.MaxRedirects == nil || *req.MaxRedirects != 50 {
+					t.Errorf("MaxRedirects = %v, want 50", req.MaxRedirects)
+				}
+			},
+		},
+		{
+			name: "location with max-redirs",
+			curl: `curl -L --max-redirs 3 https://example.com`,
+			verify: func(t *testing.T, req *models.PreparedRequest) {
+				if req.MaxRedirects == nil || *req.MaxRedirects != 3 {
+					t.Errorf("MaxRedirects = %v, want 3", req.MaxRedirects)
+				}
+			},
+		},
+		{
+			name: "max-redirs without location has no effect",
+			curl: `curl --max-redirs 3 https://example.com`,
+			verify: func(t *testing.T, req *models.PreparedRequest) {
+				if req.MaxRedirects != nil {
+					t.Errorf("MaxRedirects = %v, want nil", *req.MaxRedirects)
+				}
+			},
+		},
+		{
+			name: "max-time",
+			curl: `curl -m 2.5 https://example.com`,
+			verify: func(t *testing.T, req *models.PreparedRequest) {
+				if req.Timeout != 2500*time.Millisecond {
+					t.Errorf("Timeout = %v, want 2.5s", req.Timeout)
+				}
+			},
+		},
+		{
+			name: "tls flags",
+			curl: `curl -k --cacert ca.pem -E client.pem --key client.key https://example.com`,
+			verify: func(t *testing.T, req *models.PreparedRequest) {
+				want := models.TLSConfig{CAFile: "ca.pem", CertFile: "client.pem", KeyFile: "client.key", InsecureSkipVerify: true}
+				if req.TLS == nil || *req.TLS != want {
+					t.Errorf("TLS = %+v, want %+v", req.TLS, want)
+				}
+			},
+		},
+		{
+			name: "compressed, referer and proxy",
+			curl: `curl --compressed -e 'https://ref.example.com;auto' -x proxy.local:3128 https://example.com`,
+			verify: func(t *testing.T, req *models.PreparedRequest) {
+				if !req.Compressed {
+					t.Error("Expected Compressed to be set")
+				}
+				if req.Headers["Referer"] != "https://ref.example.com" {
+					t.Errorf("Referer = %q", req.Headers["Referer"])
+				}
+				if req.Proxy != "proxy.local:3128" {
+					t.Errorf("Proxy = %q", req.Proxy)
+				}
+			},
+		},
+		{
+			name: "unix socket",
+			curl: `curl --unix-socket /var/run/docker.sock http://localhost/version`,
+			verify: func(t *testing.T, req *models.PreparedRequest) {
+				if req.UnixSocket != "/var/run/docker.sock" {
+					t.Errorf("UnixSocket = %q", req.UnixSocket)
+				}
+			},
+		},
+		{
+			name: "noproxy",
+			curl: `curl -x socks5://proxy.local:1080 --noproxy localhost,.internal https://example.com`,
+			verify: func(t *testing.T, req *models.PreparedRequest) {
+				if req.Proxy != "socks5://proxy.local:1080" {
+					t.Errorf("Proxy = %q", req.Proxy)
+				}
+				if len(req.NoProxy) != 2 || req.NoProxy[0] != "localhost" || req.NoProxy[1] != ".internal" {
+					t.Errorf("NoProxy = %v", req.NoProxy)
+				}
+			},
+		},
+		{
+			name: "resolve",
+			curl: `curl --resolve example.com:443:127.0.0.1 --resolve api.example.com:8443:[::1] https://example.com`,
+			verify: func(t *testing.T, req *models.PreparedRequest) {
+				if req.Resolve["example.com:443"] != "127.0.0.1" || req.Resolve["api.example.com:8443"] != "::1" {
+					t.Errorf("Resolve = %v", req.Resolve)
+				}
+			},
+		},
+		{
+			name: "multipart form",
+			curl: `curl -F 'name=Ada' --form-string 'note=@literal' https://example.com/upload`,
+			verify: func(t *testing.T, req *models.PreparedRequest) {
+				if req.Method != "POST" {
+					t.Errorf("Method = %q, want POST", req.Method)
+				}
+				mediaType, params, err := mime.ParseMediaType(req.Headers["Content-Type"])
+				if err != nil || mediaType != "multipart/form-data" {
+					t.Fatalf("Content-Type = %q", req.Headers["Content-Type"])
+				}
+				form, err := multipart.NewReader(bytes.NewReader(req.Body), params["boundary"]).ReadForm(1 << 20)
+				if err != nil {
+					t.Fatalf("Invalid multipart body: %v", err)
+				}
+				if form.Value["name"][0] != "Ada" || form.Value["note"][0] != "@literal" {
+					t.Errorf("Form values = %v", form.Value)
+				}
+			},
+		},
+		{
+			name: "ignored output flags",
+			curl: `curl -sS -o /dev/null -w '%{http_code}' https://example.com`,
+			verify: func(t *testing.T, req *models.PreparedRequest) {
+				if req.URL != "https://example.com" || req.Method != "GET" {
+					t.Errorf("Method = %q, URL = %q", req.Method, req.URL)
+				}
+			},
+		},
+		{
+			name: "unsupported flag value is not a URL",
+			curl: `curl --retry-delay 5 -H 'Accept: text/plain' https://example.com`,
+			verify: func(t *testing.T, req *models.PreparedRequest) {
+				if req.URL != "https://example.com" || req.Headers["Accept"] != "text/plain" {
+					t.Errorf("URL = %q, Headers = %v", req.URL, req.Headers)
+				}
+			},
+		},
+		{
+			name: "unknown flag before a URL without a scheme",
+			curl: `curl --http3 example.com`,
+			verify: func(t *testing.T, req *models.PreparedRequest) {
+				if req.URL != "example.com" {
+					t.Errorf("URL = %q, want example.com", req.URL)
+				}
+			},
+		},
+		{
+			name: "unknown flag before a host and port",
+			curl: `curl --tcp-nodelay localhost:8080/users`,
+			verify: func(t *testing.T, req *models.PreparedRequest) {
+				if req.URL != "localhost:8080/users" {
+					t.Errorf("URL = %q, want localhost:8080/users", req.URL)
+				}
+			},
+		},
+		{
+			name: "unknown flag before a variable URL",
+			curl: `curl --tcp-nodelay ${BASE_URL}/users`,
+			verify: func(t *testing.T, req *models.PreparedRequest) {
+				if req.URL != "${BASE_URL}/users" {
+					t.Errorf("URL = %q, want ${BASE_URL}/users", req.URL)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := parser.ParseCurl(tt.curl)
+			if err != nil {
+				t.Fatalf("ParseCurl failed: %v", err)
+			}
+			tt.verify(t, req)
+		})
+	}
+}
+
+func TestCurlParser_UnsupportedFlags(t *testing.T) {
+	parser := NewCurlParser()
+
+	tests := []struct {
+		name     string
+		curl     string
+		expected []string
+	}{
+		{"all supported", `curl -sSL -k -H 'A: b' https://example.com`, nil},
+		{"unknown long flag", `curl --frobnicate https://example.com`, []string{"--frobnicate"}},
+		{"known unsupported flag with value", `curl --connect-timeout 5 https://example.com`, []string{"--connect-timeout"}},
+		{"unknown short flag in a group", `curl -sZ https://example.com`, []string{"-Z"}},
+		{"listed flag with value", `curl --retry-delay 5 https://example.com`, []string{"--retry-delay"}},
+		{"listed short flag with value", `curl -D headers.txt https://example.com`, []string{"-D"}},
+		{"unknown long flag before a variable URL", `curl --tcp-nodelay ${BASE_URL}/users`, []string{"--tcp-nodelay"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			unsupported, err := parser.UnsupportedFlags(tt.curl)
+			if err != nil {
+				t.Fatalf("UnsupportedFlags failed: %v", err)
+			}
+			if !reflect.DeepEqual(unsupported, tt.expected) {
+				t.Errorf("UnsupportedFlags() = %v, want %v", unsupported, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCurlParser_ParseCurlRelativeTo_Files(t *testing.T) {
+	dir := t.TempDir()
+	binary := []byte{0x89, 'P', 'N', 'G', 0x00, 0xff, 0x10, '\n'}
+	files := map[string][]byte{
+		"payload.json": []byte("{\n  \"name\": \"test\"\r\n}\n"),
+		"upload.png":   binary,
+		"note.txt":     []byte("hello world"),
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), content, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	parser := NewCurlParser()
+
+	tests := []struct {
+		name   string
+		curl   string
+		verify func(t *testing.T, req *models.PreparedRequest)
+	}{
+		{
+			name: "data strips newlines from files",
+			curl: `curl -d @payload.json https://example.com`,
+			verify: func(t *testing.T, req *models.PreparedRequest) {
+				if want := `{  "name": "test"}`; string(req.Body) != want {
+					t.Errorf("Body = %q, want %q", req.Body, want)
+				}
+			},
+		},
+		{
+			name: "data-binary sends files byte for byte",
+			curl: `curl --data-binary @upload.png https://example.com`,
+			verify: func(t *testing.T, req *models.PreparedRequest) {
+				if !bytes.Equal(req.Body, binary) {
+					t.Errorf("Body = %v, want %v", req.Body, binary)
+				}
+			},
+		},
+		{
+			name: "data-raw keeps the @",
+			curl: `curl --data-raw @payload.json https://example.com`,
+			verify: func(t *testing.T, req *models.PreparedRequest) {
+				if string(req.Body) != "@payload.json" {
+					t.Errorf("Body = %q", req.Body)
+				}
+			},
+		},
+		{
+			name: "json reads files",
+			curl: `curl --json @payload.json https://example.com`,
+			verify: func(t *testing.T, req *models.PreparedRequest) {
+				if !bytes.Equal(req.Body, files["payload.json"]) {
+					t.Errorf("Body = %q", req.Body)
+				}
+			},
+		},
+		{
+			name: "data-urlencode reads files",
+			curl: `curl --data-urlencode msg@note.txt https://example.com`,
+			verify: func(t *testing.T, req *models.PreparedRequest) {
+				if string(req.Body) != "msg=hello%20world" {
+					t.Errorf("Body = %q", req.Body)
+				}
+			},
+		},
+		{
+			name: "form file upload and file field",
+			curl: `curl -F 'image=@upload.png;type=image/x-test;filename=logo.png' -F 'doc=@note.txt' -F 'text=<note.txt' https://example.com`,
+			verify: func(t *testing.T, req *models.PreparedRequest) {
+				_, params, err := mime.ParseMediaType(req.Headers["Content-Type"])
+				if err != nil {
+					t.Fatalf("Content-Type = %q", req.Headers["Content-Type"])
+				}
+				form, err := multipart.NewReader(bytes.NewReader(req.Body), params["boundary"]).ReadForm(1 << 20)
+				if err != nil {
+					t.Fatalf("Invalid multipart body: %v", err)
+				}
+
+				image := form.File["image"][0]
+				if image.Filename != "logo.png" || image.Header.Get("Content-Type") != "image/x-test" {
+					t.Errorf("image part = %q, %q", image.Filename, image.Header.Get("Content-Type"))
+				}
+				f, err := image.Open()
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer func() { _ = f.Close() }()
+				content, _ := io.ReadAll(f)
+				if !bytes.Equal(content, binary) {
+					t.Errorf("image content = %v, want %v", content, binary)
+				}
+
+				doc := form.File["doc"][0]
+				if doc.Filename != "note.txt" || !strings.HasPrefix(doc.Header.Get("Content-Type"), "text/plain") {
+					t.Errorf("doc part = %q, %q", doc.Filename, doc.Header.Get("Content-Type"))
+				}
+
+				if form.Value["text"][0] != "hello world" {
+					t.Errorf("text field = %q", form.Value["text"])
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := parser.ParseCurlRelativeTo(tt.curl, dir)
+			if err != nil {
+				t.Fatalf("ParseCurlRelativeTo failed: %v", err)
+			}
+			tt.verify(t, req)
+		})
+	}
+}
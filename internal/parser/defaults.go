@@ -62,10 +62,44 @@ func MergeDefaults(test *models.Test, defaults models.DefaultConfig) {
 		test.MaxRedirects = &redirects
 	}
 
+	// Fill in TLS settings the test doesn't set from the defaults
+	if defaults.TLS != nil {
+		test.TLS = test.TLS.Merge(defaults.TLS)
+	}
+
+	// Apply proxy if not set on test
+	if test.Proxy == nil && defaults.Proxy != nil {
+		proxy := *defaults.Proxy
+		proxy.NoProxy = append([]string(nil), defaults.Proxy.NoProxy...)
+		test.Proxy = &proxy
+	}
+
+	// Merge host resolution overrides, test entries win
+	if len(defaults.Resolve) > 0 {
+		resolve := make(map[string]string, len(defaults.Resolve)+len(test.Resolve))
+		for host, addr := range defaults.Resolve {
+			resolve[host] = addr
+		}
+		for host, addr := range test.Resolve {
+			resolve[host] = addr
+		}
+		test.Resolve = resolve
+	}
+
+	// Apply unix_socket if not set on test
+	if test.UnixSocket == "" && defaults.UnixSocket != "" {
+		test.UnixSocket = defaults.UnixSocket
+	}
+
 	// Merge headers for structured requests
 	if test.Request != nil && len(defaults.Headers) > 0 {
 		mergeHeaders(test.Request, defaults.Headers)
 	}
+
+	// Merge query parameters for structured requests
+	if test.Request != nil && len(defaults.Query) > 0 {
+		mergeQuery(test.Request, defaults.Query)
+	}
 }
 
 // mergeHeaders merges default headers into request headers
@@ -83,9 +117,29 @@ func mergeHeaders(request *models.StructuredRequest, defaultHeaders map[string]s
 	}
 }
 
-// ApplyDefaults applies defaults to all tests in a suite
+// mergeQuery merges default query parameters into request query parameters
+// A parameter set on the request replaces all default values for that name
+func mergeQuery(request *models.StructuredRequest, defaultQuery models.QueryParams) {
+	if request.Query == nil {
+		request.Query = make(models.QueryParams)
+	}
+
+	for name, values := range defaultQuery {
+		if _, exists := request.Query[name]; !exists {
+			request.Query[name] = append([]string(nil), values...)
+		}
+	}
+}
+
+// ApplyDefaults applies defaults to all tests in a suite, including setup and teardown
 func ApplyDefaults(suite *models.TestSuite) {
+	for i := range suite.Setup {
+		MergeDefaults(&suite.Setup[i], suite.Defaults)
+	}
 	for i := range suite.Tests {
 		MergeDefaults(&suite.Tests[i], suite.Defaults)
 	}
+	for i := range suite.Teardown {
+		MergeDefaults(&suite.Teardown[i], suite.Defaults)
+	}
 }
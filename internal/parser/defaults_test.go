@@ -221,3 +221,134 @@ func TestApplyDefaults(t *testing.T) {
 		t.Errorf("Test 3: Expected timeout 60s (override), got %v", suite.Tests[2].Timeout)
 	}
 }
+
+func TestMergeDefaults_Query(t *testing.T) {
+	defaults := models.DefaultConfig{
+		Query: models.QueryParams{
+			"api_key": {"default-key"},
+			"lang":    {"en", "fr"},
+		},
+	}
+
+	test := &models.Test{
+		Name: "Test with query",
+		Request: &models.StructuredRequest{
+			Method: "GET",
+			URL:    "https://example.com",
+			Query:  models.QueryParams{"lang": {"de"}},
+		},
+	}
+
+	MergeDefaults(test, defaults)
+
+	if got := test.Request.Query["api_key"]; len(got) != 1 || got[0] != "default-key" {
+		t.Errorf("Expected default api_key, got %v", got)
+	}
+	// Request values replace all default values for the same name
+	if got := test.Request.Query["lang"]; len(got) != 1 || got[0] != "de" {
+		t.Errorf("Expected lang [de], got %v", got)
+	}
+
+	// Defaults must not be shared with the test
+	test.Request.Query["api_key"][0] = "changed"
+	if defaults.Query["api_key"][0] != "default-key" {
+		t.Error("Default query values were modified through the test")
+	}
+}
+
+func TestMergeDefaults_TLS(t *testing.T) {
+	defaults := models.DefaultConfig{
+		TLS: &models.TLSConfig{
+			CAFile:     "certs/ca.pem",
+			CertFile:   "certs/client.pem",
+			KeyFile:    "certs/client-key.pem",
+			MinVersion: "1.2",
+		},
+	}
+
+	test := &models.Test{
+		Name: "Test with TLS override",
+		TLS: &models.TLSConfig{
+			CertFile:   "certs/other.pem",
+			ServerName: "api.internal",
+		},
+	}
+
+	MergeDefaults(test, defaults)
+
+	if test.TLS.CAFile != "certs/ca.pem" || test.TLS.MinVersion != "1.2" {
+		t.Errorf("Expected default CA file and min version, got %+v", test.TLS)
+	}
+	// The certificate and key are taken from the defaults only as a pair
+	if test.TLS.CertFile != "certs/other.pem" || test.TLS.KeyFile != "" {
+		t.Errorf("Expected test certificate without the default key, got %+v", test.TLS)
+	}
+	if test.TLS.ServerName != "api.internal" {
+		t.Errorf("Expected server name api.internal, got %q", test.TLS.ServerName)
+	}
+
+	// Defaults must not be shared with the test
+	test.TLS.CAFile = "changed"
+	if defaults.TLS.CAFile != "certs/ca.pem" {
+		t.Error("Default TLS settings were modified through the test")
+	}
+}
+
+func TestMergeDefaults_Proxy(t *testing.T) {
+	defaults := models.DefaultConfig{
+		Proxy: &models.ProxyConfig{URL: "http://proxy.corp:3128", NoProxy: []string{"localhost"}},
+	}
+
+	test := &models.Test{Name: "Test without proxy"}
+	MergeDefaults(test, defaults)
+
+	if test.Proxy == nil || test.Proxy.URL != "http://proxy.corp:3128" {
+		t.Fatalf("Expected default proxy, got %+v", test.Proxy)
+	}
+
+	// Defaults must not be shared with the test
+	test.Proxy.NoProxy[0] = "changed"
+	if defaults.Proxy.NoProxy[0] != "localhost" {
+		t.Error("Default proxy settings were modified through the test")
+	}
+
+	override := &models.Test{
+		Name:  "Test with proxy",
+		Proxy: &models.ProxyConfig{URL: "socks5://other:1080"},
+	}
+	MergeDefaults(override, defaults)
+
+	if override.Proxy.URL != "socks5://other:1080" || override.Proxy.NoProxy != nil {
+		t.Errorf("Expected test proxy to replace the default, got %+v", override.Proxy)
+	}
+}
+
+func TestMergeDefaults_Resolve(t *testing.T) {
+	defaults := models.DefaultConfig{
+		Resolve:    map[string]string{"api.example.com:443": "10.0.0.1", "cdn.example.com": "10.0.0.2"},
+		UnixSocket: "/var/run/app.sock",
+	}
+
+	test := &models.Test{
+		Name:    "Test with resolve",
+		Resolve: map[string]string{"api.example.com:443": "10.0.0.9"},
+	}
+
+	MergeDefaults(test, defaults)
+
+	if test.Resolve["api.example.com:443"] != "10.0.0.9" {
+		t.Errorf("Expected test entry to win, got %q", test.Resolve["api.example.com:443"])
+	}
+	if test.Resolve["cdn.example.com"] != "10.0.0.2" {
+		t.Errorf("Expected default entry, got %q", test.Resolve["cdn.example.com"])
+	}
+	if test.UnixSocket != "/var/run/app.sock" {
+		t.Errorf("Expected default unix socket, got %q", test.UnixSocket)
+	}
+
+	// Defaults must not be shared with the test
+	test.Resolve["cdn.example.com"] = "changed"
+	if defaults.Resolve["cdn.example.com"] != "10.0.0.2" {
+		t.Error("Default resolve entries were modified through the test")
+	}
+}
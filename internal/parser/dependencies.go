@@ -0,0 +1,100 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"curlex/internal/models"
+)
+
+// validateDependencies checks depends_on references for unknown names,
+// ambiguous targets and cycles
+func validateDependencies(tests []models.Test) []error {
+	var errs []error
+
+	// Count names so ambiguous dependency targets can be reported
+	nameCount := make(map[string]int, len(tests))
+	for _, test := range tests {
+		nameCount[test.Name]++
+	}
+
+	for _, test := range tests {
+		for _, dep := range test.DependsOn {
+			switch {
+			case dep == test.Name:
+				errs = append(errs, fmt.Errorf("test %s: cannot depend on itself", test.Name))
+			case nameCount[dep] == 0:
+				errs = append(errs, fmt.Errorf("test %s: depends on unknown test %q", test.Name, dep))
+			case nameCount[dep] > 1:
+				errs = append(errs, fmt.Errorf("test %s: dependency %q is ambiguous, %d tests share that name", test.Name, dep, nameCount[dep]))
+			}
+		}
+	}
+
+	// Cycle detection only makes sense once every reference resolves
+	if len(errs) > 0 {
+		return errs
+	}
+
+	if cycle := findDependencyCycle(tests); cycle != nil {
+		errs = append(errs, fmt.Errorf("dependency cycle detected: %s", strings.Join(cycle, " -> ")))
+	}
+
+	return errs
+}
+
+// findDependencyCycle returns the test names forming a cycle, or nil if the
+// dependency graph is acyclic
+func findDependencyCycle(tests []models.Test) []string {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	deps := make(map[string][]string, len(tests))
+	for _, test := range tests {
+		deps[test.Name] = append(deps[test.Name], test.DependsOn...)
+	}
+
+	state := make(map[string]int, len(tests))
+	var stack []string
+
+	var visit func(name string) []string
+	visit = func(name string) []string {
+		state[name] = visiting
+		stack = append(stack, name)
+
+		for _, dep := range deps[name] {
+			switch state[dep] {
+			case visiting:
+				// Slice the stack from the first occurrence of dep to report the loop
+				for i, n := range stack {
+					if n == dep {
+						cycle := append([]string{}, stack[i:]...)
+						return append(cycle, dep)
+					}
+				}
+			case unvisited:
+				if cycle := visit(dep); cycle != nil {
+					return cycle
+				}
+			}
+		}
+
+		stack = stack[:len(stack)-1]
+		state[name] = visited
+		return nil
+	}
+
+	// Visit in file order so the reported cycle is deterministic
+	for _, test := range tests {
+		if state[test.Name] == unvisited {
+			if cycle := visit(test.Name); cycle != nil {
+				return cycle
+			}
+		}
+	}
+
+	return nil
+}
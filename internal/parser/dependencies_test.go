@@ -0,0 +1,77 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"curlex/internal/models"
+)
+
+func TestValidateDependencies(t *testing.T) {
+	tests := []struct {
+		name        string
+		tests       []models.Test
+		errContains string
+	}{
+		{
+			name: "valid chain",
+			tests: []models.Test{
+				{Name: "create"},
+				{Name: "read", DependsOn: []string{"create"}},
+				{Name: "delete", DependsOn: []string{"create", "read"}},
+			},
+		},
+		{
+			name: "unknown dependency",
+			tests: []models.Test{
+				{Name: "read", DependsOn: []string{"create"}},
+			},
+			errContains: `depends on unknown test "create"`,
+		},
+		{
+			name: "self dependency",
+			tests: []models.Test{
+				{Name: "read", DependsOn: []string{"read"}},
+			},
+			errContains: "cannot depend on itself",
+		},
+		{
+			name: "ambiguous dependency",
+			tests: []models.Test{
+				{Name: "create"},
+				{Name: "create"},
+				{Name: "read", DependsOn: []string{"create"}},
+			},
+			errContains: "ambiguous",
+		},
+		{
+			name: "cycle",
+			tests: []models.Test{
+				{Name: "a", DependsOn: []string{"c"}},
+				{Name: "b", DependsOn: []string{"a"}},
+				{Name: "c", DependsOn: []string{"b"}},
+			},
+			errContains: "dependency cycle detected: a -> c -> b -> a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validateDependencies(tt.tests)
+
+			if tt.errContains == "" {
+				if len(errs) != 0 {
+					t.Errorf("Unexpected errors: %v", errs)
+				}
+				return
+			}
+
+			if len(errs) == 0 {
+				t.Fatalf("Expected error containing %q", tt.errContains)
+			}
+			if !strings.Contains(errs[0].Error(), tt.errContains) {
+				t.Errorf("Error = %q, want it to contain %q", errs[0].Error(), tt.errContains)
+			}
+		})
+	}
+}
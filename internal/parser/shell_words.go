@@ -0,0 +1,238 @@
+package parser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// splitShellWords splits a command line into words following POSIX shell
+// quoting rules, so commands pasted from a terminal or from browser devtools
+// ("Copy as cURL") yield the same arguments the shell would pass to curl
+// Supported: single and double quotes, backslash escapes, backslash-newline
+// continuations and ANSI-C $'...' strings. Variables and globs are not expanded
+func splitShellWords(s string) ([]string, error) {
+	var (
+		words  []string
+		word   strings.Builder
+		inWord bool // distinguishes an empty quoted word from no word
+		runes  = []rune(s)
+		flush  = func() {
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		}
+	)
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		switch {
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			flush()
+
+		case r == '\\':
+			// Backslash-newline is a line continuation and produces nothing
+			if i+1 < len(runes) && runes[i+1] == '\n' {
+				i++
+				continue
+			}
+			if i+2 < len(runes) && runes[i+1] == '\r' && runes[i+2] == '\n' {
+				i += 2
+				continue
+			}
+			if i+1 < len(runes) {
+				i++
+				word.WriteRune(runes[i])
+			}
+			inWord = true
+
+		case r == '\'':
+			end := indexRune(runes, i+1, '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated single quote")
+			}
+			word.WriteString(string(runes[i+1 : end]))
+			inWord = true
+			i = end
+
+		case r == '"':
+			next, err := readDoubleQuoted(runes, i+1, &word)
+			if err != nil {
+				return nil, err
+			}
+			inWord = true
+			i = next
+
+		case r == '$' && i+1 < len(runes) && runes[i+1] == '\'':
+			next, err := readANSIQuoted(runes, i+2, &word)
+			if err != nil {
+				return nil, err
+			}
+			inWord = true
+			i = next
+
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+
+	flush()
+	return words, nil
+}
+
+// indexRune returns the index of the first r at or after start, or -1
+func indexRune(runes []rune, start int, r rune) int {
+	for i := start; i < len(runes); i++ {
+		if runes[i] == r {
+			return i
+		}
+	}
+	return -1
+}
+
+// readDoubleQuoted reads a double-quoted string starting after the opening
+// quote and returns the index of the closing quote
+// Inside double quotes a backslash only escapes $, `, ", \ and newline
+func readDoubleQuoted(runes []rune, start int, word *strings.Builder) (int, error) {
+	for i := start; i < len(runes); i++ {
+		r := runes[i]
+		switch r {
+		case '"':
+			return i, nil
+		case '\\':
+			if i+1 < len(runes) {
+				switch runes[i+1] {
+				case '$', '`', '"', '\\':
+					i++
+					word.WriteRune(runes[i])
+					continue
+				case '\n':
+					i++
+					continue
+				}
+			}
+			word.WriteRune(r)
+		default:
+			word.WriteRune(r)
+		}
+	}
+	return 0, fmt.Errorf("unterminated double quote")
+}
+
+// readANSIQuoted reads a $'...' string starting after the opening quote and
+// returns the index of the closing quote, decoding C-style escapes
+func readANSIQuoted(runes []rune, start int, word *strings.Builder) (int, error) {
+	for i := start; i < len(runes); i++ {
+		r := runes[i]
+		if r == '\'' {
+			return i, nil
+		}
+		if r != '\\' || i+1 >= len(runes) {
+			word.WriteRune(r)
+			continue
+		}
+
+		i++
+		switch esc := runes[i]; esc {
+		case 'n':
+			word.WriteByte('\n')
+		case 't':
+			word.WriteByte('\t')
+		case 'r':
+			word.WriteByte('\r')
+		case 'a':
+			word.WriteByte('\a')
+		case 'b':
+			word.WriteByte('\b')
+		case 'e', 'E':
+			word.WriteByte(0x1b)
+		case 'f':
+			word.WriteByte('\f')
+		case 'v':
+			word.WriteByte('\v')
+		case '\\', '\'', '"', '?':
+			word.WriteRune(esc)
+		case 'x':
+			// \xHH: one or two hex digits
+			digits := readDigits(runes, i+1, 2, isHexDigit)
+			if digits == "" {
+				word.WriteString(`\x`)
+				continue
+			}
+			value, _ := strconv.ParseUint(digits, 16, 8)
+			word.WriteByte(byte(value))
+			i += len(digits)
+		case 'u', 'U':
+			// \uHHHH and \UHHHHHHHH: Unicode code points
+			size := 4
+			if esc == 'U' {
+				size = 8
+			}
+			digits := readDigits(runes, i+1, size, isHexDigit)
+			if digits == "" {
+				word.WriteRune('\\')
+				word.WriteRune(esc)
+				continue
+			}
+			value, _ := strconv.ParseUint(digits, 16, 32)
+			if value > utf8.MaxRune {
+				value = utf8.RuneError
+			}
+			word.WriteRune(rune(value))
+			i += len(digits)
+		case '0', '1', '2', '3', '4', '5', '6', '7':
+			// \nnn: up to three octal digits
+			digits := readDigits(runes, i, 3, isOctalDigit)
+			value, _ := strconv.ParseUint(digits, 8, 16)
+			word.WriteByte(byte(value))
+			i += len(digits) - 1
+		default:
+			// Unknown escapes are kept verbatim, as bash does
+			word.WriteRune('\\')
+			word.WriteRune(esc)
+		}
+	}
+	return 0, fmt.Errorf("unterminated $' quote")
+}
+
+// readDigits returns up to max consecutive digits starting at start
+func readDigits(runes []rune, start, max int, valid func(rune) bool) string {
+	end := start
+	for end < len(runes) && end-start < max && valid(runes[end]) {
+		end++
+	}
+	return string(runes[start:end])
+}
+
+// isHexDigit reports whether r is a hexadecimal digit
+func isHexDigit(r rune) bool {
+	return (r >= '0' && r <= '9') || (r >= 'a' && r <= 'f') || (r >= 'A' && r <= 'F')
+}
+
+// isOctalDigit reports whether r is an octal digit
+func isOctalDigit(r rune) bool {
+	return r >= '0' && r <= '7'
+}
+
+// shellQuote quotes a word so splitShellWords reads it back unchanged
+// Words made only of characters without special meaning are left bare
+func shellQuote(word string) string {
+	if word != "" && !strings.ContainsFunc(word, needsShellQuote) {
+		return word
+	}
+	return "'" + strings.ReplaceAll(word, "'", `'\''`) + "'"
+}
+
+// needsShellQuote reports whether r must be quoted in a shell word
+func needsShellQuote(r rune) bool {
+	switch {
+	case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		return false
+	}
+	return !strings.ContainsRune("-_./:=@%+,", r)
+}
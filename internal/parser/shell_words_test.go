@@ -0,0 +1,104 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitShellWords(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expected    []string
+		shouldError bool
+	}{
+		{
+			name:     "plain words",
+			input:    "curl -X GET https://example.com",
+			expected: []string{"curl", "-X", "GET", "https://example.com"},
+		},
+		{
+			name:     "double quotes keep single quotes",
+			input:    `-H "X-Name: O'Brien"`,
+			expected: []string{"-H", "X-Name: O'Brien"},
+		},
+		{
+			name:     "single quotes keep double quotes",
+			input:    `-d '{"key": "value"}'`,
+			expected: []string{"-d", `{"key": "value"}`},
+		},
+		{
+			name:     "escaped quotes in double quotes",
+			input:    `-d "{\"key\":\"value\"}"`,
+			expected: []string{"-d", `{"key":"value"}`},
+		},
+		{
+			name:     "backslash kept before other characters in double quotes",
+			input:    `"a\nb"`,
+			expected: []string{`a\nb`},
+		},
+		{
+			name:     "escaped space outside quotes",
+			input:    `a\ b c`,
+			expected: []string{"a b", "c"},
+		},
+		{
+			name:     "line continuations",
+			input:    "curl 'https://example.com' \\\n  -H 'Accept: */*' \\\r\n  --compressed",
+			expected: []string{"curl", "https://example.com", "-H", "Accept: */*", "--compressed"},
+		},
+		{
+			name:     "adjacent quoted parts form one word",
+			input:    `'it'\''s'"-"done`,
+			expected: []string{"it's-done"},
+		},
+		{
+			name:     "empty quoted word",
+			input:    `-d ''`,
+			expected: []string{"-d", ""},
+		},
+		{
+			name:     "ansi-c quoting",
+			input:    `$'line1\nline2\t\'q\' \x41\101é'`,
+			expected: []string{"line1\nline2\t'q' AAé"},
+		},
+		{
+			name:     "dollar without quote is literal",
+			input:    `${token} $HOME`,
+			expected: []string{"${token}", "$HOME"},
+		},
+		{
+			name:        "unterminated single quote",
+			input:       `-d 'oops`,
+			shouldError: true,
+		},
+		{
+			name:        "unterminated double quote",
+			input:       `-d "oops`,
+			shouldError: true,
+		},
+		{
+			name:        "unterminated ansi-c quote",
+			input:       `$'oops`,
+			shouldError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			words, err := splitShellWords(tt.input)
+			if tt.shouldError {
+				if err == nil {
+					t.Fatalf("Expected error, got words %q", words)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(words, tt.expected) {
+				t.Errorf("splitShellWords() = %q, want %q", words, tt.expected)
+			}
+		})
+	}
+}
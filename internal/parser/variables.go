@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"regexp"
+	"strings"
+	"sync"
 
 	"curlex/internal/models"
 )
@@ -12,7 +14,9 @@ import (
 var variablePattern = regexp.MustCompile(`\$\{([^}]+)\}`)
 
 // VariableExpander handles variable substitution in test suites
+// It is safe for concurrent use so captured values can be set from parallel workers
 type VariableExpander struct {
+	mu        sync.RWMutex
 	variables map[string]string
 }
 
@@ -25,55 +29,132 @@ func NewVariableExpander() *VariableExpander {
 
 // ExpandVariables substitutes ${VAR_NAME} references in the test suite
 func (ve *VariableExpander) ExpandVariables(suite *models.TestSuite) error {
-	// Build variable map: test-level vars + environment vars
-	ve.variables = make(map[string]string)
+	ve.SetVariables(suite.Variables)
 
-	// Add test-level variables first
-	if suite.Variables != nil {
-		for key, value := range suite.Variables {
-			ve.variables[key] = value
+	// Expand variables in tests
+	for i := range suite.Tests {
+		if err := ve.expandTest(&suite.Tests[i]); err != nil {
+			return fmt.Errorf("test %s: %w", suite.Tests[i].Name, err)
 		}
 	}
 
+	return nil
+}
+
+// SetVariables resets the variable map to the given suite-level variables
+// Environment references inside variable values are expanded immediately
+func (ve *VariableExpander) SetVariables(variables map[string]string) {
+	ve.mu.Lock()
+	defer ve.mu.Unlock()
+
+	// Build variable map: test-level vars + environment vars
+	ve.variables = make(map[string]string, len(variables))
+	for key, value := range variables {
+		ve.variables[key] = value
+	}
+
 	// Expand environment variables in test-level variables
 	for key, value := range ve.variables {
-		ve.variables[key] = ve.expandString(value)
+		ve.variables[key] = ve.expandStringLocked(value)
 	}
+}
 
-	// Expand variables in tests
-	for i := range suite.Tests {
-		if err := ve.expandTest(&suite.Tests[i]); err != nil {
-			return fmt.Errorf("test %s: %w", suite.Tests[i].Name, err)
+// Set stores a single variable, overriding any existing value
+// Used to make captured response values visible to later tests
+func (ve *VariableExpander) Set(name, value string) {
+	ve.mu.Lock()
+	defer ve.mu.Unlock()
+	ve.variables[name] = value
+}
+
+// ExpandTest returns a copy of the test with variables expanded
+// The original test is left untouched so it can be expanded again later
+func (ve *VariableExpander) ExpandTest(test models.Test) (models.Test, error) {
+	expanded := test
+
+	if test.Request != nil {
+		request := *test.Request
+		if request.Multipart != nil {
+			multipartBody := *request.Multipart
+			multipartBody.Files = append([]models.MultipartFile(nil), request.Multipart.Files...)
+			request.Multipart = &multipartBody
 		}
+		expanded.Request = &request
 	}
 
-	return nil
+	if test.Proxy != nil {
+		proxy := *test.Proxy
+		expanded.Proxy = &proxy
+	}
+
+	expanded.Assertions = make([]models.Assertion, len(test.Assertions))
+	copy(expanded.Assertions, test.Assertions)
+
+	if err := ve.expandTest(&expanded); err != nil {
+		return test, err
+	}
+
+	return expanded, nil
 }
 
 // expandTest expands variables in a single test
 func (ve *VariableExpander) expandTest(test *models.Test) error {
-	// Expand curl command
+	// Expand curl command word by word
 	if test.Curl != "" {
-		test.Curl = ve.expandString(test.Curl)
+		test.Curl = ve.expandCurl(test.Curl)
 	}
 
 	// Expand structured request
 	if test.Request != nil {
 		test.Request.URL = ve.expandString(test.Request.URL)
 		test.Request.Body = ve.expandString(test.Request.Body)
+		test.Request.BodyFile = ve.expandString(test.Request.BodyFile)
 
 		// Expand headers
 		if test.Request.Headers != nil {
-			expandedHeaders := make(map[string]string)
-			for key, value := range test.Request.Headers {
-				expandedKey := ve.expandString(key)
-				expandedValue := ve.expandString(value)
-				expandedHeaders[expandedKey] = expandedValue
+			test.Request.Headers = ve.expandMap(test.Request.Headers)
+		}
+
+		// Expand query parameters
+		if test.Request.Query != nil {
+			query := make(models.QueryParams, len(test.Request.Query))
+			for name, values := range test.Request.Query {
+				expanded := make([]string, len(values))
+				for i, value := range values {
+					expanded[i] = ve.expandString(value)
+				}
+				query[ve.expandString(name)] = expanded
+			}
+			test.Request.Query = query
+		}
+
+		// Expand form and multipart values
+		if test.Request.Form != nil {
+			test.Request.Form = ve.expandMap(test.Request.Form)
+		}
+		if test.Request.Multipart != nil {
+			if test.Request.Multipart.Fields != nil {
+				test.Request.Multipart.Fields = ve.expandMap(test.Request.Multipart.Fields)
+			}
+			for i, file := range test.Request.Multipart.Files {
+				test.Request.Multipart.Files[i].Path = ve.expandString(file.Path)
+				test.Request.Multipart.Files[i].Filename = ve.expandString(file.Filename)
+				test.Request.Multipart.Files[i].ContentType = ve.expandString(file.ContentType)
 			}
-			test.Request.Headers = expandedHeaders
 		}
 	}
 
+	// Expand proxy URL, which may carry credentials from the environment
+	if test.Proxy != nil {
+		test.Proxy.URL = ve.expandString(test.Proxy.URL)
+	}
+
+	// Expand connection overrides
+	if test.Resolve != nil {
+		test.Resolve = ve.expandMap(test.Resolve)
+	}
+	test.UnixSocket = ve.expandString(test.UnixSocket)
+
 	// Expand assertions
 	for i := range test.Assertions {
 		test.Assertions[i].Value = ve.expandString(test.Assertions[i].Value)
@@ -82,8 +163,42 @@ func (ve *VariableExpander) expandTest(test *models.Test) error {
 	return nil
 }
 
+// expandCurl expands variables within each word of a curl command and
+// quotes the words again, so a value containing quotes, spaces or
+// backslashes, such as a captured token, stays inside its argument
+func (ve *VariableExpander) expandCurl(curl string) string {
+	if !strings.Contains(curl, "${") {
+		return curl
+	}
+	words, err := splitShellWords(curl)
+	if err != nil {
+		// Validation reports the quoting error; expand the text as written
+		return ve.expandString(curl)
+	}
+	for i, word := range words {
+		words[i] = shellQuote(ve.expandString(word))
+	}
+	return strings.Join(words, " ")
+}
+
+// expandMap returns a copy of m with variables expanded in keys and values
+func (ve *VariableExpander) expandMap(m map[string]string) map[string]string {
+	expanded := make(map[string]string, len(m))
+	for key, value := range m {
+		expanded[ve.expandString(key)] = ve.expandString(value)
+	}
+	return expanded
+}
+
 // expandString replaces ${VAR_NAME} with variable values
 func (ve *VariableExpander) expandString(s string) string {
+	ve.mu.RLock()
+	defer ve.mu.RUnlock()
+	return ve.expandStringLocked(s)
+}
+
+// expandStringLocked performs the substitution; callers must hold ve.mu
+func (ve *VariableExpander) expandStringLocked(s string) string {
 	return variablePattern.ReplaceAllStringFunc(s, func(match string) string {
 		// Extract variable name (remove ${ and })
 		varName := match[2 : len(match)-1]
@@ -105,6 +220,9 @@ func (ve *VariableExpander) expandString(s string) string {
 
 // GetVariables returns the current variable map (for debugging)
 func (ve *VariableExpander) GetVariables() map[string]string {
+	ve.mu.RLock()
+	defer ve.mu.RUnlock()
+
 	result := make(map[string]string)
 	for k, v := range ve.variables {
 		result[k] = v
@@ -244,3 +244,147 @@ func TestVariableExpander_ExpandTest(t *testing.T) {
 		})
 	}
 }
+
+func TestVariableExpander_ExpandTestCopy(t *testing.T) {
+	expander := NewVariableExpander()
+	expander.SetVariables(map[string]string{"ID": "1"})
+
+	original := models.Test{
+		Name: "Test",
+		Request: &models.StructuredRequest{
+			Method: "GET",
+			URL:    "https://example.com/users/${ID}",
+		},
+		Assertions: []models.Assertion{{Type: models.AssertionBodyContains, Value: "${ID}"}},
+	}
+
+	expanded, err := expander.ExpandTest(original)
+	if err != nil {
+		t.Fatalf("ExpandTest() error = %v", err)
+	}
+	if expanded.Request.URL != "https://example.com/users/1" {
+		t.Errorf("URL = %v, want https://example.com/users/1", expanded.Request.URL)
+	}
+	if expanded.Assertions[0].Value != "1" {
+		t.Errorf("Assertion value = %v, want 1", expanded.Assertions[0].Value)
+	}
+
+	// Original must be untouched so it can be expanded again with new values
+	if original.Request.URL != "https://example.com/users/${ID}" {
+		t.Errorf("Original URL was modified: %v", original.Request.URL)
+	}
+	if original.Assertions[0].Value != "${ID}" {
+		t.Errorf("Original assertion was modified: %v", original.Assertions[0].Value)
+	}
+
+	// Captured values override suite variables
+	expander.Set("ID", "2")
+	expanded, _ = expander.ExpandTest(original)
+	if expanded.Request.URL != "https://example.com/users/2" {
+		t.Errorf("URL after Set = %v, want https://example.com/users/2", expanded.Request.URL)
+	}
+}
+
+func TestVariableExpander_ExpandTestFormBodies(t *testing.T) {
+	expander := NewVariableExpander()
+	expander.SetVariables(map[string]string{"USER": "ada", "DIR": "fixtures"})
+
+	original := models.Test{
+		Name: "Upload",
+		Request: &models.StructuredRequest{
+			Method: "POST",
+			URL:    "https://example.com/upload",
+			Form:   map[string]string{"user": "${USER}"},
+			Multipart: &models.MultipartBody{
+				Fields: map[string]string{"owner": "${USER}"},
+				Files:  []models.MultipartFile{{Name: "avatar", Path: "${DIR}/avatar.png", Filename: "${USER}.png"}},
+			},
+		},
+	}
+
+	expanded, err := expander.ExpandTest(original)
+	if err != nil {
+		t.Fatalf("ExpandTest() error = %v", err)
+	}
+
+	if expanded.Request.Form["user"] != "ada" {
+		t.Errorf("Form = %v", expanded.Request.Form)
+	}
+	if expanded.Request.Multipart.Fields["owner"] != "ada" {
+		t.Errorf("Multipart fields = %v", expanded.Request.Multipart.Fields)
+	}
+	file := expanded.Request.Multipart.Files[0]
+	if file.Path != "fixtures/avatar.png" || file.Filename != "ada.png" {
+		t.Errorf("Multipart file = %+v", file)
+	}
+
+	// Original must be untouched
+	if original.Request.Form["user"] != "${USER}" || original.Request.Multipart.Fields["owner"] != "${USER}" {
+		t.Error("Original form values were modified")
+	}
+	if original.Request.Multipart.Files[0].Path != "${DIR}/avatar.png" {
+		t.Errorf("Original multipart file was modified: %+v", original.Request.Multipart.Files[0])
+	}
+}
+
+func TestVariableExpander_ExpandTestQuery(t *testing.T) {
+	expander := NewVariableExpander()
+	expander.SetVariables(map[string]string{"TERM": "a&b c", "KEY": "q"})
+
+	original := models.Test{
+		Name: "Search",
+		Request: &models.StructuredRequest{
+			Method: "GET",
+			URL:    "https://example.com/search",
+			Query:  models.QueryParams{"${KEY}": {"${TERM}"}, "tag": {"x", "${KEY}"}},
+		},
+	}
+
+	expanded, err := expander.ExpandTest(original)
+	if err != nil {
+		t.Fatalf("ExpandTest() error = %v", err)
+	}
+
+	if got := expanded.Request.Query["q"]; len(got) != 1 || got[0] != "a&b c" {
+		t.Errorf("Query[q] = %v", got)
+	}
+	if got := expanded.Request.Query["tag"]; len(got) != 2 || got[1] != "q" {
+		t.Errorf("Query[tag] = %v", got)
+	}
+	if original.Request.Query["tag"][1] != "${KEY}" {
+		t.Error("Original query values were modified")
+	}
+}
+
+func TestVariableExpander_ExpandTestCurlQuoting(t *testing.T) {
+	expander := NewVariableExpander()
+	expander.SetVariables(map[string]string{"BASE_URL": "https://example.com"})
+	expander.Set("token", `it's a "tok en" \x`)
+
+	expanded, err := expander.ExpandTest(models.Test{
+		Name: "Quoted capture",
+		Curl: `curl -H 'Authorization: Bearer ${token}' -d "t=${token}" ${BASE_URL}/users`,
+	})
+	if err != nil {
+		t.Fatalf("ExpandTest() error = %v", err)
+	}
+
+	words, err := splitShellWords(expanded.Curl)
+	if err != nil {
+		t.Fatalf("splitShellWords(%q) error = %v", expanded.Curl, err)
+	}
+	want := []string{
+		"curl",
+		"-H", `Authorization: Bearer it's a "tok en" \x`,
+		"-d", `t=it's a "tok en" \x`,
+		"https://example.com/users",
+	}
+	if len(words) != len(want) {
+		t.Fatalf("words = %q, want %q", words, want)
+	}
+	for i := range want {
+		if words[i] != want[i] {
+			t.Errorf("word %d = %q, want %q", i, words[i], want[i])
+		}
+	}
+}
@@ -1,25 +1,49 @@
 package parser
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
 	"os"
+	"path/filepath"
+	"regexp"
 	"strconv"
+	"strings"
 
 	"curlex/internal/models"
+	"github.com/antchfx/xpath"
 	"gopkg.in/yaml.v3"
 )
 
 // YAMLParser parses test suite YAML files
-type YAMLParser struct{}
+type YAMLParser struct {
+	curlParser *CurlParser
+	strict     bool     // Reject curl commands using unsupported flags
+	warnings   []string // Non-fatal issues found by the last Parse
+}
 
 // NewYAMLParser creates a new YAML parser instance
 func NewYAMLParser() *YAMLParser {
-	return &YAMLParser{}
+	return &YAMLParser{
+		curlParser: NewCurlParser(),
+	}
+}
+
+// SetStrict makes unsupported curl flags a validation error instead of a warning
+func (p *YAMLParser) SetStrict(strict bool) {
+	p.strict = strict
+}
+
+// Warnings returns non-fatal issues found by the last Parse
+func (p *YAMLParser) Warnings() []string {
+	return p.warnings
 }
 
 // Parse reads a YAML file and returns a test suite
 func (p *YAMLParser) Parse(yamlPath string) (*models.TestSuite, error) {
+	p.warnings = nil
+
 	// Read file
 	data, err := os.ReadFile(yamlPath)
 	if err != nil {
@@ -32,15 +56,21 @@ func (p *YAMLParser) Parse(yamlPath string) (*models.TestSuite, error) {
 		return nil, fmt.Errorf("failed to parse YAML: %w", err)
 	}
 
-	// Expand variables
-	expander := NewVariableExpander()
-	if err := expander.ExpandVariables(&suite); err != nil {
-		return nil, fmt.Errorf("variable expansion failed: %w", err)
-	}
+	// Variables are expanded at execution time by the runner so that
+	// values captured from earlier responses are visible to later tests
 
 	// Apply defaults to all tests
 	ApplyDefaults(&suite)
 
+	// Relative file paths in tests resolve against the test file's directory
+	setBaseDir(&suite, filepath.Dir(yamlPath))
+	// XPath expressions in tests resolve prefixes with the suite's namespaces
+	setNamespaces(&suite)
+	suite.Path = yamlPath
+	if suite.OpenAPI != "" {
+		suite.OpenAPI = ResolvePath(filepath.Dir(yamlPath), suite.OpenAPI)
+	}
+
 	// Validate suite
 	if err := p.validate(&suite); err != nil {
 		return nil, fmt.Errorf("validation failed: %w", err)
@@ -58,44 +88,334 @@ func (p *YAMLParser) validate(suite *models.TestSuite) error {
 	}
 
 	for i, test := range suite.Tests {
-		// Test must have a name
-		if test.Name == "" {
-			errs = append(errs, fmt.Errorf("test %d: name is required", i))
+		errs = append(errs, p.validateTest("test", i, test, true)...)
+	}
+
+	// Setup and teardown tests share the test schema but run strictly in
+	// order, and fixtures such as cleanup requests may omit assertions
+	phases := []struct {
+		name  string
+		tests []models.Test
+	}{
+		{"setup", suite.Setup},
+		{"teardown", suite.Teardown},
+	}
+	for _, phase := range phases {
+		for i, test := range phase.tests {
+			errs = append(errs, p.validateTest(phase.name+" test", i, test, false)...)
+			if len(test.DependsOn) > 0 {
+				errs = append(errs, fmt.Errorf("%s test %s: depends_on is not supported, %s tests run in order", phase.name, test.Name, phase.name))
+			}
+		}
+	}
+
+	if suite.Cookies != "" && suite.Cookies != models.CookiesJar {
+		errs = append(errs, fmt.Errorf("invalid cookies mode %q: expected %q", suite.Cookies, models.CookiesJar))
+	}
+
+	for prefix, uri := range suite.Namespaces {
+		if prefix == "" || strings.ContainsAny(prefix, ": ") || uri == "" {
+			errs = append(errs, fmt.Errorf("invalid namespace %q: %q", prefix, uri))
+		}
+	}
+
+	if suite.OpenAPI != "" {
+		if _, err := os.Stat(suite.OpenAPI); err != nil {
+			errs = append(errs, fmt.Errorf("openapi document not found: %s", suite.OpenAPI))
+		}
+	}
+
+	// Validate depends_on references and reject cycles
+	errs = append(errs, validateDependencies(suite.Tests)...)
+
+	// Each snapshot needs a file of its own
+	errs = append(errs, validateSnapshotNames(suite)...)
+
+	return errors.Join(errs...)
+}
+
+// validateTest performs validation on a single test definition
+// label identifies the test's section in error messages
+func (p *YAMLParser) validateTest(label string, i int, test models.Test, requireAssertions bool) []error {
+	var errs []error
+
+	// Test must have a name
+	if test.Name == "" {
+		errs = append(errs, fmt.Errorf("%s %d: name is required", label, i))
+	}
+
+	// Test must have either curl or request
+	if test.Curl == "" && test.Request == nil {
+		testID := test.Name
+		if testID == "" {
+			testID = strconv.Itoa(i)
+		}
+		errs = append(errs, fmt.Errorf("%s %s: must specify either 'curl' or 'request'", label, testID))
+	}
+
+	// Test cannot have both curl and request
+	if test.Curl != "" && test.Request != nil {
+		errs = append(errs, fmt.Errorf("%s %s: cannot specify both 'curl' and 'request'", label, test.Name))
+	}
+
+	// Test must have at least one assertion
+	if requireAssertions && len(test.Assertions) == 0 {
+		testID := test.Name
+		if testID == "" {
+			testID = strconv.Itoa(i)
+		}
+		errs = append(errs, fmt.Errorf("%s %s: must have at least one assertion", label, testID))
+	}
+
+	// Check curl commands for quoting errors and unsupported flags
+	if test.Curl != "" {
+		errs = append(errs, p.validateCurl(label, test)...)
+	}
+
+	// Validate structured request if present
+	if test.Request != nil {
+		if test.Request.URL == "" {
+			errs = append(errs, fmt.Errorf("%s %s: request.url is required", label, test.Name))
+		}
+		if test.Request.Method == "" {
+			errs = append(errs, fmt.Errorf("%s %s: request.method is required", label, test.Name))
+		}
+		errs = append(errs, validateRequestBody(label, test)...)
+	}
+
+	// Validate TLS settings
+	if test.TLS != nil {
+		if _, err := test.TLS.MinTLSVersion(); err != nil {
+			errs = append(errs, fmt.Errorf("%s %s: %w", label, test.Name, err))
+		}
+		if test.TLS.KeyFile != "" && test.TLS.CertFile == "" {
+			errs = append(errs, fmt.Errorf("%s %s: tls.key_file requires tls.cert_file", label, test.Name))
+		}
+	}
+
+	// Validate the proxy URL unless it is only known at run time
+	if test.Proxy != nil && test.Proxy.URL != "" && !strings.Contains(test.Proxy.URL, "${") {
+		if _, err := models.ParseProxyURL(test.Proxy.URL); err != nil {
+			errs = append(errs, fmt.Errorf("%s %s: %w", label, test.Name, err))
+		}
+	}
+
+	// Validate host resolution overrides unless they are only known at run time
+	for host, addr := range test.Resolve {
+		if strings.Contains(host, "${") || strings.Contains(addr, "${") {
+			continue
+		}
+		if err := validateResolve(host, addr); err != nil {
+			errs = append(errs, fmt.Errorf("%s %s: %w", label, test.Name, err))
+		}
+	}
+
+	// Validate regexes and schema references unless variables are filled in at run time
+	for _, a := range test.Assertions {
+		if strings.Contains(a.Value, "${") {
+			continue
+		}
+		switch a.Type {
+		case models.AssertionBodyMatches:
+			if _, err := regexp.Compile(a.Value); err != nil {
+				errs = append(errs, fmt.Errorf("%s %s: body_matches: invalid regex: %w", label, test.Name, err))
+			}
+		case models.AssertionJSONSchema:
+			if err := validateSchemaRef(test.BaseDir, a.Value); err != nil {
+				errs = append(errs, fmt.Errorf("%s %s: json_schema: %w", label, test.Name, err))
+			}
+		case models.AssertionJSONBody:
+			if err := validateJSONBodyRef(test.BaseDir, a.Value); err != nil {
+				errs = append(errs, fmt.Errorf("%s %s: json_body: %w", label, test.Name, err))
+			}
+		case models.AssertionSnapshot:
+			if err := validateSnapshot(a.Value); err != nil {
+				errs = append(errs, fmt.Errorf("%s %s: %w", label, test.Name, err))
+			}
 		}
+	}
 
-		// Test must have either curl or request
-		if test.Curl == "" && test.Request == nil {
-			testID := test.Name
-			if testID == "" {
-				testID = strconv.Itoa(i)
+	// Validate captures
+	for name, c := range test.Capture {
+		if name == "" || strings.ContainsAny(name, "{}$") {
+			errs = append(errs, fmt.Errorf("%s %s: invalid capture variable name %q", label, test.Name, name))
+		}
+		if c.Source == models.CaptureRegex {
+			if _, err := regexp.Compile(c.Expression); err != nil {
+				errs = append(errs, fmt.Errorf("%s %s: capture %s: invalid regex: %w", label, test.Name, name, err))
+			}
+		}
+		if c.Source == models.CaptureXPath && !strings.Contains(c.Expression, "${") {
+			if _, err := xpath.CompileWithNS(c.Expression, test.Namespaces); err != nil {
+				errs = append(errs, fmt.Errorf("%s %s: capture %s: invalid XPath: %w", label, test.Name, name, err))
 			}
-			errs = append(errs, fmt.Errorf("test %s: must specify either 'curl' or 'request'", testID))
 		}
+	}
+
+	return errs
+}
+
+// validateSchemaRef checks that an inline schema is valid JSON or that a
+// schema file exists; the schema itself is compiled when first used
+func validateSchemaRef(baseDir, value string) error {
+	value = strings.TrimSpace(value)
+	if strings.HasPrefix(value, "{") {
+		if !json.Valid([]byte(value)) {
+			return fmt.Errorf("inline schema is not valid JSON")
+		}
+		return nil
+	}
+	if _, err := os.Stat(ResolvePath(baseDir, value)); err != nil {
+		return fmt.Errorf("schema file not found: %s", value)
+	}
+	return nil
+}
+
+// validateJSONBodyRef checks that an inline expected body is valid JSON or
+// that an expected body file exists
+func validateJSONBodyRef(baseDir, value string) error {
+	body, err := models.ParseJSONBody(value)
+	if err != nil {
+		return err
+	}
+	if body.File == "" {
+		return nil
+	}
+	if _, err := os.Stat(ResolvePath(baseDir, body.File)); err != nil {
+		return fmt.Errorf("expected body file not found: %s", body.File)
+	}
+	return nil
+}
 
-		// Test cannot have both curl and request
-		if test.Curl != "" && test.Request != nil {
-			errs = append(errs, fmt.Errorf("test %s: cannot specify both 'curl' and 'request'", test.Name))
+// validateSnapshot checks a snapshot assertion's options and mask patterns
+func validateSnapshot(value string) error {
+	snapshot, err := models.ParseSnapshot(value)
+	if err != nil {
+		return err
+	}
+	for _, pattern := range snapshot.MaskRegex {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("snapshot: invalid mask_regex: %w", err)
 		}
+	}
+	return nil
+}
 
-		// Test must have at least one assertion
-		if len(test.Assertions) == 0 {
-			testID := test.Name
-			if testID == "" {
-				testID = strconv.Itoa(i)
+// validateSnapshotNames rejects snapshot assertions that would share a file
+func validateSnapshotNames(suite *models.TestSuite) []error {
+	var errs []error
+	owners := make(map[string]string)
+	for _, tests := range [][]models.Test{suite.Setup, suite.Tests, suite.Teardown} {
+		for _, test := range tests {
+			for _, a := range test.Assertions {
+				if a.Type != models.AssertionSnapshot {
+					continue
+				}
+				snapshot, err := models.ParseSnapshot(a.Value)
+				if err != nil {
+					continue // Reported by validateTest
+				}
+				name := models.SnapshotName(test, snapshot)
+				if owner, ok := owners[name]; ok {
+					errs = append(errs, fmt.Errorf("test %s: snapshot %q is also used by test %s; give one a name", test.Name, name, owner))
+					continue
+				}
+				owners[name] = test.Name
 			}
-			errs = append(errs, fmt.Errorf("test %s: must have at least one assertion", testID))
 		}
+	}
+	return errs
+}
+
+// validateCurl checks that a test's curl command can be tokenized and reports
+// flags curlex would ignore, as errors in strict mode and warnings otherwise
+func (p *YAMLParser) validateCurl(label string, test models.Test) []error {
+	unsupported, err := p.curlParser.UnsupportedFlags(test.Curl)
+	if err != nil {
+		return []error{fmt.Errorf("%s %s: %w", label, test.Name, err)}
+	}
+
+	var errs []error
+	for _, flag := range unsupported {
+		if p.strict {
+			errs = append(errs, fmt.Errorf("%s %s: unsupported curl flag %s", label, test.Name, flag))
+		} else {
+			p.warnings = append(p.warnings, fmt.Sprintf("%s %s: unsupported curl flag %s is ignored", label, test.Name, flag))
+		}
+	}
+	return errs
+}
+
+// validateResolve checks a resolve entry mapping "host:port" or "host" to an address
+func validateResolve(host, addr string) error {
+	if addr == "" {
+		return fmt.Errorf("resolve %s: address is required", host)
+	}
+	if strings.Contains(host, ":") {
+		name, port, err := net.SplitHostPort(host)
+		if err != nil || name == "" {
+			return fmt.Errorf("resolve %s: expected host or host:port", host)
+		}
+		if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+			return fmt.Errorf("resolve %s: invalid port %q", host, port)
+		}
+	} else if host == "" {
+		return fmt.Errorf("resolve: host is required")
+	}
+	return nil
+}
+
+// setBaseDir records the test file's directory on every test in the suite
+func setBaseDir(suite *models.TestSuite, dir string) {
+	for _, tests := range [][]models.Test{suite.Setup, suite.Tests, suite.Teardown} {
+		for i := range tests {
+			tests[i].BaseDir = dir
+		}
+	}
+}
+
+// setNamespaces gives every test the suite's XML namespace prefixes
+func setNamespaces(suite *models.TestSuite) {
+	for _, tests := range [][]models.Test{suite.Setup, suite.Tests, suite.Teardown} {
+		for i := range tests {
+			tests[i].Namespaces = suite.Namespaces
+		}
+	}
+}
+
+// validateRequestBody checks that a structured request specifies at most one
+// kind of body and that multipart file parts are complete
+func validateRequestBody(label string, test models.Test) []error {
+	var errs []error
+	request := test.Request
 
-		// Validate structured request if present
-		if test.Request != nil {
-			if test.Request.URL == "" {
-				errs = append(errs, fmt.Errorf("test %s: request.url is required", test.Name))
+	var bodies []string
+	if request.Body != "" {
+		bodies = append(bodies, "'body'")
+	}
+	if request.BodyFile != "" {
+		bodies = append(bodies, "'body_file'")
+	}
+	if len(request.Form) > 0 {
+		bodies = append(bodies, "'form'")
+	}
+	if request.Multipart != nil {
+		bodies = append(bodies, "'multipart'")
+	}
+	if len(bodies) > 1 {
+		errs = append(errs, fmt.Errorf("%s %s: cannot combine %s", label, test.Name, strings.Join(bodies, ", ")))
+	}
+
+	if request.Multipart != nil {
+		for i, file := range request.Multipart.Files {
+			if file.Name == "" {
+				errs = append(errs, fmt.Errorf("%s %s: multipart file %d: name is required", label, test.Name, i))
 			}
-			if test.Request.Method == "" {
-				errs = append(errs, fmt.Errorf("test %s: request.method is required", test.Name))
+			if file.Path == "" {
+				errs = append(errs, fmt.Errorf("%s %s: multipart file %d: path is required", label, test.Name, i))
 			}
 		}
 	}
 
-	return errors.Join(errs...)
+	return errs
 }
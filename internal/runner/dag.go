@@ -0,0 +1,136 @@
+package runner
+
+import (
+	"fmt"
+	"sort"
+
+	"curlex/internal/models"
+)
+
+// dependencyGraph tracks depends_on relationships between the tests of a run
+// Tests depending on a test that is not part of the run (e.g. removed by
+// --skip) are skipped up front, along with their own dependents
+type dependencyGraph struct {
+	tests      []models.Test
+	dependents [][]int  // dependents[i] lists tests waiting on test i
+	remaining  []int    // remaining[i] counts unfinished dependencies of test i
+	done       []bool   // test has finished, been skipped, or been released
+	skipReason []string // reason a test was skipped, empty if not skipped
+	excluded   []int    // tests skipped before the run because a dependency is not part of it
+}
+
+// newDependencyGraph builds the graph for the given tests
+func newDependencyGraph(tests []models.Test) *dependencyGraph {
+	g := &dependencyGraph{
+		tests:      tests,
+		dependents: make([][]int, len(tests)),
+		remaining:  make([]int, len(tests)),
+		done:       make([]bool, len(tests)),
+		skipReason: make([]string, len(tests)),
+	}
+
+	index := make(map[string]int, len(tests))
+	for i, test := range tests {
+		if _, exists := index[test.Name]; !exists {
+			index[test.Name] = i
+		}
+	}
+
+	missing := make([]string, len(tests))
+	for i, test := range tests {
+		for _, dep := range test.DependsOn {
+			j, ok := index[dep]
+			if !ok {
+				if missing[i] == "" {
+					missing[i] = dep
+				}
+				continue
+			}
+			if j == i {
+				continue
+			}
+			g.dependents[j] = append(g.dependents[j], i)
+			g.remaining[i]++
+		}
+	}
+
+	// The parser rejects unknown names, so a missing dependency was filtered out
+	for i, dep := range missing {
+		if dep != "" && !g.done[i] {
+			g.excluded = append(g.excluded, g.skip(i, fmt.Sprintf("dependency %q was skipped", dep))...)
+		}
+	}
+	sort.Ints(g.excluded)
+
+	return g
+}
+
+// ready returns the tests that have no outstanding dependencies, in file order
+func (g *dependencyGraph) ready() []int {
+	var ready []int
+	for i := range g.tests {
+		if !g.done[i] && g.remaining[i] == 0 {
+			g.done[i] = true
+			ready = append(ready, i)
+		}
+	}
+	return ready
+}
+
+// complete records the outcome of test i and returns the tests it released
+// along with any dependents that must now be skipped
+func (g *dependencyGraph) complete(i int, success bool) (ready []int, skipped []int) {
+	for _, d := range g.dependents[i] {
+		if g.done[d] {
+			continue
+		}
+
+		if !success {
+			reason := fmt.Sprintf("dependency %q failed", g.tests[i].Name)
+			if g.skipReason[i] != "" {
+				reason = fmt.Sprintf("dependency %q was skipped", g.tests[i].Name)
+			}
+			skipped = append(skipped, g.skip(d, reason)...)
+			continue
+		}
+
+		g.remaining[d]--
+		if g.remaining[d] == 0 {
+			g.done[d] = true
+			ready = append(ready, d)
+		}
+	}
+
+	return ready, skipped
+}
+
+// skip marks test i and everything depending on it as skipped
+func (g *dependencyGraph) skip(i int, reason string) []int {
+	g.done[i] = true
+	g.skipReason[i] = reason
+	skipped := []int{i}
+
+	_, transitive := g.complete(i, false)
+	return append(skipped, transitive...)
+}
+
+// pending returns the tests that have neither run nor been skipped, in file order
+func (g *dependencyGraph) pending() []int {
+	var pending []int
+	for i := range g.tests {
+		if !g.done[i] {
+			pending = append(pending, i)
+		}
+	}
+	return pending
+}
+
+// skippedResult builds the result reported for a skipped test
+func (g *dependencyGraph) skippedResult(i int) models.TestResult {
+	return models.TestResult{
+		Test:       g.tests[i],
+		Success:    false,
+		Skipped:    true,
+		SkipReason: g.skipReason[i],
+	}
+}
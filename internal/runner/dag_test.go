@@ -0,0 +1,264 @@
+package runner
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"curlex/internal/models"
+)
+
+func TestDependencyGraph_SkipsDependentsOfFailures(t *testing.T) {
+	graph := newDependencyGraph([]models.Test{
+		{Name: "create"},
+		{Name: "read", DependsOn: []string{"create"}},
+		{Name: "delete", DependsOn: []string{"read"}},
+		{Name: "health"},
+	})
+
+	ready := graph.ready()
+	if len(ready) != 2 || ready[0] != 0 || ready[1] != 3 {
+		t.Fatalf("ready() = %v, want [0 3]", ready)
+	}
+
+	released, skipped := graph.complete(0, false)
+	if len(released) != 0 {
+		t.Errorf("Expected no released tests, got %v", released)
+	}
+	if len(skipped) != 2 {
+		t.Fatalf("Expected read and delete to be skipped, got %v", skipped)
+	}
+
+	if reason := graph.skippedResult(1).SkipReason; reason != `dependency "create" failed` {
+		t.Errorf("read skip reason = %q", reason)
+	}
+	if reason := graph.skippedResult(2).SkipReason; reason != `dependency "read" was skipped` {
+		t.Errorf("delete skip reason = %q", reason)
+	}
+}
+
+func TestDependencyGraph_SkipsDependentsOfExcludedTests(t *testing.T) {
+	// "login" was removed by --skip
+	graph := newDependencyGraph([]models.Test{
+		{Name: "profile", DependsOn: []string{"login"}},
+		{Name: "health"},
+		{Name: "logout", DependsOn: []string{"profile"}},
+	})
+
+	if len(graph.excluded) != 2 || graph.excluded[0] != 0 || graph.excluded[1] != 2 {
+		t.Fatalf("excluded = %v, want [0 2]", graph.excluded)
+	}
+	if reason := graph.skippedResult(0).SkipReason; reason != `dependency "login" was skipped` {
+		t.Errorf("profile skip reason = %q", reason)
+	}
+	if reason := graph.skippedResult(2).SkipReason; reason != `dependency "profile" was skipped` {
+		t.Errorf("logout skip reason = %q", reason)
+	}
+	if ready := graph.ready(); len(ready) != 1 || ready[0] != 1 {
+		t.Errorf("ready() = %v, want [1]", ready)
+	}
+}
+
+func TestDependencyGraph_WaitsForAllDependencies(t *testing.T) {
+	graph := newDependencyGraph([]models.Test{
+		{Name: "a"},
+		{Name: "b"},
+		{Name: "c", DependsOn: []string{"a", "b"}},
+	})
+	graph.ready()
+
+	if released, _ := graph.complete(0, true); len(released) != 0 {
+		t.Errorf("c released before b completed: %v", released)
+	}
+	if released, _ := graph.complete(1, true); len(released) != 1 || released[0] != 2 {
+		t.Errorf("Expected c to be released, got %v", released)
+	}
+}
+
+func TestRunner_Run_DependencyOrder(t *testing.T) {
+	var mu sync.Mutex
+	var order []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		order = append(order, r.URL.Path)
+		mu.Unlock()
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	newTest := func(name, path string, deps ...string) models.Test {
+		return models.Test{
+			Name:       name,
+			Request:    &models.StructuredRequest{Method: "GET", URL: server.URL + path},
+			Assertions: []models.Assertion{{Type: models.AssertionStatus, Value: "200"}},
+			DependsOn:  deps,
+		}
+	}
+
+	suite := &models.TestSuite{
+		Tests: []models.Test{
+			newTest("read", "/read", "create"),
+			newTest("create", "/create"),
+			newTest("broken", "/fail"),
+			newTest("after broken", "/after", "broken"),
+		},
+	}
+
+	runner := NewRunner(5*time.Second, "")
+	result, err := runner.Run(context.Background(), suite)
+	if err != nil {
+		t.Fatalf("Runner.Run failed: %v", err)
+	}
+
+	if got := strings.Join(order, ","); got != "/create,/read,/fail" {
+		t.Errorf("Request order = %s, want /create,/read,/fail", got)
+	}
+	if result.PassedTests != 2 || result.FailedTests != 1 || result.SkippedTests != 1 {
+		t.Errorf("passed=%d failed=%d skipped=%d, want 2/1/1", result.PassedTests, result.FailedTests, result.SkippedTests)
+	}
+	if !result.HasFailures() {
+		t.Error("Suite with a failed test should report failures")
+	}
+}
+
+func TestRunner_RunParallel_Dependencies(t *testing.T) {
+	var mu sync.Mutex
+	finished := make(map[string]time.Time)
+	started := make(map[string]time.Time)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		started[r.URL.Path] = time.Now()
+		mu.Unlock()
+		time.Sleep(30 * time.Millisecond)
+		mu.Lock()
+		finished[r.URL.Path] = time.Now()
+		mu.Unlock()
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	newTest := func(name, path string, deps ...string) models.Test {
+		return models.Test{
+			Name:       name,
+			Request:    &models.StructuredRequest{Method: "GET", URL: server.URL + path},
+			Assertions: []models.Assertion{{Type: models.AssertionStatus, Value: "200"}},
+			DependsOn:  deps,
+		}
+	}
+
+	suite := &models.TestSuite{
+		Tests: []models.Test{
+			newTest("create", "/create"),
+			newTest("read", "/read", "create"),
+			newTest("delete", "/delete", "read"),
+			newTest("health", "/health"),
+			newTest("broken", "/fail"),
+			newTest("needs broken", "/never", "broken"),
+		},
+	}
+
+	runner := NewRunner(5*time.Second, "")
+	result, err := runner.RunParallel(context.Background(), suite, 4, false)
+	if err != nil {
+		t.Fatalf("Runner.RunParallel failed: %v", err)
+	}
+
+	if result.TotalTests != 6 {
+		t.Fatalf("Expected 6 results, got %d", result.TotalTests)
+	}
+	if result.SkippedTests != 1 || result.FailedTests != 1 || result.PassedTests != 4 {
+		t.Errorf("passed=%d failed=%d skipped=%d, want 4/1/1", result.PassedTests, result.FailedTests, result.SkippedTests)
+	}
+
+	for i, res := range result.Results {
+		if res.Test.Name != suite.Tests[i].Name {
+			t.Errorf("Results[%d] = %s, want %s: results should be in file order", i, res.Test.Name, suite.Tests[i].Name)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if _, ok := started["/never"]; ok {
+		t.Error("Dependent of a failed test should not run")
+	}
+	if started["/read"].Before(finished["/create"]) {
+		t.Error("read started before create finished")
+	}
+	if started["/delete"].Before(finished["/read"]) {
+		t.Error("delete started before read finished")
+	}
+}
+
+func TestRunner_SkippedPrerequisite(t *testing.T) {
+	var mu sync.Mutex
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested = append(requested, r.URL.Path)
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	newTest := func(name, path string, deps ...string) models.Test {
+		return models.Test{
+			Name:       name,
+			Request:    &models.StructuredRequest{Method: "GET", URL: server.URL + path},
+			Assertions: []models.Assertion{{Type: models.AssertionStatus, Value: "200"}},
+			DependsOn:  deps,
+		}
+	}
+
+	for _, parallel := range []bool{false, true} {
+		mu.Lock()
+		requested = nil
+		mu.Unlock()
+
+		suite := &models.TestSuite{
+			Tests: []models.Test{
+				newTest("login", "/login"),
+				newTest("profile", "/profile", "login"),
+				newTest("health", "/health"),
+			},
+		}
+		suite.Tests = FilterTests(suite, FilterConfig{SkipTests: "login"})
+
+		runner := NewRunner(5*time.Second, "")
+		var result *models.SuiteResult
+		var err error
+		if parallel {
+			result, err = runner.RunParallel(context.Background(), suite, 4, false)
+		} else {
+			result, err = runner.Run(context.Background(), suite)
+		}
+		if err != nil {
+			t.Fatalf("parallel=%v: run failed: %v", parallel, err)
+		}
+
+		if result.PassedTests != 1 || result.SkippedTests != 1 {
+			t.Errorf("parallel=%v: passed=%d skipped=%d, want 1/1", parallel, result.PassedTests, result.SkippedTests)
+		}
+		for _, res := range result.Results {
+			if res.Test.Name == "profile" && res.SkipReason != `dependency "login" was skipped` {
+				t.Errorf("parallel=%v: profile skip reason = %q", parallel, res.SkipReason)
+			}
+		}
+		mu.Lock()
+		if got := strings.Join(requested, ","); got != "/health" {
+			t.Errorf("parallel=%v: requests = %s, want /health", parallel, got)
+		}
+		mu.Unlock()
+	}
+}
@@ -33,6 +33,8 @@ func FilterTests(suite *models.TestSuite, config FilterConfig) []models.Test {
 		}
 	}
 
+	included := make(map[string]bool)
+
 	for _, test := range suite.Tests {
 		// Skip if test name matches skip pattern
 		if config.SkipTests != "" && test.Name == config.SkipTests {
@@ -54,6 +56,31 @@ func FilterTests(suite *models.TestSuite, config FilterConfig) []models.Test {
 		}
 
 		if include {
+			included[test.Name] = true
+		}
+	}
+
+	// Pull in prerequisites of selected tests so depends_on chains still work
+	// Explicitly skipped tests stay excluded; the runner reports their
+	// dependents as skipped instead of running them without the prerequisite
+	for changed := true; changed; {
+		changed = false
+		for _, test := range suite.Tests {
+			if !included[test.Name] {
+				continue
+			}
+			for _, dep := range test.DependsOn {
+				if !included[dep] && dep != config.SkipTests {
+					included[dep] = true
+					changed = true
+				}
+			}
+		}
+	}
+
+	// Preserve file order
+	for _, test := range suite.Tests {
+		if included[test.Name] && !(config.SkipTests != "" && test.Name == config.SkipTests) {
 			filtered = append(filtered, test)
 		}
 	}
@@ -135,3 +135,24 @@ func TestFilterTests_CombinedPatternAndSkip(t *testing.T) {
 		t.Errorf("Expected 'API Test 1', got '%s'", filtered[0].Name)
 	}
 }
+
+func TestFilterTests_IncludesDependencies(t *testing.T) {
+	suite := &models.TestSuite{
+		Tests: []models.Test{
+			{Name: "Login"},
+			{Name: "Create", DependsOn: []string{"Login"}},
+			{Name: "Read", DependsOn: []string{"Create"}},
+			{Name: "Health"},
+		},
+	}
+
+	filtered := FilterTests(suite, FilterConfig{TestName: "Read"})
+
+	var names []string
+	for _, test := range filtered {
+		names = append(names, test.Name)
+	}
+	if len(names) != 3 || names[0] != "Login" || names[1] != "Create" || names[2] != "Read" {
+		t.Errorf("Filtered = %v, want [Login Create Read]", names)
+	}
+}
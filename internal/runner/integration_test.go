@@ -4,6 +4,9 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -214,3 +217,425 @@ func TestRunner_Integration_Redirect(t *testing.T) {
 		t.Errorf("Expected 1 passed test (catching redirect), got %d", result.PassedTests)
 	}
 }
+
+func TestRunner_Integration_Capture(t *testing.T) {
+	// Create test HTTP server with a login flow
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"token":"tok-123","user":{"id":7}}`))
+		case "/users/7":
+			if r.Header.Get("Authorization") != "Bearer tok-123" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"id":7}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	suite := &models.TestSuite{
+		Variables: map[string]string{"BASE_URL": server.URL},
+		Tests: []models.Test{
+			{
+				Name: "Login",
+				Request: &models.StructuredRequest{
+					Method: "POST",
+					URL:    "${BASE_URL}/login",
+				},
+				Capture: map[string]models.Capture{
+					"token":   {Source: models.CaptureJSONPath, Expression: ".token"},
+					"user_id": {Source: models.CaptureJSONPath, Expression: ".user.id"},
+				},
+				Assertions: []models.Assertion{
+					{Type: models.AssertionStatus, Value: "200"},
+				},
+			},
+			{
+				Name: "Get user",
+				Request: &models.StructuredRequest{
+					Method:  "GET",
+					URL:     "${BASE_URL}/users/${user_id}",
+					Headers: map[string]string{"Authorization": "Bearer ${token}"},
+				},
+				Assertions: []models.Assertion{
+					{Type: models.AssertionStatus, Value: "200"},
+					{Type: models.AssertionJSONPath, Value: ".id == ${user_id}"},
+				},
+			},
+		},
+	}
+
+	runner := NewRunner(5*time.Second, "")
+	result, err := runner.Run(context.Background(), suite)
+	if err != nil {
+		t.Fatalf("Runner.Run failed: %v", err)
+	}
+
+	if result.PassedTests != 2 {
+		for _, r := range result.Results {
+			t.Logf("%s: error=%v failures=%v", r.Test.Name, r.Error, r.Failures)
+		}
+		t.Fatalf("Expected 2 passed tests, got %d", result.PassedTests)
+	}
+
+	if result.Results[0].Captured["token"] != "tok-123" {
+		t.Errorf("Captured token = %q, want tok-123", result.Results[0].Captured["token"])
+	}
+}
+
+func TestRunner_Integration_CaptureInCurl(t *testing.T) {
+	const token = `tok 'a" b`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			_, _ = w.Write([]byte(`{"token":"tok 'a\" b"}`))
+		case "/users/7":
+			if r.Header.Get("Authorization") != "Bearer "+token {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(`{"id":7}`))
+		}
+	}))
+	defer server.Close()
+
+	suite := &models.TestSuite{
+		Variables: map[string]string{"BASE_URL": server.URL},
+		Tests: []models.Test{
+			{
+				Name: "Login",
+				Curl: "curl -X POST ${BASE_URL}/login",
+				Capture: map[string]models.Capture{
+					"token": {Source: models.CaptureJSONPath, Expression: ".token"},
+				},
+				Assertions: []models.Assertion{
+					{Type: models.AssertionStatus, Value: "200"},
+				},
+			},
+			{
+				Name: "Get user",
+				Curl: "curl -H 'Authorization: Bearer ${token}' ${BASE_URL}/users/7",
+				Assertions: []models.Assertion{
+					{Type: models.AssertionStatus, Value: "200"},
+				},
+			},
+		},
+	}
+
+	runner := NewRunner(5*time.Second, "")
+	result, err := runner.Run(context.Background(), suite)
+	if err != nil {
+		t.Fatalf("Runner.Run failed: %v", err)
+	}
+
+	if result.PassedTests != 2 {
+		for _, r := range result.Results {
+			t.Logf("%s: error=%v failures=%v", r.Test.Name, r.Error, r.Failures)
+		}
+		t.Fatalf("Expected 2 passed tests, got %d", result.PassedTests)
+	}
+	if result.Results[0].Captured["token"] != token {
+		t.Errorf("Captured token = %q, want %q", result.Results[0].Captured["token"], token)
+	}
+}
+
+func TestRunner_Integration_BodyMatchesCapture(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/form":
+			_, _ = w.Write([]byte(`<form><input type="hidden" name="csrf" value="c5rf-77"></form>`))
+		case "/submit":
+			if r.Header.Get("X-CSRF-Token") != "c5rf-77" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			_, _ = w.Write([]byte("saved"))
+		}
+	}))
+	defer server.Close()
+
+	suite := &models.TestSuite{
+		Variables: map[string]string{"BASE_URL": server.URL},
+		Tests: []models.Test{
+			{
+				Name:    "Load form",
+				Request: &models.StructuredRequest{Method: "GET", URL: "${BASE_URL}/form"},
+				Assertions: []models.Assertion{
+					{Type: models.AssertionBodyMatches, Value: `name="csrf" value="(?P<csrf>[^"]+)"`},
+				},
+			},
+			{
+				Name: "Submit form",
+				Request: &models.StructuredRequest{
+					Method:  "POST",
+					URL:     "${BASE_URL}/submit",
+					Headers: map[string]string{"X-CSRF-Token": "${csrf}"},
+				},
+				Assertions: []models.Assertion{
+					{Type: models.AssertionStatus, Value: "200"},
+					{Type: models.AssertionBodyNotContains, Value: "error"},
+				},
+			},
+		},
+	}
+
+	runner := NewRunner(5*time.Second, "")
+	result, err := runner.Run(context.Background(), suite)
+	if err != nil {
+		t.Fatalf("Runner.Run failed: %v", err)
+	}
+
+	if result.PassedTests != 2 {
+		for _, r := range result.Results {
+			t.Logf("%s: error=%v failures=%v", r.Test.Name, r.Error, r.Failures)
+		}
+		t.Fatalf("Expected 2 passed tests, got %d", result.PassedTests)
+	}
+	if result.Results[0].Captured["csrf"] != "c5rf-77" {
+		t.Errorf("Captured csrf = %q, want c5rf-77", result.Results[0].Captured["csrf"])
+	}
+}
+
+func TestRunner_Integration_CaptureFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	suite := &models.TestSuite{
+		Tests: []models.Test{
+			{
+				Name: "Missing token",
+				Request: &models.StructuredRequest{
+					Method: "GET",
+					URL:    server.URL,
+				},
+				Capture: map[string]models.Capture{
+					"token": {Source: models.CaptureJSONPath, Expression: ".token"},
+				},
+				Assertions: []models.Assertion{
+					{Type: models.AssertionStatus, Value: "200"},
+				},
+			},
+		},
+	}
+
+	runner := NewRunner(5*time.Second, "")
+	result, err := runner.Run(context.Background(), suite)
+	if err != nil {
+		t.Fatalf("Runner.Run failed: %v", err)
+	}
+
+	if result.FailedTests != 1 {
+		t.Fatalf("Expected capture failure to fail the test, got %d failed", result.FailedTests)
+	}
+	if result.Results[0].Failures[0].Type != models.AssertionCapture {
+		t.Errorf("Failure type = %s, want %s", result.Results[0].Failures[0].Type, models.AssertionCapture)
+	}
+}
+
+func TestRunner_Integration_CookieJar(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "s-1", Path: "/"})
+		case "/account":
+			if _, err := r.Cookie("session"); err != nil {
+				w.WriteHeader(http.StatusUnauthorized)
+			}
+		}
+	}))
+	defer server.Close()
+
+	request := func(path string) *models.StructuredRequest {
+		return &models.StructuredRequest{Method: "GET", URL: server.URL + path}
+	}
+	status := func(code string) []models.Assertion {
+		return []models.Assertion{{Type: models.AssertionStatus, Value: code}}
+	}
+	disabled := false
+
+	suite := &models.TestSuite{
+		Cookies: models.CookiesJar,
+		Setup:   []models.Test{{Name: "Login", Request: request("/login")}},
+		Tests: []models.Test{
+			{Name: "Account", Request: request("/account"), Assertions: status("200")},
+			{Name: "Anonymous", Request: request("/account"), Assertions: status("401"), CookieJar: &disabled},
+		},
+	}
+
+	runner := NewRunner(5*time.Second, "")
+	result, err := runner.Run(context.Background(), suite)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	for _, r := range result.Results {
+		if !r.Success {
+			t.Errorf("%s failed: %v %v", r.Test.Name, r.Failures, r.Error)
+		}
+	}
+
+	// A suite without the jar doesn't keep cookies from the previous run
+	suite.Cookies = ""
+	suite.Tests[0].Assertions = status("401")
+	result, err = runner.Run(context.Background(), suite)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if !result.Results[0].Success {
+		t.Errorf("Account without jar: %v", result.Results[0].Failures)
+	}
+}
+
+func TestRunner_Integration_OpenAPI(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/users/1":
+			_, _ = w.Write([]byte(`{"id": 1, "name": "Ann"}`))
+		case "/users/2":
+			_, _ = w.Write([]byte(`{"id": "2"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+
+	spec := `openapi: 3.1.0
+info: {title: Users, version: "1"}
+paths:
+  /users:
+    get:
+      responses:
+        "200": {description: ok}
+  /users/{id}:
+    get:
+      operationId: getUser
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [id, name]
+                properties:
+                  id: {type: integer}
+                  name: {type: string}
+`
+	specPath := filepath.Join(t.TempDir(), "openapi.yaml")
+	if err := os.WriteFile(specPath, []byte(spec), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	optOut := false
+	suite := &models.TestSuite{
+		Variables: map[string]string{"BASE_URL": server.URL},
+		OpenAPI:   specPath,
+		Tests: []models.Test{
+			{
+				Name:       "Valid user",
+				Request:    &models.StructuredRequest{Method: "GET", URL: "${BASE_URL}/users/1"},
+				Assertions: []models.Assertion{{Type: models.AssertionStatus, Value: "200"}},
+			},
+			{
+				Name:       "Invalid user",
+				Request:    &models.StructuredRequest{Method: "GET", URL: "${BASE_URL}/users/2"},
+				Assertions: []models.Assertion{{Type: models.AssertionStatus, Value: "200"}},
+			},
+			{
+				Name:       "Undocumented",
+				Request:    &models.StructuredRequest{Method: "GET", URL: "${BASE_URL}/health"},
+				OpenAPI:    &optOut,
+				Assertions: []models.Assertion{{Type: models.AssertionStatus, Value: "404"}},
+			},
+		},
+	}
+
+	result, err := NewRunner(5*time.Second, "").Run(context.Background(), suite)
+	if err != nil {
+		t.Fatalf("Runner.Run failed: %v", err)
+	}
+
+	if !result.Results[0].Success || !result.Results[2].Success {
+		t.Errorf("Expected valid and opted-out tests to pass, got %v and %v", result.Results[0].Failures, result.Results[2].Failures)
+	}
+	failures := result.Results[1].Failures
+	if len(failures) != 2 || failures[0].Type != models.AssertionOpenAPI {
+		t.Fatalf("Expected 2 openapi failures, got %v", failures)
+	}
+	if failures[0].Message != "GET /users/{id}: response body: missing property 'name'" {
+		t.Errorf("failure = %q", failures[0].Message)
+	}
+
+	coverage := result.OpenAPICoverage
+	if coverage == nil || len(coverage.Operations) != 2 || coverage.Covered() != 1 {
+		t.Fatalf("Expected 1 of 2 operations covered, got %+v", coverage)
+	}
+	if op := coverage.Operations[1]; op.OperationID != "getUser" || op.Requests != 2 {
+		t.Errorf("getUser coverage = %+v", op)
+	}
+}
+
+func TestRunner_Integration_Snapshots(t *testing.T) {
+	name := "Ann"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": 1, "name": "` + name + `", "token": "` + r.URL.Query().Get("t") + `"}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	suite := &models.TestSuite{
+		Variables: map[string]string{"BASE_URL": server.URL},
+		Path:      filepath.Join(dir, "users.yaml"),
+		Tests: []models.Test{
+			{
+				Name:       "Get user",
+				Request:    &models.StructuredRequest{Method: "GET", URL: "${BASE_URL}/users/1?t=abc"},
+				Assertions: []models.Assertion{{Type: models.AssertionSnapshot, Value: `{"mask": [".token"]}`}},
+				BaseDir:    dir,
+			},
+		},
+	}
+	run := func(update bool) *models.SuiteResult {
+		r := NewRunner(5*time.Second, "")
+		r.SetUpdateSnapshots(update)
+		result, err := r.Run(context.Background(), suite)
+		if err != nil {
+			t.Fatalf("Runner.Run failed: %v", err)
+		}
+		return result
+	}
+
+	// The first run records the snapshot
+	result := run(false)
+	snapshot := filepath.Join(dir, "__snapshots__", "users", "get-user.snap")
+	if result.FailedTests != 0 || result.Snapshots == nil || len(result.Snapshots.Written) != 1 || result.Snapshots.Written[0] != snapshot {
+		t.Fatalf("Expected the snapshot to be recorded, got %+v", result.Snapshots)
+	}
+
+	// Masked values may change; other changes fail until the snapshot is updated
+	suite.Tests[0].Request.URL = "${BASE_URL}/users/1?t=xyz"
+	if result := run(false); result.FailedTests != 0 || result.Snapshots != nil {
+		t.Fatalf("Expected the masked token to be ignored, got %+v", result.Results[0].Failures)
+	}
+	name = "Bob"
+	result = run(false)
+	if result.FailedTests != 1 || !strings.Contains(result.Results[0].Failures[0].Message, `body .name: expected "Ann", got "Bob"`) {
+		t.Fatalf("Expected a name difference, got %+v", result.Results[0].Failures)
+	}
+	if result := run(true); result.FailedTests != 0 || result.Snapshots == nil {
+		t.Fatalf("Expected the snapshot to be updated, got %+v", result.Results[0].Failures)
+	}
+	if result := run(false); result.FailedTests != 0 {
+		t.Errorf("Expected the updated snapshot to match, got %+v", result.Results[0].Failures)
+	}
+}
@@ -2,49 +2,68 @@ package runner
 
 import (
 	"context"
-	"fmt"
-	"os"
+	"sort"
 	"sync"
-	"time"
 
 	"curlex/internal/models"
+	"curlex/internal/parser"
 )
 
+// indexedResult pairs a test result with the test's position in the suite
+type indexedResult struct {
+	index  int
+	result models.TestResult
+}
+
 // RunParallel executes tests in parallel with controlled concurrency
+// Tests are scheduled as a dependency graph: a test is only dispatched once
+// everything in its depends_on list has passed, and is skipped if any failed
 func (r *Runner) RunParallel(ctx context.Context, suite *models.TestSuite, concurrency int, failFast bool) (*models.SuiteResult, error) {
-	startTime := time.Now()
+	return r.runSuite(ctx, suite, func(ctx context.Context, vars *parser.VariableExpander) ([]models.TestResult, error) {
+		return r.runGraph(ctx, suite.Tests, vars, concurrency, failFast), nil
+	})
+}
 
+// runGraph executes tests concurrently as their dependencies complete
+func (r *Runner) runGraph(ctx context.Context, tests []models.Test, vars *parser.VariableExpander, concurrency int, failFast bool) []models.TestResult {
 	// Default concurrency to 10 if not specified
 	if concurrency <= 0 {
 		concurrency = 10
 	}
 
-	// Create channels for work distribution with bounded buffers
-	// Use smaller buffers to avoid excessive memory usage for large test suites
-	bufferSize := min(len(suite.Tests), concurrency*2)
-	jobs := make(chan models.Test, bufferSize)
-	results := make(chan models.TestResult, bufferSize)
+	// Channels are sized to the suite so dispatching and reporting never block
+	jobs := make(chan int, len(tests))
+	results := make(chan indexedResult, len(tests))
 
 	// Context for cancellation (fail-fast)
 	runCtx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	// Worker pool
+	// Worker pool; captured values are shared between workers through vars,
+	// so dependents always see the captures of their prerequisites
 	var wg sync.WaitGroup
 	for i := 0; i < concurrency; i++ {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			for test := range jobs {
-				// Check if context is cancelled (fail-fast)
-				select {
-				case <-runCtx.Done():
-					return
-				default:
+			for index := range jobs {
+				test := tests[index]
+
+				// Check if context is cancelled (fail-fast); queued tests are
+				// only reported when the suite ran out of time
+				if runCtx.Err() != nil {
+					if reason := abortReason(runCtx); reason != "" {
+						results <- indexedResult{index: index, result: models.TestResult{
+							Test:       test,
+							Skipped:    true,
+							SkipReason: reason,
+						}}
+					}
+					continue
 				}
 
 				// Execute the test
-				result, err := r.executor.ExecuteWithRetry(runCtx, test)
+				result, err := r.runTest(runCtx, test, vars)
 				if err != nil {
 					// Create error result
 					result = &models.TestResult{
@@ -54,110 +73,80 @@ func (r *Runner) RunParallel(ctx context.Context, suite *models.TestSuite, concu
 					}
 				}
 
-				// Run assertions if no error occurred
-				if result.Error == nil {
-					failures := r.engine.Validate(result, test.Assertions)
-					result.Failures = failures
-					result.Success = len(failures) == 0
-				}
-
-				// Log request/response if logging is enabled
-				if r.logger != nil {
-					if err := r.logger.LogTest(*result, result.PreparedRequest); err != nil {
-						// Don't fail the test, but warn the user about logging issues
-						fmt.Fprintf(os.Stderr, "Warning: failed to write log file: %v\n", err)
-					}
-				}
-
-				// Send result
-				select {
-				case results <- *result:
-					// Update progress if enabled
-					if r.progress != nil {
-						r.progress.Increment()
-					}
-					// If fail-fast is enabled and test failed, cancel context
-					if failFast && !result.Success {
-						cancel()
-					}
-				case <-runCtx.Done():
-					return
-				}
+				results <- indexedResult{index: index, result: *result}
 			}
 		}()
 	}
 
-	// Send all tests to workers
-	go func() {
-		for _, test := range suite.Tests {
-			select {
-			case jobs <- test:
-			case <-runCtx.Done():
-				close(jobs)
-				return
-			}
+	var collected []indexedResult
+	record := func(index int, result models.TestResult) {
+		collected = append(collected, indexedResult{index: index, result: result})
+		// Update progress if enabled
+		if r.progress != nil {
+			r.progress.Increment()
 		}
-		close(jobs)
-	}()
+	}
 
-	// Collect results
-	var testResults []models.TestResult
-	go func() {
-		wg.Wait()
-		close(results)
-	}()
+	graph := newDependencyGraph(tests)
+	for _, index := range graph.excluded {
+		record(index, graph.skippedResult(index))
+	}
+	inFlight := 0
+	dispatch := func(indexes []int) {
+		for _, index := range indexes {
+			jobs <- index
+			inFlight++
+		}
+	}
+
+	// Start with every test that has no dependencies
+	dispatch(graph.ready())
 
-	// Collect results with context awareness for early exit
-	for {
+	for inFlight > 0 && runCtx.Err() == nil {
 		select {
-		case result, ok := <-results:
-			if !ok {
-				// Channel closed, all results collected
-				goto done
+		case res := <-results:
+			inFlight--
+			record(res.index, res.result)
+
+			// If fail-fast is enabled and test failed, cancel context
+			if failFast && !res.result.Success {
+				cancel()
+				continue
 			}
-			testResults = append(testResults, result)
-		case <-runCtx.Done():
-			// Context cancelled, return partial results
-			// Wait a moment for any in-flight results
-			time.Sleep(100 * time.Millisecond)
-			// Drain remaining results
-			for {
-				select {
-				case result, ok := <-results:
-					if !ok {
-						goto done
-					}
-					testResults = append(testResults, result)
-				default:
-					goto done
-				}
+
+			ready, skipped := graph.complete(res.index, res.result.Success)
+			for _, index := range skipped {
+				record(index, graph.skippedResult(index))
 			}
+			dispatch(ready)
+		case <-runCtx.Done():
 		}
 	}
-done:
-
-	endTime := time.Now()
-
-	// Calculate stats
-	passed := 0
-	failed := 0
-	for _, result := range testResults {
-		if result.Success {
-			passed++
-		} else {
-			failed++
-		}
+
+	// Stop workers and collect results from requests that were in flight when
+	// the run was cancelled
+	close(jobs)
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+	for res := range results {
+		record(res.index, res.result)
 	}
 
-	suiteResult := &models.SuiteResult{
-		Results:     testResults,
-		TotalTests:  len(testResults),
-		PassedTests: passed,
-		FailedTests: failed,
-		TotalTime:   endTime.Sub(startTime),
-		StartTime:   startTime,
-		EndTime:     endTime,
+	// Tests still waiting on dependencies when max_duration was exceeded
+	if reason := abortReason(runCtx); reason != "" {
+		for _, index := range graph.pending() {
+			graph.skipReason[index] = reason
+			record(index, graph.skippedResult(index))
+		}
 	}
 
-	return suiteResult, nil
+	// Report in file order so output is stable between runs
+	sort.Slice(collected, func(i, j int) bool { return collected[i].index < collected[j].index })
+	testResults := make([]models.TestResult, len(collected))
+	for i, res := range collected {
+		testResults[i] = res.result
+	}
+	return testResults
 }
@@ -0,0 +1,165 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"curlex/internal/assertion"
+	"curlex/internal/models"
+	"curlex/internal/openapi"
+	"curlex/internal/parser"
+)
+
+// runSuite wraps the main test run with the suite's setup and teardown phases
+// Setup runs first in order; if it fails the main tests are skipped and the
+// suite is marked as errored. Teardown always runs, even after cancellation.
+func (r *Runner) runSuite(ctx context.Context, suite *models.TestSuite, runTests func(context.Context, *parser.VariableExpander) ([]models.TestResult, error)) (*models.SuiteResult, error) {
+	startTime := time.Now()
+
+	// Variables are shared across phases so setup captures reach tests and teardown
+	vars := parser.NewVariableExpander()
+	vars.SetVariables(suite.Variables)
+
+	// Cookies set by any phase are sent by later requests when the jar is enabled
+	r.executor.UseCookieJar(suite.Cookies == models.CookiesJar)
+
+	// Every exchange is validated against the OpenAPI document when one is set
+	r.contract = nil
+	if suite.OpenAPI != "" {
+		contract, err := openapi.Load(suite.OpenAPI)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load OpenAPI document: %w", err)
+		}
+		r.contract = contract
+	}
+
+	// Snapshots live next to the test file
+	r.snapshots = assertion.NewSnapshotStore(suite.Path, r.updateSnapshots)
+	r.engine.SetSnapshots(r.snapshots)
+
+	// max_duration bounds setup and tests, but never teardown
+	runCtx := ctx
+	if suite.MaxDuration > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeoutCause(ctx, suite.MaxDuration, maxDurationExceeded{limit: suite.MaxDuration})
+		defer cancel()
+	}
+
+	setupResults, setupErr := r.runPhase(runCtx, suite.Setup, vars, true)
+
+	var results []models.TestResult
+	var err error
+	if setupErr == nil {
+		results, err = runTests(runCtx, vars)
+	} else {
+		results = r.skipAll(suite.Tests, "suite setup failed")
+	}
+
+	// Teardown must clean up fixtures even if --fail-fast or SIGINT cancelled the run
+	teardownResults, _ := r.runPhase(context.WithoutCancel(ctx), suite.Teardown, vars, false)
+
+	if err != nil {
+		return nil, err
+	}
+
+	suiteResult := newSuiteResult(results, startTime, time.Now())
+	suiteResult.SetupResults = setupResults
+	suiteResult.TeardownResults = teardownResults
+	if r.contract != nil {
+		suiteResult.OpenAPICoverage = r.contract.Coverage()
+	}
+	if written := r.snapshots.Written(); len(written) > 0 {
+		suiteResult.Snapshots = &models.SnapshotReport{Written: written}
+	}
+	if setupErr != nil {
+		suiteResult.Error = fmt.Errorf("setup failed: %w", setupErr)
+	} else if reason := abortReason(runCtx); reason != "" {
+		suiteResult.Error = errors.New(reason)
+	}
+
+	return suiteResult, nil
+}
+
+// maxDurationExceeded is the cancellation cause used when a suite exceeds max_duration
+type maxDurationExceeded struct {
+	limit time.Duration
+}
+
+// Error returns a human-readable description of the exceeded limit
+func (e maxDurationExceeded) Error() string {
+	return fmt.Sprintf("suite max_duration %s exceeded", e.limit)
+}
+
+// abortReason returns why tests that never ran should be reported as skipped
+// Returns "" when they should simply be dropped (fail-fast, interrupt)
+func abortReason(ctx context.Context) string {
+	var exceeded maxDurationExceeded
+	if errors.As(context.Cause(ctx), &exceeded) {
+		return exceeded.Error()
+	}
+	return ""
+}
+
+// runPhase executes setup or teardown tests sequentially in file order
+// With stopOnFailure, the remaining tests are skipped after the first failure
+// Returns an error describing the first failed test
+func (r *Runner) runPhase(ctx context.Context, tests []models.Test, vars *parser.VariableExpander, stopOnFailure bool) ([]models.TestResult, error) {
+	var results []models.TestResult
+	var firstErr error
+
+	for i, test := range tests {
+		// Remaining setup tests are skipped once max_duration is exceeded
+		if reason := abortReason(ctx); reason != "" {
+			results = append(results, r.skipAll(tests[i:], reason)...)
+			return results, errors.New(reason)
+		}
+
+		result, err := r.runTest(ctx, test, vars)
+		if err != nil {
+			result = &models.TestResult{
+				Test:    test,
+				Success: false,
+				Error:   err,
+			}
+		}
+
+		results = append(results, *result)
+
+		// Update progress if enabled
+		if r.progress != nil {
+			r.progress.Increment()
+		}
+
+		if !result.Success && firstErr == nil {
+			firstErr = fmt.Errorf("test %q failed", test.Name)
+			if stopOnFailure {
+				reason := fmt.Sprintf("setup test %q failed", test.Name)
+				results = append(results, r.skipAll(tests[i+1:], reason)...)
+				break
+			}
+		}
+	}
+
+	return results, firstErr
+}
+
+// skipAll reports every test as skipped with the given reason
+func (r *Runner) skipAll(tests []models.Test, reason string) []models.TestResult {
+	results := make([]models.TestResult, 0, len(tests))
+	for _, test := range tests {
+		results = append(results, models.TestResult{
+			Test:       test,
+			Success:    false,
+			Skipped:    true,
+			SkipReason: reason,
+		})
+
+		// Update progress if enabled
+		if r.progress != nil {
+			r.progress.Increment()
+		}
+	}
+	return results
+}
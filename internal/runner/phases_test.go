@@ -0,0 +1,224 @@
+package runner
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"curlex/internal/models"
+)
+
+// phaseServer records request paths and fails any path starting with /fail
+func phaseServer(t *testing.T) (*httptest.Server, func() []string) {
+	var mu sync.Mutex
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.URL.Path)
+		mu.Unlock()
+		switch r.URL.Path {
+		case "/fixtures":
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":"fx-1"}`))
+		case "/fail":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string{}, paths...)
+	}
+}
+
+func phaseTest(name, url, status string) models.Test {
+	return models.Test{
+		Name:       name,
+		Request:    &models.StructuredRequest{Method: "GET", URL: url},
+		Assertions: []models.Assertion{{Type: models.AssertionStatus, Value: status}},
+	}
+}
+
+func TestRunner_SetupAndTeardown(t *testing.T) {
+	server, paths := phaseServer(t)
+
+	setup := phaseTest("Create fixture", server.URL+"/fixtures", "201")
+	setup.Capture = map[string]models.Capture{
+		"fixture_id": {Source: models.CaptureJSONPath, Expression: ".id"},
+	}
+
+	suite := &models.TestSuite{
+		Setup:    []models.Test{setup},
+		Tests:    []models.Test{phaseTest("Use fixture", server.URL+"/items/${fixture_id}", "200")},
+		Teardown: []models.Test{phaseTest("Delete fixture", server.URL+"/cleanup/${fixture_id}", "200")},
+	}
+
+	for name, run := range map[string]func(*Runner) (*models.SuiteResult, error){
+		"sequential": func(r *Runner) (*models.SuiteResult, error) { return r.Run(context.Background(), suite) },
+		"parallel": func(r *Runner) (*models.SuiteResult, error) {
+			return r.RunParallel(context.Background(), suite, 2, false)
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			result, err := run(NewRunner(5*time.Second, ""))
+			if err != nil {
+				t.Fatalf("run failed: %v", err)
+			}
+
+			if result.Error != nil {
+				t.Errorf("Unexpected suite error: %v", result.Error)
+			}
+			if len(result.SetupResults) != 1 || len(result.TeardownResults) != 1 {
+				t.Fatalf("Expected 1 setup and 1 teardown result, got %d and %d", len(result.SetupResults), len(result.TeardownResults))
+			}
+			if result.TotalTests != 1 || result.PassedTests != 1 {
+				t.Errorf("Expected main test to pass on its own, got total=%d passed=%d", result.TotalTests, result.PassedTests)
+			}
+			if result.HasFailures() {
+				t.Error("Suite should not report failures")
+			}
+		})
+	}
+
+	got := paths()
+	if len(got) < 3 || got[1] != "/items/fx-1" || got[2] != "/cleanup/fx-1" {
+		t.Errorf("Setup capture not shared with tests and teardown, requests = %v", got)
+	}
+}
+
+func TestRunner_SetupFailure(t *testing.T) {
+	server, paths := phaseServer(t)
+
+	suite := &models.TestSuite{
+		Setup: []models.Test{
+			phaseTest("Broken setup", server.URL+"/fail", "200"),
+			phaseTest("Never runs", server.URL+"/setup-two", "200"),
+		},
+		Tests: []models.Test{
+			phaseTest("Test 1", server.URL+"/one", "200"),
+			phaseTest("Test 2", server.URL+"/two", "200"),
+		},
+		Teardown: []models.Test{phaseTest("Cleanup", server.URL+"/cleanup", "200")},
+	}
+
+	result, err := NewRunner(5*time.Second, "").Run(context.Background(), suite)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if result.Error == nil {
+		t.Error("Expected suite error after failed setup")
+	}
+	if !result.HasFailures() {
+		t.Error("Suite with failed setup should report failures")
+	}
+	if result.FailedTests != 0 || result.SkippedTests != 2 {
+		t.Errorf("Main tests should be skipped, not failed: failed=%d skipped=%d", result.FailedTests, result.SkippedTests)
+	}
+	if !result.SetupResults[1].Skipped {
+		t.Error("Remaining setup tests should be skipped after a failure")
+	}
+	if got := paths(); len(got) != 2 || got[0] != "/fail" || got[1] != "/cleanup" {
+		t.Errorf("Requests = %v, want [/fail /cleanup]", got)
+	}
+}
+
+func TestRunner_TeardownAfterCancellation(t *testing.T) {
+	server, paths := phaseServer(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	suite := &models.TestSuite{
+		Tests:    []models.Test{phaseTest("Test 1", server.URL+"/one", "200")},
+		Teardown: []models.Test{phaseTest("Cleanup", server.URL+"/cleanup", "200")},
+	}
+
+	result, err := NewRunner(5*time.Second, "").Run(ctx, suite)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if len(result.TeardownResults) != 1 || !result.TeardownResults[0].Success {
+		t.Fatalf("Teardown should run and pass after cancellation, got %+v", result.TeardownResults)
+	}
+	if got := paths(); len(got) != 1 || got[0] != "/cleanup" {
+		t.Errorf("Requests = %v, want only /cleanup", got)
+	}
+}
+
+func TestRunner_TeardownAfterFailFast(t *testing.T) {
+	server, paths := phaseServer(t)
+
+	suite := &models.TestSuite{
+		Tests: []models.Test{
+			phaseTest("Failing", server.URL+"/fail", "200"),
+		},
+		Teardown: []models.Test{phaseTest("Cleanup", server.URL+"/cleanup", "200")},
+	}
+
+	result, err := NewRunner(5*time.Second, "").RunParallel(context.Background(), suite, 1, true)
+	if err != nil {
+		t.Fatalf("RunParallel failed: %v", err)
+	}
+
+	if len(result.TeardownResults) != 1 || !result.TeardownResults[0].Success {
+		t.Fatalf("Teardown should run after fail-fast, got %+v", result.TeardownResults)
+	}
+	got := paths()
+	if got[len(got)-1] != "/cleanup" {
+		t.Errorf("Teardown should run last, requests = %v", got)
+	}
+}
+
+func TestRunner_MaxDuration(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(60 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	tests := make([]models.Test, 6)
+	for i := range tests {
+		tests[i] = phaseTest("Slow test", server.URL, "200")
+	}
+	suite := &models.TestSuite{
+		MaxDuration: 100 * time.Millisecond,
+		Tests:       tests,
+		Teardown:    []models.Test{phaseTest("Cleanup", server.URL, "200")},
+	}
+
+	for name, run := range map[string]func(*Runner) (*models.SuiteResult, error){
+		"sequential": func(r *Runner) (*models.SuiteResult, error) { return r.Run(context.Background(), suite) },
+		"parallel": func(r *Runner) (*models.SuiteResult, error) {
+			return r.RunParallel(context.Background(), suite, 1, false)
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			result, err := run(NewRunner(5*time.Second, ""))
+			if err != nil {
+				t.Fatalf("run failed: %v", err)
+			}
+
+			if result.Error == nil || !strings.Contains(result.Error.Error(), "max_duration") {
+				t.Errorf("Expected max_duration suite error, got %v", result.Error)
+			}
+			if result.TotalTests != 6 {
+				t.Errorf("Every test should be reported, got %d", result.TotalTests)
+			}
+			if result.SkippedTests < 3 {
+				t.Errorf("Expected remaining tests to be skipped, got %d skipped", result.SkippedTests)
+			}
+			if len(result.TeardownResults) != 1 || !result.TeardownResults[0].Success {
+				t.Errorf("Teardown should still run, got %+v", result.TeardownResults)
+			}
+		})
+	}
+}
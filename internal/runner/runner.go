@@ -3,29 +3,41 @@ package runner
 import (
 	"context"
 	"fmt"
+	"maps"
 	"os"
+	"slices"
+	"sort"
 	"time"
 
 	"curlex/internal/assertion"
+	"curlex/internal/capture"
 	"curlex/internal/executor"
 	"curlex/internal/models"
+	"curlex/internal/openapi"
 	"curlex/internal/output"
+	"curlex/internal/parser"
 )
 
 // Runner executes test suites
 type Runner struct {
-	executor *executor.Executor
-	engine   *assertion.Engine
-	logger   *output.RequestLogger
-	progress *output.Progress
+	executor  *executor.Executor
+	engine    *assertion.Engine
+	extractor *capture.Extractor
+	logger    *output.RequestLogger
+	progress  *output.Progress
+	contract  *openapi.Spec // Suite's OpenAPI document, nil when not configured
+
+	snapshots       *assertion.SnapshotStore // Snapshots of the running suite's test file
+	updateSnapshots bool                     // Rewrite snapshots instead of comparing against them
 }
 
 // NewRunner creates a new test runner
 func NewRunner(timeout time.Duration, logDir string) *Runner {
 	return &Runner{
-		executor: executor.NewExecutor(timeout),
-		engine:   assertion.NewEngine(),
-		logger:   output.NewRequestLogger(logDir),
+		executor:  executor.NewExecutor(timeout),
+		engine:    assertion.NewEngine(),
+		extractor: capture.NewExtractor(),
+		logger:    output.NewRequestLogger(logDir),
 	}
 }
 
@@ -34,31 +46,62 @@ func (r *Runner) SetProgress(progress *output.Progress) {
 	r.progress = progress
 }
 
+// SetProxy sets the proxy for tests that don't configure their own
+func (r *Runner) SetProxy(proxy *models.ProxyConfig) {
+	r.executor.SetProxy(proxy)
+}
+
+// SetResolve sets host resolution overrides applied to every test
+func (r *Runner) SetResolve(resolve map[string]string) {
+	r.executor.SetResolve(resolve)
+}
+
+// SetUpdateSnapshots makes snapshot assertions record the current response
+// instead of comparing against the stored snapshot
+func (r *Runner) SetUpdateSnapshots(update bool) {
+	r.updateSnapshots = update
+}
+
+// SaveCookies writes the suite's cookie jar to path as a Netscape cookie file
+func (r *Runner) SaveCookies(path string) error {
+	return r.executor.WriteCookieJar(path)
+}
+
 // Run executes all tests in the suite sequentially
+// Tests run in file order except where depends_on requires a prerequisite to run first
 func (r *Runner) Run(ctx context.Context, suite *models.TestSuite) (*models.SuiteResult, error) {
-	startTime := time.Now()
+	return r.runSuite(ctx, suite, func(ctx context.Context, vars *parser.VariableExpander) ([]models.TestResult, error) {
+		return r.runSequential(ctx, suite.Tests, vars)
+	})
+}
+
+// runSequential executes tests one at a time, honoring depends_on ordering
+func (r *Runner) runSequential(ctx context.Context, tests []models.Test, vars *parser.VariableExpander) ([]models.TestResult, error) {
 	var results []models.TestResult
 
-	for _, test := range suite.Tests {
-		// Execute the test (with retry if configured)
-		result, err := r.executor.ExecuteWithRetry(ctx, test)
-		if err != nil {
-			return nil, err
+	graph := newDependencyGraph(tests)
+	for _, s := range graph.excluded {
+		results = append(results, graph.skippedResult(s))
+		if r.progress != nil {
+			r.progress.Increment()
 		}
+	}
+	queue := graph.ready()
 
-		// Run assertions if no error occurred
-		if result.Error == nil {
-			failures := r.engine.Validate(result, test.Assertions)
-			result.Failures = failures
-			result.Success = len(failures) == 0
+	for len(queue) > 0 {
+		// Stop scheduling new tests once cancelled and return partial results
+		if ctx.Err() != nil {
+			break
 		}
 
-		// Log request/response if logging is enabled
-		if r.logger != nil {
-			if err := r.logger.LogTest(*result, result.PreparedRequest); err != nil {
-				// Don't fail the test, but warn the user about logging issues
-				fmt.Fprintf(os.Stderr, "Warning: failed to write log file: %v\n", err)
-			}
+		// Always pick the earliest ready test to keep file order where possible
+		sort.Ints(queue)
+		i := queue[0]
+		queue = queue[1:]
+
+		result, err := r.runTest(ctx, tests[i], vars)
+		if err != nil {
+			return nil, err
 		}
 
 		results = append(results, *result)
@@ -67,30 +110,120 @@ func (r *Runner) Run(ctx context.Context, suite *models.TestSuite) (*models.Suit
 		if r.progress != nil {
 			r.progress.Increment()
 		}
+
+		ready, skipped := graph.complete(i, result.Success)
+		for _, s := range skipped {
+			results = append(results, graph.skippedResult(s))
+			if r.progress != nil {
+				r.progress.Increment()
+			}
+		}
+		queue = append(queue, ready...)
 	}
 
-	endTime := time.Now()
+	// Tests that never ran because max_duration was exceeded are reported as skipped
+	if reason := abortReason(ctx); reason != "" {
+		unrun := append(queue, graph.pending()...)
+		sort.Ints(unrun)
+		for _, i := range unrun {
+			graph.skipReason[i] = reason
+			results = append(results, graph.skippedResult(i))
+		}
+	}
+
+	return results, nil
+}
 
-	// Calculate stats
+// newSuiteResult calculates suite statistics from individual results
+func newSuiteResult(results []models.TestResult, startTime, endTime time.Time) *models.SuiteResult {
 	passed := 0
 	failed := 0
+	skipped := 0
 	for _, result := range results {
-		if result.Success {
+		switch {
+		case result.Success:
 			passed++
-		} else {
+		case result.Skipped:
+			skipped++
+		default:
 			failed++
 		}
 	}
 
-	suiteResult := &models.SuiteResult{
-		Results:     results,
-		TotalTests:  len(results),
-		PassedTests: passed,
-		FailedTests: failed,
-		TotalTime:   endTime.Sub(startTime),
-		StartTime:   startTime,
-		EndTime:     endTime,
+	return &models.SuiteResult{
+		Results:      results,
+		TotalTests:   len(results),
+		PassedTests:  passed,
+		FailedTests:  failed,
+		SkippedTests: skipped,
+		TotalTime:    endTime.Sub(startTime),
+		StartTime:    startTime,
+		EndTime:      endTime,
+	}
+}
+
+// runTest expands variables, executes a single test, validates assertions and
+// stores any captured values in vars for subsequent tests
+func (r *Runner) runTest(ctx context.Context, test models.Test, vars *parser.VariableExpander) (*models.TestResult, error) {
+	// Expand variables at execution time so earlier captures are visible
+	expanded, err := vars.ExpandTest(test)
+	if err != nil {
+		return &models.TestResult{
+			Test:    test,
+			Success: false,
+			Error:   fmt.Errorf("variable expansion failed: %w", err),
+		}, nil
+	}
+
+	// Execute the test (with retry if configured)
+	result, err := r.executor.ExecuteWithRetry(ctx, expanded)
+	if err != nil {
+		return result, err
+	}
+
+	// Run assertions and captures if no error occurred
+	if result.Error == nil {
+		// expr assertions can refer to variables, including earlier captures
+		if slices.ContainsFunc(expanded.Assertions, func(a models.Assertion) bool { return a.Type == models.AssertionExpr }) {
+			result.Variables = vars.GetVariables()
+		}
+
+		failures := r.engine.Validate(result, expanded.Assertions)
+
+		// Exchanges are checked against the suite's OpenAPI document unless the test opts out
+		if r.contract != nil && (test.OpenAPI == nil || *test.OpenAPI) {
+			failures = append(failures, r.contract.Validate(result)...)
+		}
+
+		// Named groups in body_matches patterns are captured too; explicit captures win
+		captured := r.extractor.ExtractNamedGroups(result, expanded.Assertions)
+		if len(expanded.Capture) > 0 {
+			explicit, captureFailures := r.extractor.Extract(result, expanded.Capture)
+			if captured == nil {
+				captured = explicit
+			} else {
+				maps.Copy(captured, explicit)
+			}
+			failures = append(failures, captureFailures...)
+		}
+		if captured != nil {
+			for name, value := range captured {
+				vars.Set(name, value)
+			}
+			result.Captured = captured
+		}
+
+		result.Failures = failures
+		result.Success = len(failures) == 0
+	}
+
+	// Log request/response if logging is enabled
+	if r.logger != nil {
+		if err := r.logger.LogTest(*result, result.PreparedRequest); err != nil {
+			// Don't fail the test, but warn the user about logging issues
+			fmt.Fprintf(os.Stderr, "Warning: failed to write log file: %v\n", err)
+		}
 	}
 
-	return suiteResult, nil
+	return result, nil
 }